@@ -0,0 +1,270 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vnFuhung2903/vcs-report-service/dto"
+	"github.com/vnFuhung2903/vcs-report-service/entities"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/logger"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/middlewares"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/retry"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/notifiers"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/services"
+	"go.uber.org/zap"
+)
+
+type deadLetterHandler struct {
+	deadLetterService   services.IDeadLetterService
+	reportService       services.IReportService
+	notificationService notifiers.INotificationService
+	runService          services.IReportRunService
+	jwtMiddleware       middlewares.IJWTMiddleware
+	idempotency         middlewares.IIdempotencyMiddleware
+	logger              logger.ILogger
+}
+
+func NewDeadLetterHandler(deadLetterService services.IDeadLetterService, reportService services.IReportService, notificationService notifiers.INotificationService, runService services.IReportRunService, jwtMiddleware middlewares.IJWTMiddleware, idempotency middlewares.IIdempotencyMiddleware, logger logger.ILogger) *deadLetterHandler {
+	return &deadLetterHandler{deadLetterService, reportService, notificationService, runService, jwtMiddleware, idempotency, logger}
+}
+
+func (h *deadLetterHandler) SetupRoutes(r *gin.Engine) {
+	deadLetterRoutes := r.Group("/report/dead-letters", h.jwtMiddleware.RequireScope("report:admin"))
+	{
+		deadLetterRoutes.GET("", h.List)
+		deadLetterRoutes.GET("/:id", h.Get)
+		deadLetterRoutes.POST("/:id/replay", h.idempotency.Handle(), h.Replay)
+		deadLetterRoutes.DELETE("/:id", h.Delete)
+	}
+}
+
+// List godoc
+// @Summary List dead letters
+// @Description Lists report deliveries that failed after exhausting their retries, one page at a time and oldest first within a page. Pass the returned next_cursor to read the following page; it is 0 after the last one
+// @Tags dead-letter
+// @Produce json
+// @Param cursor query int false "Cursor returned with the previous page, 0 for the first one"
+// @Param limit query int false "Approximate number of dead letters per page (default 100, max 500)"
+// @Success 200 {object} dto.APIResponse{data=dto.DeadLetterPage} "Dead letters retrieved successfully"
+// @Failure 400 {object} dto.APIResponse "Invalid cursor or limit"
+// @Failure 500 {object} dto.APIResponse "Failed to retrieve dead letters"
+// @Security BearerAuth
+// @Router /report/dead-letters [get]
+func (h *deadLetterHandler) List(c *gin.Context) {
+	var req dto.DeadLetterListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	letters, next, err := h.deadLetterService.List(c.Request.Context(), req.Cursor, req.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Code:    "INTERNAL_SERVER_ERROR",
+			Message: "Failed to retrieve dead letters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Code:    "DEAD_LETTERS_RETRIEVED",
+		Message: "Dead letters retrieved successfully",
+		Data:    dto.DeadLetterPage{Letters: letters, NextCursor: next},
+	})
+}
+
+// Get godoc
+// @Summary Get dead letter
+// @Description Returns a single failed report delivery
+// @Tags dead-letter
+// @Produce json
+// @Param id path string true "Dead letter ID"
+// @Success 200 {object} dto.APIResponse{data=entities.DeadLetter} "Dead letter retrieved successfully"
+// @Failure 404 {object} dto.APIResponse "Dead letter not found"
+// @Failure 500 {object} dto.APIResponse "Failed to retrieve dead letter"
+// @Security BearerAuth
+// @Router /report/dead-letters/{id} [get]
+func (h *deadLetterHandler) Get(c *gin.Context) {
+	letter, err := h.deadLetterService.Get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.abortWithError(c, err, "Failed to retrieve dead letter")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Code:    "DEAD_LETTER_RETRIEVED",
+		Message: "Dead letter retrieved successfully",
+		Data:    letter,
+	})
+}
+
+// Replay godoc
+// @Summary Replay dead letter
+// @Description Regenerates the report of a failed delivery and sends it to its target again. The dead letter is removed on success and kept otherwise, with the attempts of the replay added to its own
+// @Tags dead-letter
+// @Produce json
+// @Param id path string true "Dead letter ID"
+// @Param Idempotency-Key header string false "Key under which a retry of this request returns the original response instead of sending again"
+// @Success 200 {object} dto.APIResponse{data=entities.ReportRun} "Dead letter replayed successfully"
+// @Failure 404 {object} dto.APIResponse "Dead letter not found"
+// @Failure 409 {object} map[string]string "Request with this idempotency key is still in progress"
+// @Failure 422 {object} map[string]string "Idempotency key was used for a different request"
+// @Failure 500 {object} dto.APIResponse "Failed to record the run, replay the dead letter or keep it parked"
+// @Security BearerAuth
+// @Router /report/dead-letters/{id}/replay [post]
+func (h *deadLetterHandler) Replay(c *gin.Context) {
+	ctx := c.Request.Context()
+	letter, err := h.deadLetterService.Get(ctx, c.Param("id"))
+	if err != nil {
+		h.abortWithError(c, err, "Failed to retrieve dead letter")
+		return
+	}
+
+	run, err := h.runService.Start(ctx, entities.ReportRun{
+		Trigger:    entities.RunTriggerReplay,
+		Owner:      letter.Owner,
+		Schedule:   letter.Schedule,
+		Channel:    letter.Channel,
+		Recipients: []string{letter.Target},
+		Template:   letter.Template,
+		Filter:     letter.Filter,
+		StartTime:  letter.StartTime,
+		EndTime:    letter.EndTime,
+	}, nil)
+	if err != nil {
+		h.logger.Error("failed to record report run", zap.String("dead_letter", letter.Id), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Code:    "INTERNAL_SERVER_ERROR",
+			Message: "Failed to record report run",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	report, err := h.reportService.GenerateReport(ctx, letter.StartTime, letter.EndTime, letter.Filter)
+	if err != nil {
+		run.Error = err.Error()
+		run = h.finishRun(ctx, run, nil)
+		h.abortReplay(c, letter, run, err)
+		return
+	}
+	report.Template = letter.Template
+
+	if err := h.notificationService.Notify(ctx, letter.Channel, letter.Target, report); err != nil {
+		run.FailedRecipients = []string{letter.Target}
+		run.Error = err.Error()
+		run = h.finishRun(ctx, run, &report)
+		h.abortReplay(c, letter, run, err)
+		return
+	}
+
+	run = h.finishRun(ctx, run, &report)
+	if err := h.deadLetterService.Delete(ctx, letter.Id); err != nil && !errors.Is(err, services.ErrDeadLetterNotFound) {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Code:    "INTERNAL_SERVER_ERROR",
+			Message: "Report replayed but dead letter could not be removed",
+			Error:   err.Error(),
+			Data:    run,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Code:    "DEAD_LETTER_REPLAYED",
+		Message: "Dead letter replayed successfully",
+		Data:    run,
+	})
+}
+
+// Delete godoc
+// @Summary Delete dead letter
+// @Description Discards a failed report delivery without replaying it
+// @Tags dead-letter
+// @Produce json
+// @Param id path string true "Dead letter ID"
+// @Success 200 {object} dto.APIResponse "Dead letter deleted successfully"
+// @Failure 404 {object} dto.APIResponse "Dead letter not found"
+// @Failure 500 {object} dto.APIResponse "Failed to delete dead letter"
+// @Security BearerAuth
+// @Router /report/dead-letters/{id} [delete]
+func (h *deadLetterHandler) Delete(c *gin.Context) {
+	if err := h.deadLetterService.Delete(c.Request.Context(), c.Param("id")); err != nil {
+		h.abortWithError(c, err, "Failed to delete dead letter")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Code:    "DEAD_LETTER_DELETED",
+		Message: "Dead letter deleted successfully",
+	})
+}
+
+// finishRun stores the outcome of run. The replay has already happened, so a
+// failure is only logged and the response still reports it.
+func (h *deadLetterHandler) finishRun(ctx context.Context, run entities.ReportRun, report *dto.ReportResponse) entities.ReportRun {
+	finished, err := h.runService.Finish(context.WithoutCancel(ctx), run, report, nil)
+	if err != nil {
+		h.logger.Warn("failed to record report run", zap.String("run", run.Id), zap.Error(err))
+	}
+	return finished
+}
+
+// abortReplay keeps the letter parked, recording the attempts of the replay
+// and its cause, and reports the failed replay.
+func (h *deadLetterHandler) abortReplay(c *gin.Context, letter entities.DeadLetter, run entities.ReportRun, cause error) {
+	letter.RunId = run.Id
+	letter.Error = cause.Error()
+	if _, err := h.deadLetterService.Park(context.WithoutCancel(c.Request.Context()), letter, retry.Attempts(cause)); err != nil {
+		h.logger.Error("failed to keep dead letter parked", zap.String("dead_letter", letter.Id), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Code:    "INTERNAL_SERVER_ERROR",
+			Message: "Failed to replay dead letter and to record the attempt",
+			Error:   errors.Join(cause, err).Error(),
+			Data:    run,
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, dto.APIResponse{
+		Success: false,
+		Code:    "INTERNAL_SERVER_ERROR",
+		Message: "Failed to replay dead letter",
+		Error:   cause.Error(),
+		Data:    run,
+	})
+}
+
+func (h *deadLetterHandler) abortWithError(c *gin.Context, err error, message string) {
+	if errors.Is(err, services.ErrDeadLetterNotFound) {
+		c.JSON(http.StatusNotFound, dto.APIResponse{
+			Success: false,
+			Code:    "NOT_FOUND",
+			Message: "Dead letter not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, dto.APIResponse{
+		Success: false,
+		Code:    "INTERNAL_SERVER_ERROR",
+		Message: message,
+		Error:   err.Error(),
+	})
+}
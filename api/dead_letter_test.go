@@ -0,0 +1,264 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/vnFuhung2903/vcs-report-service/dto"
+	"github.com/vnFuhung2903/vcs-report-service/entities"
+	"github.com/vnFuhung2903/vcs-report-service/interfaces"
+	"github.com/vnFuhung2903/vcs-report-service/mocks/logger"
+	"github.com/vnFuhung2903/vcs-report-service/mocks/middlewares"
+	"github.com/vnFuhung2903/vcs-report-service/mocks/notifiers"
+	"github.com/vnFuhung2903/vcs-report-service/mocks/services"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/retry"
+	usecases "github.com/vnFuhung2903/vcs-report-service/usecases/services"
+)
+
+type DeadLetterHandlerSuite struct {
+	suite.Suite
+	ctrl                  *gomock.Controller
+	mockDeadLetterService *services.MockIDeadLetterService
+	mockReportService     *services.MockIReportService
+	mockNotification      *notifiers.MockINotificationService
+	mockRunService        *services.MockIReportRunService
+	mockJWTMiddleware     *middlewares.MockIJWTMiddleware
+	mockIdempotency       *middlewares.MockIIdempotencyMiddleware
+	mockLogger            *logger.MockILogger
+	router                *gin.Engine
+	letter                entities.DeadLetter
+}
+
+func (s *DeadLetterHandlerSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.mockDeadLetterService = services.NewMockIDeadLetterService(s.ctrl)
+	s.mockReportService = services.NewMockIReportService(s.ctrl)
+	s.mockNotification = notifiers.NewMockINotificationService(s.ctrl)
+	s.mockRunService = services.NewMockIReportRunService(s.ctrl)
+	s.mockJWTMiddleware = middlewares.NewMockIJWTMiddleware(s.ctrl)
+	s.mockIdempotency = middlewares.NewMockIIdempotencyMiddleware(s.ctrl)
+	s.mockLogger = logger.NewMockILogger(s.ctrl)
+
+	s.mockJWTMiddleware.EXPECT().
+		RequireScope("report:admin").
+		Return(func(c *gin.Context) {
+			c.Next()
+		}).
+		AnyTimes()
+
+	s.mockIdempotency.EXPECT().
+		Handle().
+		Return(func(c *gin.Context) {
+			c.Next()
+		}).
+		AnyTimes()
+
+	gin.SetMode(gin.TestMode)
+	s.router = gin.New()
+	NewDeadLetterHandler(s.mockDeadLetterService, s.mockReportService, s.mockNotification, s.mockRunService, s.mockJWTMiddleware, s.mockIdempotency, s.mockLogger).SetupRoutes(s.router)
+
+	s.letter = entities.DeadLetter{
+		Id:        "letter-1",
+		RunId:     "run-0",
+		Schedule:  "daily",
+		Owner:     "user-1",
+		Channel:   "email",
+		Target:    "ops@example.com",
+		StartTime: time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC),
+		Error:     "connection refused",
+		Attempts:  1,
+	}
+}
+
+func (s *DeadLetterHandlerSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func TestDeadLetterHandlerSuite(t *testing.T) {
+	suite.Run(t, new(DeadLetterHandlerSuite))
+}
+
+func (s *DeadLetterHandlerSuite) serve(method string, path string) (*httptest.ResponseRecorder, dto.APIResponse) {
+	req := httptest.NewRequest(method, path, nil)
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+
+	var response dto.APIResponse
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	return w, response
+}
+
+func (s *DeadLetterHandlerSuite) expectReplayRun(failed bool, generated bool) {
+	s.mockRunService.EXPECT().
+		Start(gomock.Any(), gomock.Any(), nil).
+		DoAndReturn(func(ctx context.Context, run entities.ReportRun, lease *interfaces.Lease) (entities.ReportRun, error) {
+			s.Equal(entities.RunTriggerReplay, run.Trigger)
+			s.Equal("daily", run.Schedule)
+			s.Equal("user-1", run.Owner)
+			s.Equal([]string{"ops@example.com"}, run.Recipients)
+			s.Equal(s.letter.StartTime, run.StartTime)
+			run.Id = "run-1"
+			return run, nil
+		})
+	s.mockRunService.EXPECT().
+		Finish(gomock.Any(), gomock.Any(), gomock.Any(), nil).
+		DoAndReturn(func(ctx context.Context, run entities.ReportRun, report *dto.ReportResponse, lease *interfaces.Lease) (entities.ReportRun, error) {
+			s.Equal(generated, report != nil)
+			if failed {
+				s.NotEmpty(run.Error)
+				run.Status = entities.RunFailed
+			} else {
+				run.Status = entities.RunSucceeded
+			}
+			return run, nil
+		})
+}
+
+func (s *DeadLetterHandlerSuite) TestList() {
+	s.mockDeadLetterService.EXPECT().List(gomock.Any(), uint64(12), 50).Return([]entities.DeadLetter{s.letter}, uint64(34), nil)
+
+	w, response := s.serve(http.MethodGet, "/report/dead-letters?cursor=12&limit=50")
+	s.Equal(http.StatusOK, w.Code)
+	s.Equal("DEAD_LETTERS_RETRIEVED", response.Code)
+	page := response.Data.(map[string]interface{})
+	s.Len(page["letters"], 1)
+	s.Equal(float64(34), page["next_cursor"])
+}
+
+func (s *DeadLetterHandlerSuite) TestListInvalidQuery() {
+	w, response := s.serve(http.MethodGet, "/report/dead-letters?limit=1000")
+	s.Equal(http.StatusBadRequest, w.Code)
+	s.NotEmpty(response.Error)
+
+	w, _ = s.serve(http.MethodGet, "/report/dead-letters?cursor=-1")
+	s.Equal(http.StatusBadRequest, w.Code)
+}
+
+func (s *DeadLetterHandlerSuite) TestListError() {
+	s.mockDeadLetterService.EXPECT().List(gomock.Any(), uint64(0), 0).Return(nil, uint64(0), errors.New("redis connection failed"))
+
+	w, response := s.serve(http.MethodGet, "/report/dead-letters")
+	s.Equal(http.StatusInternalServerError, w.Code)
+	s.Equal("Failed to retrieve dead letters", response.Message)
+}
+
+func (s *DeadLetterHandlerSuite) TestGet() {
+	s.mockDeadLetterService.EXPECT().Get(gomock.Any(), "letter-1").Return(s.letter, nil)
+
+	w, response := s.serve(http.MethodGet, "/report/dead-letters/letter-1")
+	s.Equal(http.StatusOK, w.Code)
+	s.Equal("DEAD_LETTER_RETRIEVED", response.Code)
+}
+
+func (s *DeadLetterHandlerSuite) TestGetNotFound() {
+	s.mockDeadLetterService.EXPECT().Get(gomock.Any(), "missing").Return(entities.DeadLetter{}, usecases.ErrDeadLetterNotFound)
+
+	w, response := s.serve(http.MethodGet, "/report/dead-letters/missing")
+	s.Equal(http.StatusNotFound, w.Code)
+	s.Equal("NOT_FOUND", response.Code)
+}
+
+func (s *DeadLetterHandlerSuite) TestReplay() {
+	report := dto.ReportResponse{ContainerCount: 2}
+	s.mockDeadLetterService.EXPECT().Get(gomock.Any(), "letter-1").Return(s.letter, nil)
+	s.expectReplayRun(false, true)
+	s.mockReportService.EXPECT().GenerateReport(gomock.Any(), s.letter.StartTime, s.letter.EndTime, entities.ContainerFilter{}).Return(report, nil)
+	s.mockNotification.EXPECT().Notify(gomock.Any(), "email", "ops@example.com", report).Return(nil)
+	s.mockDeadLetterService.EXPECT().Delete(gomock.Any(), "letter-1").Return(nil)
+
+	w, response := s.serve(http.MethodPost, "/report/dead-letters/letter-1/replay")
+	s.Equal(http.StatusOK, w.Code)
+	s.True(response.Success)
+	s.Equal("DEAD_LETTER_REPLAYED", response.Code)
+}
+
+func (s *DeadLetterHandlerSuite) TestReplayNotFound() {
+	s.mockDeadLetterService.EXPECT().Get(gomock.Any(), "missing").Return(entities.DeadLetter{}, usecases.ErrDeadLetterNotFound)
+
+	w, _ := s.serve(http.MethodPost, "/report/dead-letters/missing/replay")
+	s.Equal(http.StatusNotFound, w.Code)
+}
+
+func (s *DeadLetterHandlerSuite) TestReplayGenerateReportError() {
+	s.mockDeadLetterService.EXPECT().Get(gomock.Any(), "letter-1").Return(s.letter, nil)
+	s.expectReplayRun(true, false)
+	s.mockReportService.EXPECT().GenerateReport(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(dto.ReportResponse{}, errors.New("elasticsearch unavailable"))
+	s.mockDeadLetterService.EXPECT().
+		Park(gomock.Any(), gomock.Any(), 1).
+		DoAndReturn(func(ctx context.Context, letter entities.DeadLetter, attempts int) (entities.DeadLetter, error) {
+			s.Equal("letter-1", letter.Id)
+			s.Equal("run-1", letter.RunId)
+			s.Equal(1, letter.Attempts)
+			s.Equal("elasticsearch unavailable", letter.Error)
+			return letter, nil
+		})
+
+	w, response := s.serve(http.MethodPost, "/report/dead-letters/letter-1/replay")
+	s.Equal(http.StatusInternalServerError, w.Code)
+	s.Equal("Failed to replay dead letter", response.Message)
+}
+
+func (s *DeadLetterHandlerSuite) TestReplayNotifyError() {
+	s.mockDeadLetterService.EXPECT().Get(gomock.Any(), "letter-1").Return(s.letter, nil)
+	s.expectReplayRun(true, true)
+	s.mockReportService.EXPECT().GenerateReport(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(dto.ReportResponse{}, nil)
+	exhausted := retry.Policy{Attempts: 3}.Do(context.Background(), func(ctx context.Context) error {
+		return errors.New("smtp unavailable")
+	})
+	s.mockNotification.EXPECT().Notify(gomock.Any(), "email", "ops@example.com", gomock.Any()).Return(exhausted)
+	s.mockDeadLetterService.EXPECT().Park(gomock.Any(), gomock.Any(), 3).Return(s.letter, nil)
+
+	w, response := s.serve(http.MethodPost, "/report/dead-letters/letter-1/replay")
+	s.Equal(http.StatusInternalServerError, w.Code)
+	s.Equal("smtp unavailable", response.Error)
+}
+
+func (s *DeadLetterHandlerSuite) TestReplayParkError() {
+	s.mockDeadLetterService.EXPECT().Get(gomock.Any(), "letter-1").Return(s.letter, nil)
+	s.expectReplayRun(true, true)
+	s.mockReportService.EXPECT().GenerateReport(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(dto.ReportResponse{}, nil)
+	s.mockNotification.EXPECT().Notify(gomock.Any(), "email", "ops@example.com", gomock.Any()).Return(errors.New("smtp unavailable"))
+	s.mockDeadLetterService.EXPECT().Park(gomock.Any(), gomock.Any(), 1).Return(entities.DeadLetter{}, errors.New("redis connection failed"))
+	s.mockLogger.EXPECT().Error("failed to keep dead letter parked", gomock.Any(), gomock.Any()).Times(1)
+
+	w, response := s.serve(http.MethodPost, "/report/dead-letters/letter-1/replay")
+	s.Equal(http.StatusInternalServerError, w.Code)
+	s.Equal("Failed to replay dead letter and to record the attempt", response.Message)
+	s.Contains(response.Error, "smtp unavailable")
+	s.Contains(response.Error, "redis connection failed")
+}
+
+func (s *DeadLetterHandlerSuite) TestReplayRunStartError() {
+	s.mockDeadLetterService.EXPECT().Get(gomock.Any(), "letter-1").Return(s.letter, nil)
+	s.mockRunService.EXPECT().Start(gomock.Any(), gomock.Any(), nil).Return(entities.ReportRun{}, errors.New("redis connection failed"))
+	s.mockLogger.EXPECT().Error("failed to record report run", gomock.Any(), gomock.Any()).Times(1)
+
+	w, response := s.serve(http.MethodPost, "/report/dead-letters/letter-1/replay")
+	s.Equal(http.StatusInternalServerError, w.Code)
+	s.Equal("Failed to record report run", response.Message)
+}
+
+func (s *DeadLetterHandlerSuite) TestDelete() {
+	s.mockDeadLetterService.EXPECT().Delete(gomock.Any(), "letter-1").Return(nil)
+
+	w, response := s.serve(http.MethodDelete, "/report/dead-letters/letter-1")
+	s.Equal(http.StatusOK, w.Code)
+	s.Equal("DEAD_LETTER_DELETED", response.Code)
+}
+
+func (s *DeadLetterHandlerSuite) TestDeleteNotFound() {
+	s.mockDeadLetterService.EXPECT().Delete(gomock.Any(), "missing").Return(usecases.ErrDeadLetterNotFound)
+
+	w, _ := s.serve(http.MethodDelete, "/report/dead-letters/missing")
+	s.Equal(http.StatusNotFound, w.Code)
+}
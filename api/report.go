@@ -1,46 +1,74 @@
 package api
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/vnFuhung2903/vcs-report-service/dto"
+	"github.com/vnFuhung2903/vcs-report-service/entities"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/env"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/logger"
 	"github.com/vnFuhung2903/vcs-report-service/pkg/middlewares"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/timerange"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/exporters"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/i18n"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/notifiers"
 	"github.com/vnFuhung2903/vcs-report-service/usecases/services"
+	"go.uber.org/zap"
 )
 
 type reportHandler struct {
-	reportService services.IReportService
-	jwtMiddleware middlewares.IJWTMiddleware
+	reportService       services.IReportService
+	notificationService notifiers.INotificationService
+	runService          services.IReportRunService
+	jwtMiddleware       middlewares.IJWTMiddleware
+	idempotency         middlewares.IIdempotencyMiddleware
+	targetHosts         env.TargetHostsEnv
+	logger              logger.ILogger
 }
 
-func NewReportHandler(reportService services.IReportService, jwtMiddleware middlewares.IJWTMiddleware) *reportHandler {
-	return &reportHandler{reportService, jwtMiddleware}
+func NewReportHandler(reportService services.IReportService, notificationService notifiers.INotificationService, runService services.IReportRunService, jwtMiddleware middlewares.IJWTMiddleware, idempotency middlewares.IIdempotencyMiddleware, reportEnv env.ReportEnv, logger logger.ILogger) *reportHandler {
+	return &reportHandler{reportService, notificationService, runService, jwtMiddleware, idempotency, reportEnv.TargetHosts, logger}
 }
 
 func (h *reportHandler) SetupRoutes(r *gin.Engine) {
-	reportRoutes := r.Group("/report", h.jwtMiddleware.RequireScope("report:mail"))
+	readRoutes := r.Group("/report", h.jwtMiddleware.RequireScope("report:read"))
 	{
-		reportRoutes.GET("/mail", h.SendEmail)
+		readRoutes.GET("", h.GetReport)
+	}
+
+	mailRoutes := r.Group("/report", h.jwtMiddleware.RequireScope("report:mail"))
+	{
+		mailRoutes.GET("/mail", h.idempotency.Handle(), h.SendEmail)
+		mailRoutes.GET("/notify", h.idempotency.Handle(), h.Notify)
 	}
 }
 
-// SendEmail godoc
-// @Summary Send container status report via email
-// @Description Generates a container uptime/downtime report and sends it to the provided email address
+// GetReport godoc
+// @Summary Get container status report
+// @Description Calculates the container uptime/downtime report for the given time range and returns it, as JSON or as a CSV download of the per-container figures when format=csv or the Accept header asks for text/csv
 // @Tags report
 // @Produce json
-// @Param email query string true "Recipient email address"
-// @Param start_time query string true "Start date (e.g. 2006-01-02)"
-// @Param end_time query string false "End date (defaults to current time)"
-// @Success 200 {object} dto.APIResponse "Report emailed successfully"
-// @Failure 400 {object} dto.APIResponse "Invalid input or time range"
-// @Failure 500 {object} dto.APIResponse "Failed to retrieve data or send email"
+// @Produce text/csv
+// @Param range query string false "Named range (today, yesterday, week_to_date, last_week, month_to_date, last_month) or relative expression (e.g. now-24h, now-7d/d..now/d), instead of start_time and end_time"
+// @Param start_time query string false "Start date (e.g. 2006-01-02), RFC 3339 timestamp or relative expression (e.g. now-7d/d), inclusive; required without range"
+// @Param end_time query string false "End date, included whole, RFC 3339 timestamp or relative expression, exclusive (defaults to current time)"
+// @Param tz query string false "IANA timezone the day boundaries of dates are taken in (defaults to UTC)"
+// @Param container_id query []string false "Container ID to report on, repeated or comma separated" collectionFormat(multi)
+// @Param container_pattern query string false "Container ID prefix, or glob when it contains wildcards (e.g. api-*-eu)"
+// @Param labels query string false "Label selector the containers must match (e.g. team=payments,env=prod)"
+// @Param format query string false "Response format (defaults to the Accept header, then json)" Enums(json, csv)
+// @Success 200 {object} dto.APIResponse{data=dto.ReportResponse} "Report retrieved successfully"
+// @Failure 400 {object} dto.APIResponse "Invalid input, time range or container filter"
+// @Failure 500 {object} dto.APIResponse "Failed to retrieve data"
 // @Security BearerAuth
-// @Router /report/mail [get]
-func (h *reportHandler) SendEmail(c *gin.Context) {
-	var req dto.ReportRequest
+// @Router /report [get]
+func (h *reportHandler) GetReport(c *gin.Context) {
+	var req dto.ReportQueryRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
 		c.JSON(http.StatusBadRequest, dto.APIResponse{
 			Success: false,
@@ -51,80 +79,416 @@ func (h *reportHandler) SendEmail(c *gin.Context) {
 		return
 	}
 
-	startTime, err := time.Parse(time.RFC3339, req.StartTime+"T00:00:00Z")
+	startTime, endTime, ok := parseTimeRange(c, req.Range, req.StartTime, req.EndTime, req.Timezone)
+	if !ok {
+		return
+	}
+	filter, ok := parseContainerFilter(c, req.ContainerIds, req.ContainerPattern, req.Labels)
+	if !ok {
+		return
+	}
+
+	report, err := h.reportService.GenerateReport(c.Request.Context(), startTime, endTime, filter)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.APIResponse{
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
 			Success: false,
-			Code:    "BAD_REQUEST",
-			Message: "Invalid start time format",
+			Code:    "INTERNAL_SERVER_ERROR",
+			Message: "Failed to generate report",
 			Error:   err.Error(),
 		})
 		return
 	}
 
-	var endTime time.Time
-	if req.EndTime == "" {
-		endTime = time.Now()
-	} else {
-		endTime, err = time.Parse(time.RFC3339, req.EndTime+"T23:59:59Z")
+	if wantsCSV(c, req.Format) {
+		// The timezone was already validated with the range; it names the
+		// file after the dates the caller asked for.
+		localizer, _ := i18n.NewLocalizer(i18n.DefaultLocale, req.Timezone)
+		exporter := exporters.NewCSVExporter()
+		data, err := exporter.Export(report)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, dto.APIResponse{
+			c.JSON(http.StatusInternalServerError, dto.APIResponse{
 				Success: false,
-				Code:    "BAD_REQUEST",
-				Message: "Invalid start time format",
+				Code:    "INTERNAL_SERVER_ERROR",
+				Message: "Failed to export report",
 				Error:   err.Error(),
 			})
 			return
 		}
+
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", exporters.FileName(exporter, report, localizer)))
+		c.Data(http.StatusOK, exporter.ContentType(), data)
+		return
 	}
 
-	if startTime.After(endTime) {
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Code:    "REPORT_RETRIEVED",
+		Message: "Report retrieved successfully",
+		Data:    report,
+	})
+}
+
+// SendEmail godoc
+// @Summary Send container status report via email
+// @Description Generates a container uptime/downtime report and sends it to the provided email address
+// @Tags report
+// @Produce json
+// @Param email query string true "Recipient email address"
+// @Param range query string false "Named range (today, yesterday, week_to_date, last_week, month_to_date, last_month) or relative expression (e.g. now-24h, now-7d/d..now/d), instead of start_time and end_time"
+// @Param start_time query string false "Start date (e.g. 2006-01-02), RFC 3339 timestamp or relative expression (e.g. now-7d/d), inclusive; required without range"
+// @Param end_time query string false "End date, included whole, RFC 3339 timestamp or relative expression, exclusive (defaults to current time)"
+// @Param tz query string false "IANA timezone the day boundaries of dates are taken in (defaults to UTC)"
+// @Param container_id query []string false "Container ID to report on, repeated or comma separated" collectionFormat(multi)
+// @Param container_pattern query string false "Container ID prefix, or glob when it contains wildcards (e.g. api-*-eu)"
+// @Param labels query string false "Label selector the containers must match (e.g. team=payments,env=prod)"
+// @Param Idempotency-Key header string false "Key under which a retry of this request returns the original response instead of sending again"
+// @Success 200 {object} dto.APIResponse{data=entities.ReportRun} "Report emailed successfully"
+// @Failure 400 {object} dto.APIResponse "Invalid input, time range or container filter"
+// @Failure 409 {object} map[string]string "Request with this idempotency key is still in progress"
+// @Failure 422 {object} map[string]string "Idempotency key was used for a different request"
+// @Failure 500 {object} dto.APIResponse "Failed to record the run, retrieve data or send email"
+// @Security BearerAuth
+// @Router /report/mail [get]
+func (h *reportHandler) SendEmail(c *gin.Context) {
+	var req dto.ReportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
 		c.JSON(http.StatusBadRequest, dto.APIResponse{
 			Success: false,
 			Code:    "BAD_REQUEST",
 			Message: "Invalid request data",
-			Error:   "start time cannot be after end time",
+			Error:   err.Error(),
 		})
 		return
 	}
 
-	statusList, err := h.reportService.GetEsStatus(c.Request.Context(), 10000, startTime, endTime, dto.Asc)
+	startTime, endTime, ok := parseTimeRange(c, req.Range, req.StartTime, req.EndTime, req.Timezone)
+	if !ok {
+		return
+	}
+	filter, ok := parseContainerFilter(c, req.ContainerIds, req.ContainerPattern, req.Labels)
+	if !ok {
+		return
+	}
+
+	run, ok := h.startRun(c, entities.ReportRun{
+		Trigger:    entities.RunTriggerAPI,
+		Owner:      c.GetString("userId"),
+		Channel:    notifiers.ChannelEmail,
+		Recipients: []string{req.Email},
+		Filter:     filter,
+		StartTime:  startTime,
+		EndTime:    endTime,
+	})
+	if !ok {
+		return
+	}
+
+	report, err := h.reportService.GenerateReport(c.Request.Context(), startTime, endTime, filter)
 	if err != nil {
+		run.Error = err.Error()
+		h.finishRun(c, run, nil)
 		c.JSON(http.StatusInternalServerError, dto.APIResponse{
 			Success: false,
 			Code:    "INTERNAL_SERVER_ERROR",
-			Message: "Failed to retrieve healthcheck status",
+			Message: "Failed to generate report",
 			Error:   err.Error(),
 		})
 		return
 	}
 
-	overlapStatusList, err := h.reportService.GetEsStatus(c.Request.Context(), 1, endTime, time.Now(), dto.Asc)
-	if err != nil {
+	if err := h.reportService.SendEmail(c.Request.Context(), req.Email, report); err != nil {
+		run.FailedRecipients = []string{req.Email}
+		run.Error = err.Error()
+		h.finishRun(c, run, &report)
 		c.JSON(http.StatusInternalServerError, dto.APIResponse{
 			Success: false,
 			Code:    "INTERNAL_SERVER_ERROR",
-			Message: "Failed to retrieve overlap healthcheck status",
+			Message: "Failed to send email",
 			Error:   err.Error(),
 		})
 		return
 	}
 
-	onCount, offCount, totalUptime := h.reportService.CalculateReportStatistic(statusList, overlapStatusList, startTime, endTime)
+	run = h.finishRun(c, run, &report)
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Code:    "REPORT_EMAILED",
+		Message: "Report emailed successfully",
+		Data:    run,
+	})
+}
 
-	if err := h.reportService.SendEmail(c.Request.Context(), req.Email, onCount+offCount, onCount, offCount, totalUptime, startTime, endTime); err != nil {
+// Notify godoc
+// @Summary Send container status report to a notification channel
+// @Description Generates a container uptime/downtime report and delivers it through the selected channel (email address or webhook URL as target)
+// @Tags report
+// @Produce json
+// @Param channel query string true "Notification channel" Enums(email, slack, teams, webhook)
+// @Param target query string true "Recipient email address, or https webhook URL on a host allowed for the channel"
+// @Param range query string false "Named range (today, yesterday, week_to_date, last_week, month_to_date, last_month) or relative expression (e.g. now-24h, now-7d/d..now/d), instead of start_time and end_time"
+// @Param start_time query string false "Start date (e.g. 2006-01-02), RFC 3339 timestamp or relative expression (e.g. now-7d/d), inclusive; required without range"
+// @Param end_time query string false "End date, included whole, RFC 3339 timestamp or relative expression, exclusive (defaults to current time)"
+// @Param tz query string false "IANA timezone the day boundaries of dates are taken in (defaults to UTC)"
+// @Param container_id query []string false "Container ID to report on, repeated or comma separated" collectionFormat(multi)
+// @Param container_pattern query string false "Container ID prefix, or glob when it contains wildcards (e.g. api-*-eu)"
+// @Param labels query string false "Label selector the containers must match (e.g. team=payments,env=prod)"
+// @Param Idempotency-Key header string false "Key under which a retry of this request returns the original response instead of sending again"
+// @Success 200 {object} dto.APIResponse{data=entities.ReportRun} "Report sent successfully"
+// @Failure 400 {object} dto.APIResponse "Invalid input, target, time range or container filter"
+// @Failure 409 {object} map[string]string "Request with this idempotency key is still in progress"
+// @Failure 422 {object} map[string]string "Idempotency key was used for a different request"
+// @Failure 500 {object} dto.APIResponse "Failed to record the run, retrieve data or send notification"
+// @Security BearerAuth
+// @Router /report/notify [get]
+func (h *reportHandler) Notify(c *gin.Context) {
+	var req dto.NotifyRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if !validateTarget(c, h.targetHosts, req.Channel, req.Target) {
+		return
+	}
+
+	startTime, endTime, ok := parseTimeRange(c, req.Range, req.StartTime, req.EndTime, req.Timezone)
+	if !ok {
+		return
+	}
+	filter, ok := parseContainerFilter(c, req.ContainerIds, req.ContainerPattern, req.Labels)
+	if !ok {
+		return
+	}
+
+	run, ok := h.startRun(c, entities.ReportRun{
+		Trigger:    entities.RunTriggerAPI,
+		Owner:      c.GetString("userId"),
+		Channel:    req.Channel,
+		Recipients: []string{req.Target},
+		Filter:     filter,
+		StartTime:  startTime,
+		EndTime:    endTime,
+	})
+	if !ok {
+		return
+	}
+
+	report, err := h.reportService.GenerateReport(c.Request.Context(), startTime, endTime, filter)
+	if err != nil {
+		run.Error = err.Error()
+		h.finishRun(c, run, nil)
 		c.JSON(http.StatusInternalServerError, dto.APIResponse{
 			Success: false,
 			Code:    "INTERNAL_SERVER_ERROR",
-			Message: "Failed to send email",
+			Message: "Failed to generate report",
 			Error:   err.Error(),
 		})
 		return
 	}
 
+	if err := h.notificationService.Notify(c.Request.Context(), req.Channel, req.Target, report); err != nil {
+		run.FailedRecipients = []string{req.Target}
+		run.Error = err.Error()
+		h.finishRun(c, run, &report)
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Code:    "INTERNAL_SERVER_ERROR",
+			Message: "Failed to send notification",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	run = h.finishRun(c, run, &report)
 	c.JSON(http.StatusOK, dto.APIResponse{
 		Success: true,
-		Code:    "REPORT_EMAILED",
-		Message: "Report emailed successfully",
+		Code:    "REPORT_SENT",
+		Message: "Report sent successfully",
+		Data:    run,
 	})
 }
+
+// validateTarget rejects targets the channel may not deliver to, such as
+// webhook URLs outside the configured hosts.
+func validateTarget(c *gin.Context, targetHosts env.TargetHostsEnv, channel string, target string) bool {
+	if err := env.ValidateReportTarget(targetHosts, channel, target); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid target",
+			Error:   err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+// wantsCSV reports whether the report should be rendered as CSV: an explicit
+// format query parameter wins over the Accept header, and JSON is preferred
+// when both are acceptable.
+func wantsCSV(c *gin.Context, format string) bool {
+	if format != "" {
+		return format == exporters.FormatCSV
+	}
+	return c.NegotiateFormat(gin.MIMEJSON, exporters.MIMECSV) == exporters.MIMECSV
+}
+
+// parseTimeRange resolves the [startTime, endTime) window of a request, either
+// from a named or relative range or from explicit bounds. Bounds accept an
+// RFC 3339 timestamp, a date or date math relative to now; dates and relative
+// expressions are taken in timezone (UTC when empty) and an end date includes
+// that whole day. A missing end means now.
+func parseTimeRange(c *gin.Context, rangeExpr string, start string, end string, timezone string) (time.Time, time.Time, bool) {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid timezone",
+			Error:   err.Error(),
+		})
+		return time.Time{}, time.Time{}, false
+	}
+	now := time.Now().In(location)
+
+	if rangeExpr != "" {
+		if start != "" || end != "" {
+			c.JSON(http.StatusBadRequest, dto.APIResponse{
+				Success: false,
+				Code:    "BAD_REQUEST",
+				Message: "Invalid request data",
+				Error:   "range cannot be combined with start_time or end_time",
+			})
+			return time.Time{}, time.Time{}, false
+		}
+
+		startTime, endTime, err := timerange.Resolve(rangeExpr, now, location)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.APIResponse{
+				Success: false,
+				Code:    "BAD_REQUEST",
+				Message: "Invalid time range",
+				Error:   err.Error(),
+			})
+			return time.Time{}, time.Time{}, false
+		}
+		return startTime, endTime, true
+	}
+
+	startTime, err := timerange.ParseBound(start, now, location, false)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid start time format",
+			Error:   err.Error(),
+		})
+		return time.Time{}, time.Time{}, false
+	}
+
+	endTime := now
+	if end != "" {
+		endTime, err = timerange.ParseBound(end, now, location, true)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.APIResponse{
+				Success: false,
+				Code:    "BAD_REQUEST",
+				Message: "Invalid end time format",
+				Error:   err.Error(),
+			})
+			return time.Time{}, time.Time{}, false
+		}
+	}
+
+	if !startTime.Before(endTime) {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request data",
+			Error:   "start time must be before end time",
+		})
+		return time.Time{}, time.Time{}, false
+	}
+
+	return startTime, endTime, true
+}
+
+// parseContainerFilter builds the filter that scopes a request to part of the
+// fleet from container IDs, repeated or comma separated, an ID prefix or glob
+// and a label selector such as "team=payments,env=prod".
+func parseContainerFilter(c *gin.Context, ids []string, pattern string, selector string) (entities.ContainerFilter, bool) {
+	filter := entities.ContainerFilter{Pattern: pattern}
+	for _, id := range ids {
+		for _, item := range strings.Split(id, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				filter.Ids = append(filter.Ids, item)
+			}
+		}
+	}
+
+	for _, requirement := range strings.Split(selector, ",") {
+		if requirement = strings.TrimSpace(requirement); requirement == "" {
+			continue
+		}
+		key, value, found := strings.Cut(requirement, "=")
+		if !found {
+			c.JSON(http.StatusBadRequest, dto.APIResponse{
+				Success: false,
+				Code:    "BAD_REQUEST",
+				Message: "Invalid container filter",
+				Error:   fmt.Sprintf("label selector %q is not of the form key=value", requirement),
+			})
+			return entities.ContainerFilter{}, false
+		}
+		if filter.Labels == nil {
+			filter.Labels = make(map[string]string)
+		}
+		filter.Labels[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	return filter, validateContainerFilter(c, filter)
+}
+
+func validateContainerFilter(c *gin.Context, filter entities.ContainerFilter) bool {
+	if err := filter.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid container filter",
+			Error:   err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+// startRun records run before the report is sent and answers with an error when
+// it cannot, so that no delivery goes out without a trace in the run history.
+func (h *reportHandler) startRun(c *gin.Context, run entities.ReportRun) (entities.ReportRun, bool) {
+	run, err := h.runService.Start(c.Request.Context(), run, nil)
+	if err != nil {
+		h.logger.Error("failed to record report run", zap.String("channel", run.Channel), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Code:    "INTERNAL_SERVER_ERROR",
+			Message: "Failed to record report run",
+			Error:   err.Error(),
+		})
+		return run, false
+	}
+	return run, true
+}
+
+// finishRun stores the outcome of run. The delivery has already happened, so a
+// failure is only logged and the response still reports it.
+func (h *reportHandler) finishRun(c *gin.Context, run entities.ReportRun, report *dto.ReportResponse) entities.ReportRun {
+	finished, err := h.runService.Finish(context.WithoutCancel(c.Request.Context()), run, report, nil)
+	if err != nil {
+		h.logger.Warn("failed to record report run", zap.String("run", run.Id), zap.Error(err))
+	}
+	return finished
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vnFuhung2903/vcs-report-service/dto"
+	"github.com/vnFuhung2903/vcs-report-service/entities"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/env"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/middlewares"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/services"
+)
+
+type reportJobHandler struct {
+	jobService    services.IReportJobService
+	jwtMiddleware middlewares.IJWTMiddleware
+	idempotency   middlewares.IIdempotencyMiddleware
+	targetHosts   env.TargetHostsEnv
+}
+
+func NewReportJobHandler(jobService services.IReportJobService, jwtMiddleware middlewares.IJWTMiddleware, idempotency middlewares.IIdempotencyMiddleware, reportEnv env.ReportEnv) *reportJobHandler {
+	return &reportJobHandler{jobService, jwtMiddleware, idempotency, reportEnv.TargetHosts}
+}
+
+func (h *reportJobHandler) SetupRoutes(r *gin.Engine) {
+	jobRoutes := r.Group("/report/jobs", h.jwtMiddleware.RequireScope("report:mail"))
+	{
+		jobRoutes.POST("", h.idempotency.Handle(), h.Create)
+		jobRoutes.GET("/:id", h.Get)
+	}
+}
+
+// Create godoc
+// @Summary Queue report job
+// @Description Queues the generation and delivery of a container uptime/downtime report and returns the job immediately
+// @Tags job
+// @Accept json
+// @Produce json
+// @Param body body dto.ReportJobRequest true "Report job definition"
+// @Param Idempotency-Key header string false "Key under which a retry of this request returns the original response instead of sending again"
+// @Success 202 {object} dto.APIResponse{data=entities.ReportJob} "Report job queued successfully"
+// @Failure 400 {object} dto.APIResponse "Invalid input, target, time range or container filter"
+// @Failure 409 {object} map[string]string "Request with this idempotency key is still in progress"
+// @Failure 422 {object} map[string]string "Idempotency key was used for a different request"
+// @Failure 500 {object} dto.APIResponse "Failed to queue report job"
+// @Security BearerAuth
+// @Router /report/jobs [post]
+func (h *reportJobHandler) Create(c *gin.Context) {
+	var req dto.ReportJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if !validateTarget(c, h.targetHosts, req.Channel, req.Target) {
+		return
+	}
+
+	startTime, endTime, ok := parseTimeRange(c, req.Range, req.StartTime, req.EndTime, req.Timezone)
+	if !ok {
+		return
+	}
+	if !validateContainerFilter(c, req.Filter) {
+		return
+	}
+
+	job, err := h.jobService.Enqueue(c.Request.Context(), entities.ReportJob{
+		Owner:     c.GetString("userId"),
+		Channel:   req.Channel,
+		Target:    req.Target,
+		Filter:    req.Filter,
+		StartTime: startTime,
+		EndTime:   endTime,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Code:    "INTERNAL_SERVER_ERROR",
+			Message: "Failed to queue report job",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.APIResponse{
+		Success: true,
+		Code:    "JOB_QUEUED",
+		Message: "Report job queued successfully",
+		Data:    job,
+	})
+}
+
+// Get godoc
+// @Summary Get report job
+// @Description Returns the status of a report job queued by the authenticated user, with the recorded run attached once it has finished
+// @Tags job
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} dto.APIResponse{data=entities.ReportJob} "Report job retrieved successfully"
+// @Failure 404 {object} dto.APIResponse "Report job not found"
+// @Failure 500 {object} dto.APIResponse "Failed to retrieve report job"
+// @Security BearerAuth
+// @Router /report/jobs/{id} [get]
+func (h *reportJobHandler) Get(c *gin.Context) {
+	job, err := h.jobService.Get(c.Request.Context(), c.GetString("userId"), c.Param("id"))
+	if errors.Is(err, services.ErrJobNotFound) {
+		c.JSON(http.StatusNotFound, dto.APIResponse{
+			Success: false,
+			Code:    "NOT_FOUND",
+			Message: "Report job not found",
+			Error:   err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Code:    "INTERNAL_SERVER_ERROR",
+			Message: "Failed to retrieve report job",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Code:    "JOB_RETRIEVED",
+		Message: "Report job retrieved successfully",
+		Data:    job,
+	})
+}
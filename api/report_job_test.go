@@ -0,0 +1,220 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/vnFuhung2903/vcs-report-service/dto"
+	"github.com/vnFuhung2903/vcs-report-service/entities"
+	"github.com/vnFuhung2903/vcs-report-service/mocks/middlewares"
+	"github.com/vnFuhung2903/vcs-report-service/mocks/services"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/env"
+	usecases "github.com/vnFuhung2903/vcs-report-service/usecases/services"
+)
+
+type ReportJobHandlerSuite struct {
+	suite.Suite
+	ctrl              *gomock.Controller
+	mockJobService    *services.MockIReportJobService
+	mockJWTMiddleware *middlewares.MockIJWTMiddleware
+	mockIdempotency   *middlewares.MockIIdempotencyMiddleware
+	router            *gin.Engine
+	request           dto.ReportJobRequest
+}
+
+func (s *ReportJobHandlerSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.mockJobService = services.NewMockIReportJobService(s.ctrl)
+	s.mockJWTMiddleware = middlewares.NewMockIJWTMiddleware(s.ctrl)
+	s.mockIdempotency = middlewares.NewMockIIdempotencyMiddleware(s.ctrl)
+
+	s.mockJWTMiddleware.EXPECT().
+		RequireScope("report:mail").
+		Return(func(c *gin.Context) {
+			c.Set("userId", "user-1")
+			c.Next()
+		}).
+		AnyTimes()
+
+	s.mockIdempotency.EXPECT().
+		Handle().
+		Return(func(c *gin.Context) {
+			c.Next()
+		}).
+		AnyTimes()
+
+	gin.SetMode(gin.TestMode)
+	s.router = gin.New()
+	NewReportJobHandler(s.mockJobService, s.mockJWTMiddleware, s.mockIdempotency, env.ReportEnv{
+		TargetHosts: env.TargetHostsEnv{Slack: []string{"hooks.slack.com"}},
+	}).SetupRoutes(s.router)
+
+	s.request = dto.ReportJobRequest{
+		StartTime: "2024-01-01",
+		EndTime:   "2024-01-31",
+		Channel:   "email",
+		Target:    "ops@example.com",
+	}
+}
+
+func (s *ReportJobHandlerSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func TestReportJobHandlerSuite(t *testing.T) {
+	suite.Run(t, new(ReportJobHandlerSuite))
+}
+
+func (s *ReportJobHandlerSuite) serve(method string, path string, body interface{}) (*httptest.ResponseRecorder, dto.APIResponse) {
+	var payload bytes.Buffer
+	if body != nil {
+		s.Require().NoError(json.NewEncoder(&payload).Encode(body))
+	}
+
+	req := httptest.NewRequest(method, path, &payload)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+
+	var response dto.APIResponse
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	return w, response
+}
+
+func (s *ReportJobHandlerSuite) TestCreate() {
+	s.mockJobService.EXPECT().
+		Enqueue(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, job entities.ReportJob) (entities.ReportJob, error) {
+			s.Equal("user-1", job.Owner)
+			s.Equal("email", job.Channel)
+			s.Equal("ops@example.com", job.Target)
+			s.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), job.StartTime)
+			s.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), job.EndTime)
+			job.Id = "job-1"
+			job.Status = entities.JobQueued
+			return job, nil
+		})
+
+	w, response := s.serve(http.MethodPost, "/report/jobs", s.request)
+	s.Equal(http.StatusAccepted, w.Code)
+	s.True(response.Success)
+	s.Equal("JOB_QUEUED", response.Code)
+
+	data, ok := response.Data.(map[string]interface{})
+	s.Require().True(ok)
+	s.Equal("job-1", data["id"])
+	s.Equal("queued", data["status"])
+}
+
+func (s *ReportJobHandlerSuite) TestCreateWithRange() {
+	s.request.StartTime, s.request.EndTime = "", ""
+	s.request.Range = "last_week"
+	s.mockJobService.EXPECT().
+		Enqueue(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, job entities.ReportJob) (entities.ReportJob, error) {
+			s.Equal(time.Monday, job.StartTime.Weekday())
+			s.Equal(7*24*time.Hour, job.EndTime.Sub(job.StartTime))
+			s.True(job.EndTime.Before(time.Now()))
+			return job, nil
+		})
+
+	w, _ := s.serve(http.MethodPost, "/report/jobs", s.request)
+	s.Equal(http.StatusAccepted, w.Code)
+
+	s.request.Range = ""
+	w, response := s.serve(http.MethodPost, "/report/jobs", s.request)
+	s.Equal(http.StatusBadRequest, w.Code)
+	s.Equal("Invalid request data", response.Message)
+}
+
+func (s *ReportJobHandlerSuite) TestCreateWithFilter() {
+	s.request.Filter = entities.ContainerFilter{Pattern: "api-", Labels: map[string]string{"team": "payments"}}
+	s.mockJobService.EXPECT().
+		Enqueue(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, job entities.ReportJob) (entities.ReportJob, error) {
+			s.Equal(s.request.Filter, job.Filter)
+			return job, nil
+		})
+
+	w, _ := s.serve(http.MethodPost, "/report/jobs", s.request)
+	s.Equal(http.StatusAccepted, w.Code)
+
+	s.request.Filter.Pattern = "api-["
+	w, response := s.serve(http.MethodPost, "/report/jobs", s.request)
+	s.Equal(http.StatusBadRequest, w.Code)
+	s.Equal("Invalid container filter", response.Message)
+}
+
+func (s *ReportJobHandlerSuite) TestCreateInvalidTarget() {
+	s.request.Channel = "slack"
+	s.request.Target = "https://internal.example.com/hook"
+
+	w, response := s.serve(http.MethodPost, "/report/jobs", s.request)
+	s.Equal(http.StatusBadRequest, w.Code)
+	s.Equal("Invalid target", response.Message)
+}
+
+func (s *ReportJobHandlerSuite) TestCreateInvalidBody() {
+	s.request.Channel = "fax"
+
+	w, response := s.serve(http.MethodPost, "/report/jobs", s.request)
+	s.Equal(http.StatusBadRequest, w.Code)
+	s.Equal("Invalid request data", response.Message)
+}
+
+func (s *ReportJobHandlerSuite) TestCreateInvalidTimeRange() {
+	s.request.StartTime = "2024-02-01"
+
+	w, response := s.serve(http.MethodPost, "/report/jobs", s.request)
+	s.Equal(http.StatusBadRequest, w.Code)
+	s.Equal("start time must be before end time", response.Error)
+}
+
+func (s *ReportJobHandlerSuite) TestCreateServiceError() {
+	s.mockJobService.EXPECT().Enqueue(gomock.Any(), gomock.Any()).Return(entities.ReportJob{}, errors.New("redis connection failed"))
+
+	w, response := s.serve(http.MethodPost, "/report/jobs", s.request)
+	s.Equal(http.StatusInternalServerError, w.Code)
+	s.Equal("Failed to queue report job", response.Message)
+}
+
+func (s *ReportJobHandlerSuite) TestGet() {
+	s.mockJobService.EXPECT().
+		Get(gomock.Any(), "user-1", "job-1").
+		Return(entities.ReportJob{Id: "job-1", Status: entities.JobSucceeded, Result: &entities.ReportRun{Id: "run-1"}}, nil)
+
+	w, response := s.serve(http.MethodGet, "/report/jobs/job-1", nil)
+	s.Equal(http.StatusOK, w.Code)
+	s.Equal("JOB_RETRIEVED", response.Code)
+
+	data, ok := response.Data.(map[string]interface{})
+	s.Require().True(ok)
+	s.Equal("succeeded", data["status"])
+	s.NotNil(data["result"])
+}
+
+func (s *ReportJobHandlerSuite) TestGetNotFound() {
+	s.mockJobService.EXPECT().Get(gomock.Any(), "user-1", "missing").Return(entities.ReportJob{}, usecases.ErrJobNotFound)
+
+	w, response := s.serve(http.MethodGet, "/report/jobs/missing", nil)
+	s.Equal(http.StatusNotFound, w.Code)
+	s.Equal("NOT_FOUND", response.Code)
+}
+
+func (s *ReportJobHandlerSuite) TestGetServiceError() {
+	s.mockJobService.EXPECT().Get(gomock.Any(), "user-1", "job-1").Return(entities.ReportJob{}, errors.New("redis connection failed"))
+
+	w, response := s.serve(http.MethodGet, "/report/jobs/job-1", nil)
+	s.Equal(http.StatusInternalServerError, w.Code)
+	s.Equal("Failed to retrieve report job", response.Message)
+}
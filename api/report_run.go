@@ -0,0 +1,124 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vnFuhung2903/vcs-report-service/dto"
+	"github.com/vnFuhung2903/vcs-report-service/entities"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/middlewares"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/services"
+)
+
+type reportRunHandler struct {
+	runService    services.IReportRunService
+	jwtMiddleware middlewares.IJWTMiddleware
+}
+
+func NewReportRunHandler(runService services.IReportRunService, jwtMiddleware middlewares.IJWTMiddleware) *reportRunHandler {
+	return &reportRunHandler{runService, jwtMiddleware}
+}
+
+func (h *reportRunHandler) SetupRoutes(r *gin.Engine) {
+	runRoutes := r.Group("/report/runs", h.jwtMiddleware.RequireScope("report:read"))
+	{
+		runRoutes.GET("", h.List)
+		runRoutes.GET("/:id", h.Get)
+	}
+}
+
+// List godoc
+// @Summary List report runs
+// @Description Lists the report runs of the caller, most recent first: the runs it triggered through the API and those of its subscriptions, plus the runs of the configured schedules for callers with the report:admin scope
+// @Tags run
+// @Produce json
+// @Param limit query int false "Maximum number of runs (default 50)"
+// @Param status query string false "Run status" Enums(running, succeeded, partial, failed)
+// @Param trigger query string false "Run trigger" Enums(schedule, catchup, replay, api)
+// @Param schedule query string false "Schedule name"
+// @Success 200 {object} dto.APIResponse{data=[]entities.ReportRun} "Report runs retrieved successfully"
+// @Failure 400 {object} dto.APIResponse "Invalid input"
+// @Failure 500 {object} dto.APIResponse "Failed to retrieve report runs"
+// @Security BearerAuth
+// @Router /report/runs [get]
+func (h *reportRunHandler) List(c *gin.Context) {
+	var req dto.RunListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	runs, err := h.runService.List(c.Request.Context(), runOwners(c), req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Code:    "INTERNAL_SERVER_ERROR",
+			Message: "Failed to retrieve report runs",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Code:    "RUNS_RETRIEVED",
+		Message: "Report runs retrieved successfully",
+		Data:    runs,
+	})
+}
+
+// Get godoc
+// @Summary Get report run
+// @Description Returns a single report run of the caller with its outcome; callers with the report:admin scope may also read the runs of the configured schedules
+// @Tags run
+// @Produce json
+// @Param id path string true "Run ID"
+// @Success 200 {object} dto.APIResponse{data=entities.ReportRun} "Report run retrieved successfully"
+// @Failure 404 {object} dto.APIResponse "Report run not found"
+// @Failure 500 {object} dto.APIResponse "Failed to retrieve report run"
+// @Security BearerAuth
+// @Router /report/runs/{id} [get]
+func (h *reportRunHandler) Get(c *gin.Context) {
+	run, err := h.runService.Get(c.Request.Context(), runOwners(c), c.Param("id"))
+	if errors.Is(err, services.ErrRunNotFound) {
+		c.JSON(http.StatusNotFound, dto.APIResponse{
+			Success: false,
+			Code:    "NOT_FOUND",
+			Message: "Report run not found",
+			Error:   err.Error(),
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Code:    "INTERNAL_SERVER_ERROR",
+			Message: "Failed to retrieve report run",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Code:    "RUN_RETRIEVED",
+		Message: "Report run retrieved successfully",
+		Data:    run,
+	})
+}
+
+// runOwners lists whose runs the caller may read: its own, and those of the
+// configured schedules when it administers reports.
+func runOwners(c *gin.Context) []string {
+	owners := []string{c.GetString("userId")}
+	if slices.Contains(c.GetStringSlice("scopes"), "report:admin") {
+		owners = append(owners, entities.RunOwnerSystem)
+	}
+	return owners
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/vnFuhung2903/vcs-report-service/dto"
+	"github.com/vnFuhung2903/vcs-report-service/entities"
+	"github.com/vnFuhung2903/vcs-report-service/mocks/middlewares"
+	"github.com/vnFuhung2903/vcs-report-service/mocks/services"
+	usecases "github.com/vnFuhung2903/vcs-report-service/usecases/services"
+)
+
+type ReportRunHandlerSuite struct {
+	suite.Suite
+	ctrl              *gomock.Controller
+	mockRunService    *services.MockIReportRunService
+	mockJWTMiddleware *middlewares.MockIJWTMiddleware
+	router            *gin.Engine
+	scopes            []string
+}
+
+func (s *ReportRunHandlerSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.mockRunService = services.NewMockIReportRunService(s.ctrl)
+	s.mockJWTMiddleware = middlewares.NewMockIJWTMiddleware(s.ctrl)
+	s.scopes = []string{"report:read"}
+
+	s.mockJWTMiddleware.EXPECT().
+		RequireScope("report:read").
+		Return(func(c *gin.Context) {
+			c.Set("scopes", s.scopes)
+			c.Set("userId", "user-1")
+			c.Next()
+		}).
+		AnyTimes()
+
+	gin.SetMode(gin.TestMode)
+	s.router = gin.New()
+	NewReportRunHandler(s.mockRunService, s.mockJWTMiddleware).SetupRoutes(s.router)
+}
+
+func (s *ReportRunHandlerSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func TestReportRunHandlerSuite(t *testing.T) {
+	suite.Run(t, new(ReportRunHandlerSuite))
+}
+
+func (s *ReportRunHandlerSuite) serve(path string) (*httptest.ResponseRecorder, dto.APIResponse) {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+
+	var response dto.APIResponse
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	return w, response
+}
+
+func (s *ReportRunHandlerSuite) TestList() {
+	s.mockRunService.EXPECT().
+		List(gomock.Any(), []string{"user-1"}, dto.RunListRequest{Limit: 10, Status: "failed", Trigger: "schedule", Schedule: "daily"}).
+		Return([]entities.ReportRun{{Id: "run-1", Status: entities.RunFailed}}, nil)
+
+	w, response := s.serve("/report/runs?limit=10&status=failed&trigger=schedule&schedule=daily")
+	s.Equal(http.StatusOK, w.Code)
+	s.True(response.Success)
+	s.Equal("RUNS_RETRIEVED", response.Code)
+	s.Len(response.Data, 1)
+}
+
+func (s *ReportRunHandlerSuite) TestListInvalidQuery() {
+	w, response := s.serve("/report/runs?status=unknown")
+	s.Equal(http.StatusBadRequest, w.Code)
+	s.NotEmpty(response.Error)
+
+	w, _ = s.serve("/report/runs?limit=1000")
+	s.Equal(http.StatusBadRequest, w.Code)
+}
+
+func (s *ReportRunHandlerSuite) TestListError() {
+	s.mockRunService.EXPECT().List(gomock.Any(), []string{"user-1"}, dto.RunListRequest{}).Return(nil, errors.New("redis connection failed"))
+
+	w, response := s.serve("/report/runs")
+	s.Equal(http.StatusInternalServerError, w.Code)
+	s.Equal("Failed to retrieve report runs", response.Message)
+}
+
+func (s *ReportRunHandlerSuite) TestGet() {
+	s.mockRunService.EXPECT().Get(gomock.Any(), []string{"user-1"}, "run-1").Return(entities.ReportRun{Id: "run-1", Status: entities.RunSucceeded}, nil)
+
+	w, response := s.serve("/report/runs/run-1")
+	s.Equal(http.StatusOK, w.Code)
+	s.Equal("RUN_RETRIEVED", response.Code)
+	s.Equal("succeeded", response.Data.(map[string]interface{})["status"])
+}
+
+func (s *ReportRunHandlerSuite) TestAdminReadsSystemRuns() {
+	s.scopes = []string{"report:read", "report:admin"}
+	s.mockRunService.EXPECT().
+		List(gomock.Any(), []string{"user-1", entities.RunOwnerSystem}, dto.RunListRequest{}).
+		Return([]entities.ReportRun{{Id: "run-1", Owner: entities.RunOwnerSystem}}, nil)
+	s.mockRunService.EXPECT().
+		Get(gomock.Any(), []string{"user-1", entities.RunOwnerSystem}, "run-1").
+		Return(entities.ReportRun{Id: "run-1", Owner: entities.RunOwnerSystem}, nil)
+
+	w, response := s.serve("/report/runs")
+	s.Equal(http.StatusOK, w.Code)
+	s.Len(response.Data, 1)
+
+	w, _ = s.serve("/report/runs/run-1")
+	s.Equal(http.StatusOK, w.Code)
+}
+
+func (s *ReportRunHandlerSuite) TestGetNotFound() {
+	s.mockRunService.EXPECT().Get(gomock.Any(), []string{"user-1"}, "missing").Return(entities.ReportRun{}, usecases.ErrRunNotFound)
+
+	w, response := s.serve("/report/runs/missing")
+	s.Equal(http.StatusNotFound, w.Code)
+	s.Equal("NOT_FOUND", response.Code)
+}
+
+func (s *ReportRunHandlerSuite) TestGetError() {
+	s.mockRunService.EXPECT().Get(gomock.Any(), []string{"user-1"}, "run-1").Return(entities.ReportRun{}, fmt.Errorf("decode: %w", errors.New("unexpected end of JSON input")))
+
+	w, response := s.serve("/report/runs/run-1")
+	s.Equal(http.StatusInternalServerError, w.Code)
+	s.Equal("Failed to retrieve report run", response.Message)
+}
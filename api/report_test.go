@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -15,15 +16,23 @@ import (
 
 	"github.com/vnFuhung2903/vcs-report-service/dto"
 	"github.com/vnFuhung2903/vcs-report-service/entities"
+	"github.com/vnFuhung2903/vcs-report-service/interfaces"
+	"github.com/vnFuhung2903/vcs-report-service/mocks/logger"
 	"github.com/vnFuhung2903/vcs-report-service/mocks/middlewares"
+	"github.com/vnFuhung2903/vcs-report-service/mocks/notifiers"
 	"github.com/vnFuhung2903/vcs-report-service/mocks/services"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/env"
 )
 
 type ReportHandlerSuite struct {
 	suite.Suite
 	ctrl              *gomock.Controller
 	mockReportService *services.MockIReportService
+	mockNotification  *notifiers.MockINotificationService
+	mockRunService    *services.MockIReportRunService
 	mockJWTMiddleware *middlewares.MockIJWTMiddleware
+	mockIdempotency   *middlewares.MockIIdempotencyMiddleware
+	mockLogger        *logger.MockILogger
 	handler           *reportHandler
 	router            *gin.Engine
 }
@@ -31,16 +40,41 @@ type ReportHandlerSuite struct {
 func (s *ReportHandlerSuite) SetupTest() {
 	s.ctrl = gomock.NewController(s.T())
 	s.mockReportService = services.NewMockIReportService(s.ctrl)
+	s.mockNotification = notifiers.NewMockINotificationService(s.ctrl)
+	s.mockRunService = services.NewMockIReportRunService(s.ctrl)
 	s.mockJWTMiddleware = middlewares.NewMockIJWTMiddleware(s.ctrl)
+	s.mockIdempotency = middlewares.NewMockIIdempotencyMiddleware(s.ctrl)
+	s.mockLogger = logger.NewMockILogger(s.ctrl)
+
+	s.mockJWTMiddleware.EXPECT().
+		RequireScope("report:read").
+		Return(func(c *gin.Context) {
+			c.Next()
+		}).
+		AnyTimes()
 
 	s.mockJWTMiddleware.EXPECT().
 		RequireScope("report:mail").
+		Return(func(c *gin.Context) {
+			c.Set("userId", "user-1")
+			c.Next()
+		}).
+		AnyTimes()
+
+	s.mockIdempotency.EXPECT().
+		Handle().
 		Return(func(c *gin.Context) {
 			c.Next()
 		}).
 		AnyTimes()
 
-	s.handler = NewReportHandler(s.mockReportService, s.mockJWTMiddleware)
+	s.handler = NewReportHandler(s.mockReportService, s.mockNotification, s.mockRunService, s.mockJWTMiddleware, s.mockIdempotency, env.ReportEnv{
+		TargetHosts: env.TargetHostsEnv{
+			Slack:   []string{"hooks.slack.com"},
+			Teams:   []string{"*.webhook.office.com"},
+			Webhook: []string{"example.com"},
+		},
+	}, s.mockLogger)
 
 	gin.SetMode(gin.TestMode)
 	s.router = gin.New()
@@ -55,40 +89,286 @@ func TestReportHandlerSuite(t *testing.T) {
 	suite.Run(t, new(ReportHandlerSuite))
 }
 
-func (s *ReportHandlerSuite) TestSendEmail() {
-	baseTime := time.Now()
-	startTime := baseTime.Add(-4 * time.Hour)
+func (s *ReportHandlerSuite) expectRun(channel string, target string, failed bool, generated bool) {
+	s.mockRunService.EXPECT().
+		Start(gomock.Any(), gomock.Any(), nil).
+		DoAndReturn(func(ctx context.Context, run entities.ReportRun, lease *interfaces.Lease) (entities.ReportRun, error) {
+			s.Equal(entities.RunTriggerAPI, run.Trigger)
+			s.Equal("user-1", run.Owner)
+			s.Equal(channel, run.Channel)
+			s.Equal([]string{target}, run.Recipients)
+			run.Id = "run-1"
+			return run, nil
+		})
+	s.mockRunService.EXPECT().
+		Finish(gomock.Any(), gomock.Any(), gomock.Any(), nil).
+		DoAndReturn(func(ctx context.Context, run entities.ReportRun, report *dto.ReportResponse, lease *interfaces.Lease) (entities.ReportRun, error) {
+			s.Equal("run-1", run.Id)
+			s.Equal(generated, report != nil)
+			if failed {
+				s.NotEmpty(run.Error)
+				run.Status = entities.RunFailed
+			} else {
+				run.Status = entities.RunSucceeded
+			}
+			return run, nil
+		})
+}
 
-	statusList := map[string][]dto.EsStatus{
-		"container1": {
-			{ContainerId: "container1", Status: entities.ContainerOn, Uptime: int64(3600), LastUpdated: baseTime.Add(-210 * time.Minute)},
-			{ContainerId: "container1", Status: entities.ContainerOff, Uptime: int64(1800), LastUpdated: baseTime.Add(-3 * time.Hour)},
-			{ContainerId: "container1", Status: entities.ContainerOn, Uptime: int64(3600), LastUpdated: baseTime.Add(-2 * time.Hour)},
-		},
-		"container2": {
-			{ContainerId: "container2", Status: entities.ContainerOff, Uptime: int64(7200), LastUpdated: baseTime.Add(-1 * time.Minute)},
+func (s *ReportHandlerSuite) TestGetReport() {
+	startTime := time.Now().Add(-4 * time.Hour)
+
+	report := dto.ReportResponse{
+		ContainerCount:    2,
+		ContainerOnCount:  1,
+		ContainerOffCount: 1,
+		TotalUptime:       50.0,
+		Containers: []dto.ContainerReport{
+			{ContainerId: "container1", Status: entities.ContainerOn, UptimeHours: 50.0, Transitions: 1},
+			{ContainerId: "container2", Status: entities.ContainerOff, DowntimeHours: 4.0},
 		},
 	}
+	s.mockReportService.EXPECT().
+		GenerateReport(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(report, nil)
+
+	params := url.Values{}
+	params.Set("start_time", startTime.UTC().Format("2006-01-02"))
+
+	req := httptest.NewRequest("GET", "/report?"+params.Encode(), nil)
+	w := httptest.NewRecorder()
+
+	s.router.ServeHTTP(w, req)
+	s.Equal(http.StatusOK, w.Code)
+
+	var response struct {
+		dto.APIResponse
+		Data dto.ReportResponse `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	s.NoError(err)
+	s.True(response.Success)
+	s.Equal("REPORT_RETRIEVED", response.Code)
+	s.Equal(2, response.Data.ContainerCount)
+	s.Equal(1, response.Data.ContainerOnCount)
+	s.Equal(1, response.Data.ContainerOffCount)
+	s.Equal(50.0, response.Data.TotalUptime)
+	s.Equal(report.Containers, response.Data.Containers)
+}
+
+func (s *ReportHandlerSuite) TestGetReportTimeRange() {
+	saigon, err := time.LoadLocation("Asia/Ho_Chi_Minh")
+	s.Require().NoError(err)
+
+	tests := []struct {
+		query string
+		start time.Time
+		end   time.Time
+	}{
+		{"start_time=2024-01-01&end_time=2024-01-31", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"start_time=2024-01-01&end_time=2024-01-01&tz=Asia/Ho_Chi_Minh", time.Date(2024, 1, 1, 0, 0, 0, 0, saigon), time.Date(2024, 1, 2, 0, 0, 0, 0, saigon)},
+		{"start_time=2024-01-01T06:30:00%2B07:00&end_time=2024-01-01T12:00:00Z&tz=Europe/Berlin", time.Date(2023, 12, 31, 23, 30, 0, 0, time.UTC), time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)},
+		{"start_time=2024-01-01&end_time=2024-01-01T12:00:00Z&tz=Asia/Ho_Chi_Minh", time.Date(2023, 12, 31, 17, 0, 0, 0, time.UTC), time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)},
+	}
 
-	overlapStatusList := map[string][]dto.EsStatus{
-		"container1": {},
-		"container2": {},
+	for _, test := range tests {
+		s.mockReportService.EXPECT().
+			GenerateReport(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, startTime time.Time, endTime time.Time, filter entities.ContainerFilter) (dto.ReportResponse, error) {
+				s.True(test.start.Equal(startTime), "%s: start %s", test.query, startTime)
+				s.True(test.end.Equal(endTime), "%s: end %s", test.query, endTime)
+				return dto.ReportResponse{}, nil
+			})
+
+		req := httptest.NewRequest("GET", "/report?"+test.query, nil)
+		w := httptest.NewRecorder()
+		s.router.ServeHTTP(w, req)
+		s.Equal(http.StatusOK, w.Code, test.query)
 	}
+}
+
+func (s *ReportHandlerSuite) TestGetReportRange() {
+	saigon, err := time.LoadLocation("Asia/Ho_Chi_Minh")
+	s.Require().NoError(err)
+	today := time.Now().In(saigon)
+	midnight := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, saigon)
 
 	s.mockReportService.EXPECT().
-		GetEsStatus(gomock.Any(), 10000, gomock.Any(), gomock.Any(), dto.Asc).
-		Return(statusList, nil)
+		GenerateReport(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, startTime time.Time, endTime time.Time, filter entities.ContainerFilter) (dto.ReportResponse, error) {
+			s.True(midnight.AddDate(0, 0, -1).Equal(startTime), "start %s", startTime)
+			s.True(midnight.Equal(endTime), "end %s", endTime)
+			return dto.ReportResponse{}, nil
+		})
+	s.mockReportService.EXPECT().
+		GenerateReport(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, startTime time.Time, endTime time.Time, filter entities.ContainerFilter) (dto.ReportResponse, error) {
+			s.True(midnight.AddDate(0, 0, -7).Equal(startTime), "start %s", startTime)
+			s.WithinDuration(time.Now(), endTime, time.Minute)
+			return dto.ReportResponse{}, nil
+		})
+
+	req := httptest.NewRequest("GET", "/report?range=yesterday&tz=Asia/Ho_Chi_Minh", nil)
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	s.Equal(http.StatusOK, w.Code)
 
+	req = httptest.NewRequest("GET", "/report?start_time=now-7d/d&tz=Asia/Ho_Chi_Minh", nil)
+	w = httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	s.Equal(http.StatusOK, w.Code)
+}
+
+func (s *ReportHandlerSuite) TestGetReportInvalidTimeRange() {
+	tests := map[string]string{
+		"start_time=2024-01-01&tz=Mars/Olympus_Mons":                           "Invalid timezone",
+		"start_time=01/01/2024":                                                "Invalid start time format",
+		"start_time=2024-01-01&end_time=2024-01-31T25:00:00Z":                  "Invalid end time format",
+		"start_time=2024-01-01T12:00:00Z&end_time=2024-01-01T12:00:00%2B00:00": "Invalid request data",
+		"range=last_year":                       "Invalid time range",
+		"range=yesterday&start_time=2024-01-01": "Invalid request data",
+		"start_time=now-7q":                     "Invalid start time format",
+	}
+
+	for query, message := range tests {
+		req := httptest.NewRequest("GET", "/report?"+query, nil)
+		w := httptest.NewRecorder()
+		s.router.ServeHTTP(w, req)
+		s.Equal(http.StatusBadRequest, w.Code, query)
+
+		var response dto.APIResponse
+		s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+		s.Equal(message, response.Message, query)
+	}
+}
+
+func (s *ReportHandlerSuite) TestGetReportContainerFilter() {
 	s.mockReportService.EXPECT().
-		GetEsStatus(gomock.Any(), 1, gomock.Any(), gomock.Any(), dto.Asc).
-		Return(overlapStatusList, nil)
+		GenerateReport(gomock.Any(), gomock.Any(), gomock.Any(), entities.ContainerFilter{
+			Ids:     []string{"container1", "container2", "container3"},
+			Pattern: "api-*",
+			Labels:  map[string]string{"team": "payments", "env": "prod"},
+		}).
+		Return(dto.ReportResponse{}, nil)
 
+	req := httptest.NewRequest("GET", "/report?start_time=2024-01-01&container_id=container1,container2&container_id=container3&container_pattern=api-*&labels=team%3Dpayments,%20env%3Dprod", nil)
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	s.Equal(http.StatusOK, w.Code)
+}
+
+func (s *ReportHandlerSuite) TestGetReportInvalidContainerFilter() {
+	for _, query := range []string{
+		"start_time=2024-01-01&labels=team",
+		"start_time=2024-01-01&labels=%3Dpayments",
+		"start_time=2024-01-01&container_pattern=api-[",
+	} {
+		req := httptest.NewRequest("GET", "/report?"+query, nil)
+		w := httptest.NewRecorder()
+		s.router.ServeHTTP(w, req)
+		s.Equal(http.StatusBadRequest, w.Code, query)
+
+		var response dto.APIResponse
+		s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+		s.Equal("Invalid container filter", response.Message, query)
+	}
+}
+
+func (s *ReportHandlerSuite) TestGetReportCSV() {
+	report := dto.ReportResponse{
+		StartTime: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC),
+		Containers: []dto.ContainerReport{
+			{ContainerId: "container1", Status: entities.ContainerOn, UptimeHours: 50.0, Availability: 100},
+		},
+	}
 	s.mockReportService.EXPECT().
-		CalculateReportStatistic(statusList, overlapStatusList, gomock.Any(), gomock.Any()).
-		Return(1, 1, 50.0)
+		GenerateReport(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(report, nil).
+		Times(2)
+
+	expected := "container_id,status,uptime_hours,downtime_hours,availability\ncontainer1,ON,50.00,0.00,100.00\n"
 
+	req := httptest.NewRequest("GET", "/report?start_time=2024-01-01&end_time=2024-01-31&format=csv", nil)
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	s.Equal(http.StatusOK, w.Code)
+	s.Equal("text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	s.Equal(`attachment; filename="report_2024-01-01_2024-01-31.csv"`, w.Header().Get("Content-Disposition"))
+	s.Equal(expected, w.Body.String())
+
+	req = httptest.NewRequest("GET", "/report?start_time=2024-01-01&end_time=2024-01-31", nil)
+	req.Header.Set("Accept", "text/csv")
+	w = httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	s.Equal(http.StatusOK, w.Code)
+	s.Equal(expected, w.Body.String())
+}
+
+func (s *ReportHandlerSuite) TestGetReportFormatOverridesAccept() {
 	s.mockReportService.EXPECT().
-		SendEmail(gomock.Any(), "test@example.com", 2, 1, 1, 50.0, gomock.Any(), gomock.Any()).
+		GenerateReport(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(dto.ReportResponse{}, nil)
+
+	req := httptest.NewRequest("GET", "/report?start_time=2024-01-01&format=json", nil)
+	req.Header.Set("Accept", "text/csv")
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	s.Equal(http.StatusOK, w.Code)
+	s.Contains(w.Header().Get("Content-Type"), "application/json")
+}
+
+func (s *ReportHandlerSuite) TestGetReportInvalidQueryBinding() {
+	req := httptest.NewRequest("GET", "/report", nil)
+	w := httptest.NewRecorder()
+
+	s.router.ServeHTTP(w, req)
+	s.Equal(http.StatusBadRequest, w.Code)
+
+	var response dto.APIResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	s.NoError(err)
+	s.NotEmpty(response.Error)
+
+	req = httptest.NewRequest("GET", "/report?start_time=2024-01-01&end_time=2023-01-02", nil)
+	w = httptest.NewRecorder()
+
+	s.router.ServeHTTP(w, req)
+	s.Equal(http.StatusBadRequest, w.Code)
+
+	err = json.Unmarshal(w.Body.Bytes(), &response)
+	s.NoError(err)
+	s.NotEmpty(response.Error)
+}
+
+func (s *ReportHandlerSuite) TestGetReportGenerateReportError() {
+	s.mockReportService.EXPECT().
+		GenerateReport(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(dto.ReportResponse{}, errors.New("elasticsearch error"))
+
+	req := httptest.NewRequest("GET", "/report?start_time=2024-01-01&end_time=2024-01-02", nil)
+	w := httptest.NewRecorder()
+
+	s.router.ServeHTTP(w, req)
+	s.Equal(http.StatusInternalServerError, w.Code)
+
+	var response dto.APIResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	s.NoError(err)
+	s.Equal("elasticsearch error", response.Error)
+}
+
+func (s *ReportHandlerSuite) TestSendEmail() {
+	startTime := time.Now().Add(-4 * time.Hour)
+
+	report := dto.ReportResponse{ContainerCount: 2, ContainerOnCount: 1, ContainerOffCount: 1, TotalUptime: 50.0}
+	s.expectRun("email", "test@example.com", false, true)
+	s.mockReportService.EXPECT().
+		GenerateReport(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(report, nil)
+
+	s.mockReportService.EXPECT().
+		SendEmail(gomock.Any(), "test@example.com", report).
 		Return(nil)
 
 	params := url.Values{}
@@ -106,6 +386,59 @@ func (s *ReportHandlerSuite) TestSendEmail() {
 	s.NoError(err)
 	s.True(response.Success)
 	s.Equal("REPORT_EMAILED", response.Code)
+	s.Equal("run-1", response.Data.(map[string]interface{})["id"])
+}
+
+func (s *ReportHandlerSuite) TestSendEmailRunStartError() {
+	s.mockRunService.EXPECT().
+		Start(gomock.Any(), gomock.Any(), nil).
+		Return(entities.ReportRun{}, errors.New("redis connection failed"))
+	s.mockLogger.EXPECT().Error("failed to record report run", gomock.Any(), gomock.Any()).Times(1)
+
+	params := url.Values{}
+	params.Set("email", "test@example.com")
+	params.Set("start_time", time.Now().Add(-4*time.Hour).UTC().Format("2006-01-02"))
+
+	req := httptest.NewRequest("GET", "/report/mail?"+params.Encode(), nil)
+	w := httptest.NewRecorder()
+
+	s.router.ServeHTTP(w, req)
+	s.Equal(http.StatusInternalServerError, w.Code)
+
+	var response dto.APIResponse
+	s.NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	s.Equal("Failed to record report run", response.Message)
+}
+
+func (s *ReportHandlerSuite) TestSendEmailRunFinishError() {
+	report := dto.ReportResponse{ContainerCount: 1}
+	s.mockRunService.EXPECT().
+		Start(gomock.Any(), gomock.Any(), nil).
+		Return(entities.ReportRun{Id: "run-1"}, nil)
+	s.mockReportService.EXPECT().
+		GenerateReport(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(report, nil)
+	s.mockReportService.EXPECT().
+		SendEmail(gomock.Any(), "test@example.com", report).
+		Return(nil)
+	s.mockRunService.EXPECT().
+		Finish(gomock.Any(), gomock.Any(), &report, nil).
+		Return(entities.ReportRun{Id: "run-1", Status: entities.RunSucceeded}, errors.New("redis connection failed"))
+	s.mockLogger.EXPECT().Warn("failed to record report run", gomock.Any(), gomock.Any()).Times(1)
+
+	params := url.Values{}
+	params.Set("email", "test@example.com")
+	params.Set("start_time", time.Now().Add(-4*time.Hour).UTC().Format("2006-01-02"))
+
+	req := httptest.NewRequest("GET", "/report/mail?"+params.Encode(), nil)
+	w := httptest.NewRecorder()
+
+	s.router.ServeHTTP(w, req)
+	s.Equal(http.StatusOK, w.Code)
+
+	var response dto.APIResponse
+	s.NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	s.Equal("REPORT_EMAILED", response.Code)
 }
 
 func (s *ReportHandlerSuite) TestSendEmailInvalidQueryBinding() {
@@ -153,14 +486,15 @@ func (s *ReportHandlerSuite) TestSendEmailInvalidDateRange() {
 	s.NotEmpty(response.Error)
 }
 
-func (s *ReportHandlerSuite) TestSendEmailGetEsStatusError() {
+func (s *ReportHandlerSuite) TestSendEmailGenerateReportError() {
 	baseTime := time.Now()
 	endTime := baseTime
 	startTime := baseTime.Add(-4 * time.Hour)
 
+	s.expectRun("email", "test@example.com", true, false)
 	s.mockReportService.EXPECT().
-		GetEsStatus(gomock.Any(), 10000, gomock.Any(), gomock.Any(), dto.Asc).
-		Return(map[string][]dto.EsStatus{}, errors.New("elasticsearch error"))
+		GenerateReport(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(dto.ReportResponse{}, errors.New("elasticsearch error"))
 
 	params := url.Values{}
 	params.Set("email", "test@example.com")
@@ -179,25 +513,20 @@ func (s *ReportHandlerSuite) TestSendEmailGetEsStatusError() {
 	s.Equal("elasticsearch error", response.Error)
 }
 
-func (s *ReportHandlerSuite) TestSendEmailGetEsStatusOverlapError() {
+func (s *ReportHandlerSuite) TestSendEmailSendEmailServiceError() {
 	baseTime := time.Now()
 	endTime := baseTime
 	startTime := endTime.Add(-4 * time.Hour)
-	statusList := map[string][]dto.EsStatus{
-		"container1": {
-			{ContainerId: "container1", Status: entities.ContainerOn, Uptime: int64(3600), LastUpdated: baseTime.Add(-210 * time.Minute)},
-			{ContainerId: "container1", Status: entities.ContainerOff, Uptime: int64(1800), LastUpdated: baseTime.Add(-3 * time.Hour)},
-			{ContainerId: "container1", Status: entities.ContainerOn, Uptime: int64(3600), LastUpdated: baseTime.Add(-2 * time.Hour)},
-		},
-	}
 
+	report := dto.ReportResponse{ContainerCount: 1, ContainerOnCount: 1, ContainerOffCount: 0, TotalUptime: 100.0}
+	s.expectRun("email", "test@example.com", true, true)
 	s.mockReportService.EXPECT().
-		GetEsStatus(gomock.Any(), 10000, gomock.Any(), gomock.Any(), dto.Asc).
-		Return(statusList, nil)
+		GenerateReport(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(report, nil)
 
 	s.mockReportService.EXPECT().
-		GetEsStatus(gomock.Any(), 1, gomock.Any(), gomock.Any(), dto.Asc).
-		Return(map[string][]dto.EsStatus{}, errors.New("elasticsearch error"))
+		SendEmail(gomock.Any(), "test@example.com", report).
+		Return(errors.New("service error"))
 
 	params := url.Values{}
 	params.Set("email", "test@example.com")
@@ -208,60 +537,123 @@ func (s *ReportHandlerSuite) TestSendEmailGetEsStatusOverlapError() {
 	w := httptest.NewRecorder()
 
 	s.router.ServeHTTP(w, req)
+
 	s.Equal(http.StatusInternalServerError, w.Code)
 
 	var response dto.APIResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	s.NoError(err)
-	s.Equal("elasticsearch error", response.Error)
+	s.Equal("service error", response.Error)
 }
 
-func (s *ReportHandlerSuite) TestSendEmailSendEmailServiceError() {
-	baseTime := time.Now()
-	endTime := baseTime
-	startTime := endTime.Add(-4 * time.Hour)
-	statusList := map[string][]dto.EsStatus{
-		"container1": {
-			{ContainerId: "container1", Status: entities.ContainerOn, Uptime: int64(3600), LastUpdated: baseTime.Add(-210 * time.Minute)},
-			{ContainerId: "container1", Status: entities.ContainerOff, Uptime: int64(1800), LastUpdated: baseTime.Add(-3 * time.Hour)},
-			{ContainerId: "container1", Status: entities.ContainerOn, Uptime: int64(3600), LastUpdated: baseTime.Add(-2 * time.Hour)},
-		},
-	}
+func (s *ReportHandlerSuite) TestNotify() {
+	startTime := time.Now().Add(-4 * time.Hour)
+
+	report := dto.ReportResponse{ContainerCount: 2, ContainerOnCount: 1, ContainerOffCount: 1, TotalUptime: 50.0}
+	s.expectRun("slack", "https://hooks.slack.com/services/T000/B000/XXX", false, true)
+	s.mockReportService.EXPECT().
+		GenerateReport(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(report, nil)
+
+	s.mockNotification.EXPECT().
+		Notify(gomock.Any(), "slack", "https://hooks.slack.com/services/T000/B000/XXX", report).
+		Return(nil)
+
+	params := url.Values{}
+	params.Set("channel", "slack")
+	params.Set("target", "https://hooks.slack.com/services/T000/B000/XXX")
+	params.Set("start_time", startTime.UTC().Format("2006-01-02"))
+
+	req := httptest.NewRequest("GET", "/report/notify?"+params.Encode(), nil)
+	w := httptest.NewRecorder()
+
+	s.router.ServeHTTP(w, req)
+	s.Equal(http.StatusOK, w.Code)
+
+	var response dto.APIResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	s.NoError(err)
+	s.True(response.Success)
+	s.Equal("REPORT_SENT", response.Code)
+	s.Equal("succeeded", response.Data.(map[string]interface{})["status"])
+}
+
+func (s *ReportHandlerSuite) TestNotifyInvalidQueryBinding() {
+	req := httptest.NewRequest("GET", "/report/notify?channel=sms&target=123&start_time=2024-01-01", nil)
+	w := httptest.NewRecorder()
+
+	s.router.ServeHTTP(w, req)
+	s.Equal(http.StatusBadRequest, w.Code)
+
+	var response dto.APIResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	s.NoError(err)
+	s.NotEmpty(response.Error)
 
-	overlapStatusList := map[string][]dto.EsStatus{
-		"container1": {},
+	req = httptest.NewRequest("GET", "/report/notify?channel=teams&target=https://example.com&start_time=invalid-datetime", nil)
+	w = httptest.NewRecorder()
+
+	s.router.ServeHTTP(w, req)
+	s.Equal(http.StatusBadRequest, w.Code)
+}
+
+func (s *ReportHandlerSuite) TestNotifyInvalidTarget() {
+	for _, query := range []string{
+		"channel=webhook&target=http://example.com/hook",
+		"channel=webhook&target=https://169.254.169.254/latest/meta-data",
+		"channel=webhook&target=https://example.com.evil.io/hook",
+		"channel=slack&target=https://example.com/hook",
+		"channel=teams&target=https://webhook.office.com.internal/hook",
+		"channel=email&target=not-an-address",
+	} {
+		req := httptest.NewRequest("GET", "/report/notify?start_time=2024-01-01&"+query, nil)
+		w := httptest.NewRecorder()
+		s.router.ServeHTTP(w, req)
+		s.Equal(http.StatusBadRequest, w.Code, query)
+
+		var response dto.APIResponse
+		s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+		s.Equal("Invalid target", response.Message, query)
 	}
+}
 
+func (s *ReportHandlerSuite) TestNotifyGenerateReportError() {
+	s.expectRun("webhook", "https://example.com", true, false)
 	s.mockReportService.EXPECT().
-		GetEsStatus(gomock.Any(), 10000, gomock.Any(), gomock.Any(), dto.Asc).
-		Return(statusList, nil)
+		GenerateReport(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(dto.ReportResponse{}, errors.New("elasticsearch error"))
 
-	s.mockReportService.EXPECT().
-		GetEsStatus(gomock.Any(), 1, gomock.Any(), gomock.Any(), dto.Asc).
-		Return(overlapStatusList, nil)
+	req := httptest.NewRequest("GET", "/report/notify?channel=webhook&target=https://example.com&start_time=2024-01-01&end_time=2024-01-02", nil)
+	w := httptest.NewRecorder()
 
-	s.mockReportService.EXPECT().
-		CalculateReportStatistic(statusList, overlapStatusList, gomock.Any(), gomock.Any()).
-		Return(1, 0, 100.0)
+	s.router.ServeHTTP(w, req)
+	s.Equal(http.StatusInternalServerError, w.Code)
+
+	var response dto.APIResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	s.NoError(err)
+	s.Equal("elasticsearch error", response.Error)
+}
 
+func (s *ReportHandlerSuite) TestNotifyServiceError() {
+	report := dto.ReportResponse{ContainerCount: 1, ContainerOnCount: 1}
+	s.expectRun("teams", "https://contoso.webhook.office.com/webhookb2/XXX", true, true)
 	s.mockReportService.EXPECT().
-		SendEmail(gomock.Any(), "test@example.com", 1, 1, 0, 100.0, gomock.Any(), gomock.Any()).
-		Return(errors.New("service error"))
+		GenerateReport(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(report, nil)
 
-	params := url.Values{}
-	params.Set("email", "test@example.com")
-	params.Set("start_time", startTime.UTC().Format("2006-01-02"))
-	params.Set("end_time", endTime.UTC().Format("2006-01-02"))
+	s.mockNotification.EXPECT().
+		Notify(gomock.Any(), "teams", "https://contoso.webhook.office.com/webhookb2/XXX", report).
+		Return(errors.New("webhook request failed: 500 Internal Server Error"))
 
-	req := httptest.NewRequest("GET", "/report/mail?"+params.Encode(), nil)
+	req := httptest.NewRequest("GET", "/report/notify?channel=teams&target=https://contoso.webhook.office.com/webhookb2/XXX&start_time=2024-01-01&end_time=2024-01-02", nil)
 	w := httptest.NewRecorder()
 
 	s.router.ServeHTTP(w, req)
-
 	s.Equal(http.StatusInternalServerError, w.Code)
 
 	var response dto.APIResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	s.NoError(err)
-	s.Equal("service error", response.Error)
+	s.Equal("Failed to send notification", response.Message)
 }
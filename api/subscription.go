@@ -0,0 +1,209 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vnFuhung2903/vcs-report-service/dto"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/middlewares"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/services"
+)
+
+type subscriptionHandler struct {
+	subscriptionService services.ISubscriptionService
+	jwtMiddleware       middlewares.IJWTMiddleware
+}
+
+func NewSubscriptionHandler(subscriptionService services.ISubscriptionService, jwtMiddleware middlewares.IJWTMiddleware) *subscriptionHandler {
+	return &subscriptionHandler{subscriptionService, jwtMiddleware}
+}
+
+func (h *subscriptionHandler) SetupRoutes(r *gin.Engine) {
+	subscriptionRoutes := r.Group("/report/subscriptions", h.jwtMiddleware.RequireScope("report:subscribe"))
+	{
+		subscriptionRoutes.POST("", h.Create)
+		subscriptionRoutes.GET("", h.List)
+		subscriptionRoutes.GET("/:id", h.Get)
+		subscriptionRoutes.PUT("/:id", h.Update)
+		subscriptionRoutes.DELETE("/:id", h.Delete)
+	}
+}
+
+// Create godoc
+// @Summary Create report subscription
+// @Description Subscribes the authenticated user to a scheduled report
+// @Tags subscription
+// @Accept json
+// @Produce json
+// @Param body body dto.SubscriptionRequest true "Subscription definition"
+// @Success 201 {object} dto.APIResponse{data=entities.ReportSubscription} "Subscription created successfully"
+// @Failure 400 {object} dto.APIResponse "Invalid subscription"
+// @Failure 500 {object} dto.APIResponse "Failed to save subscription"
+// @Security BearerAuth
+// @Router /report/subscriptions [post]
+func (h *subscriptionHandler) Create(c *gin.Context) {
+	var req dto.SubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	subscription, err := h.subscriptionService.Create(c.Request.Context(), c.GetString("userId"), req)
+	if err != nil {
+		h.abortWithError(c, err, "Failed to create subscription")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.APIResponse{
+		Success: true,
+		Code:    "SUBSCRIPTION_CREATED",
+		Message: "Subscription created successfully",
+		Data:    subscription,
+	})
+}
+
+// List godoc
+// @Summary List report subscriptions
+// @Description Lists the report subscriptions owned by the authenticated user
+// @Tags subscription
+// @Produce json
+// @Success 200 {object} dto.APIResponse{data=[]entities.ReportSubscription} "Subscriptions retrieved successfully"
+// @Failure 500 {object} dto.APIResponse "Failed to retrieve subscriptions"
+// @Security BearerAuth
+// @Router /report/subscriptions [get]
+func (h *subscriptionHandler) List(c *gin.Context) {
+	subscriptions, err := h.subscriptionService.List(c.Request.Context(), c.GetString("userId"))
+	if err != nil {
+		h.abortWithError(c, err, "Failed to retrieve subscriptions")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Code:    "SUBSCRIPTIONS_RETRIEVED",
+		Message: "Subscriptions retrieved successfully",
+		Data:    subscriptions,
+	})
+}
+
+// Get godoc
+// @Summary Get report subscription
+// @Description Returns a report subscription owned by the authenticated user
+// @Tags subscription
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 200 {object} dto.APIResponse{data=entities.ReportSubscription} "Subscription retrieved successfully"
+// @Failure 404 {object} dto.APIResponse "Subscription not found"
+// @Failure 500 {object} dto.APIResponse "Failed to retrieve subscription"
+// @Security BearerAuth
+// @Router /report/subscriptions/{id} [get]
+func (h *subscriptionHandler) Get(c *gin.Context) {
+	subscription, err := h.subscriptionService.Get(c.Request.Context(), c.GetString("userId"), c.Param("id"))
+	if err != nil {
+		h.abortWithError(c, err, "Failed to retrieve subscription")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Code:    "SUBSCRIPTION_RETRIEVED",
+		Message: "Subscription retrieved successfully",
+		Data:    subscription,
+	})
+}
+
+// Update godoc
+// @Summary Update report subscription
+// @Description Replaces a report subscription owned by the authenticated user
+// @Tags subscription
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param body body dto.SubscriptionRequest true "Subscription definition"
+// @Success 200 {object} dto.APIResponse{data=entities.ReportSubscription} "Subscription updated successfully"
+// @Failure 400 {object} dto.APIResponse "Invalid subscription"
+// @Failure 404 {object} dto.APIResponse "Subscription not found"
+// @Failure 500 {object} dto.APIResponse "Failed to save subscription"
+// @Security BearerAuth
+// @Router /report/subscriptions/{id} [put]
+func (h *subscriptionHandler) Update(c *gin.Context) {
+	var req dto.SubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid request data",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	subscription, err := h.subscriptionService.Update(c.Request.Context(), c.GetString("userId"), c.Param("id"), req)
+	if err != nil {
+		h.abortWithError(c, err, "Failed to update subscription")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Code:    "SUBSCRIPTION_UPDATED",
+		Message: "Subscription updated successfully",
+		Data:    subscription,
+	})
+}
+
+// Delete godoc
+// @Summary Delete report subscription
+// @Description Removes a report subscription owned by the authenticated user
+// @Tags subscription
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 200 {object} dto.APIResponse "Subscription deleted successfully"
+// @Failure 404 {object} dto.APIResponse "Subscription not found"
+// @Failure 500 {object} dto.APIResponse "Failed to delete subscription"
+// @Security BearerAuth
+// @Router /report/subscriptions/{id} [delete]
+func (h *subscriptionHandler) Delete(c *gin.Context) {
+	if err := h.subscriptionService.Delete(c.Request.Context(), c.GetString("userId"), c.Param("id")); err != nil {
+		h.abortWithError(c, err, "Failed to delete subscription")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.APIResponse{
+		Success: true,
+		Code:    "SUBSCRIPTION_DELETED",
+		Message: "Subscription deleted successfully",
+	})
+}
+
+func (h *subscriptionHandler) abortWithError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrSubscriptionNotFound):
+		c.JSON(http.StatusNotFound, dto.APIResponse{
+			Success: false,
+			Code:    "NOT_FOUND",
+			Message: "Subscription not found",
+			Error:   err.Error(),
+		})
+	case errors.Is(err, services.ErrInvalidSubscription):
+		c.JSON(http.StatusBadRequest, dto.APIResponse{
+			Success: false,
+			Code:    "BAD_REQUEST",
+			Message: "Invalid subscription",
+			Error:   err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.APIResponse{
+			Success: false,
+			Code:    "INTERNAL_SERVER_ERROR",
+			Message: message,
+			Error:   err.Error(),
+		})
+	}
+}
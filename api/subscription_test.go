@@ -0,0 +1,190 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/vnFuhung2903/vcs-report-service/dto"
+	"github.com/vnFuhung2903/vcs-report-service/entities"
+	"github.com/vnFuhung2903/vcs-report-service/mocks/middlewares"
+	"github.com/vnFuhung2903/vcs-report-service/mocks/services"
+	usecases "github.com/vnFuhung2903/vcs-report-service/usecases/services"
+)
+
+type SubscriptionHandlerSuite struct {
+	suite.Suite
+	ctrl                    *gomock.Controller
+	mockSubscriptionService *services.MockISubscriptionService
+	mockJWTMiddleware       *middlewares.MockIJWTMiddleware
+	router                  *gin.Engine
+	request                 dto.SubscriptionRequest
+}
+
+func (s *SubscriptionHandlerSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.mockSubscriptionService = services.NewMockISubscriptionService(s.ctrl)
+	s.mockJWTMiddleware = middlewares.NewMockIJWTMiddleware(s.ctrl)
+
+	s.mockJWTMiddleware.EXPECT().
+		RequireScope("report:subscribe").
+		Return(func(c *gin.Context) {
+			c.Set("userId", "user-1")
+			c.Next()
+		}).
+		AnyTimes()
+
+	gin.SetMode(gin.TestMode)
+	s.router = gin.New()
+	NewSubscriptionHandler(s.mockSubscriptionService, s.mockJWTMiddleware).SetupRoutes(s.router)
+
+	s.request = dto.SubscriptionRequest{
+		Name:       "weekday digest",
+		Schedule:   "0 8 * * MON-FRI",
+		Channel:    "slack",
+		Recipients: []string{"https://hooks.slack.com/services/T000/B000/XXX"},
+	}
+}
+
+func (s *SubscriptionHandlerSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func TestSubscriptionHandlerSuite(t *testing.T) {
+	suite.Run(t, new(SubscriptionHandlerSuite))
+}
+
+func (s *SubscriptionHandlerSuite) serve(method string, path string, body interface{}) (*httptest.ResponseRecorder, dto.APIResponse) {
+	var payload bytes.Buffer
+	if body != nil {
+		s.Require().NoError(json.NewEncoder(&payload).Encode(body))
+	}
+
+	req := httptest.NewRequest(method, path, &payload)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+
+	var response dto.APIResponse
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	return w, response
+}
+
+func (s *SubscriptionHandlerSuite) TestCreate() {
+	s.mockSubscriptionService.EXPECT().
+		Create(gomock.Any(), "user-1", s.request).
+		Return(entities.ReportSubscription{Id: "sub-1", Owner: "user-1"}, nil)
+
+	w, response := s.serve(http.MethodPost, "/report/subscriptions", s.request)
+	s.Equal(http.StatusCreated, w.Code)
+	s.True(response.Success)
+	s.Equal("SUBSCRIPTION_CREATED", response.Code)
+	s.Equal("sub-1", response.Data.(map[string]interface{})["id"])
+}
+
+func (s *SubscriptionHandlerSuite) TestCreateInvalidBody() {
+	s.request.Channel = "sms"
+
+	w, response := s.serve(http.MethodPost, "/report/subscriptions", s.request)
+	s.Equal(http.StatusBadRequest, w.Code)
+	s.NotEmpty(response.Error)
+
+	s.request.Channel = "slack"
+	s.request.Recipients = nil
+
+	w, _ = s.serve(http.MethodPost, "/report/subscriptions", s.request)
+	s.Equal(http.StatusBadRequest, w.Code)
+}
+
+func (s *SubscriptionHandlerSuite) TestCreateInvalidSubscription() {
+	s.mockSubscriptionService.EXPECT().
+		Create(gomock.Any(), "user-1", s.request).
+		Return(entities.ReportSubscription{}, fmt.Errorf("%w: bad schedule", usecases.ErrInvalidSubscription))
+
+	w, response := s.serve(http.MethodPost, "/report/subscriptions", s.request)
+	s.Equal(http.StatusBadRequest, w.Code)
+	s.Equal("Invalid subscription", response.Message)
+}
+
+func (s *SubscriptionHandlerSuite) TestList() {
+	s.mockSubscriptionService.EXPECT().
+		List(gomock.Any(), "user-1").
+		Return([]entities.ReportSubscription{{Id: "sub-1"}, {Id: "sub-2"}}, nil)
+
+	w, response := s.serve(http.MethodGet, "/report/subscriptions", nil)
+	s.Equal(http.StatusOK, w.Code)
+	s.Equal("SUBSCRIPTIONS_RETRIEVED", response.Code)
+	s.Len(response.Data, 2)
+}
+
+func (s *SubscriptionHandlerSuite) TestListError() {
+	s.mockSubscriptionService.EXPECT().
+		List(gomock.Any(), "user-1").
+		Return(nil, errors.New("redis connection failed"))
+
+	w, response := s.serve(http.MethodGet, "/report/subscriptions", nil)
+	s.Equal(http.StatusInternalServerError, w.Code)
+	s.Equal("redis connection failed", response.Error)
+}
+
+func (s *SubscriptionHandlerSuite) TestGet() {
+	s.mockSubscriptionService.EXPECT().
+		Get(gomock.Any(), "user-1", "sub-1").
+		Return(entities.ReportSubscription{Id: "sub-1"}, nil)
+
+	w, response := s.serve(http.MethodGet, "/report/subscriptions/sub-1", nil)
+	s.Equal(http.StatusOK, w.Code)
+	s.Equal("SUBSCRIPTION_RETRIEVED", response.Code)
+}
+
+func (s *SubscriptionHandlerSuite) TestGetNotFound() {
+	s.mockSubscriptionService.EXPECT().
+		Get(gomock.Any(), "user-1", "missing").
+		Return(entities.ReportSubscription{}, usecases.ErrSubscriptionNotFound)
+
+	w, response := s.serve(http.MethodGet, "/report/subscriptions/missing", nil)
+	s.Equal(http.StatusNotFound, w.Code)
+	s.Equal("NOT_FOUND", response.Code)
+}
+
+func (s *SubscriptionHandlerSuite) TestUpdate() {
+	s.mockSubscriptionService.EXPECT().
+		Update(gomock.Any(), "user-1", "sub-1", s.request).
+		Return(entities.ReportSubscription{Id: "sub-1"}, nil)
+
+	w, response := s.serve(http.MethodPut, "/report/subscriptions/sub-1", s.request)
+	s.Equal(http.StatusOK, w.Code)
+	s.Equal("SUBSCRIPTION_UPDATED", response.Code)
+}
+
+func (s *SubscriptionHandlerSuite) TestUpdateInvalidBody() {
+	w, _ := s.serve(http.MethodPut, "/report/subscriptions/sub-1", map[string]string{"name": "digest"})
+	s.Equal(http.StatusBadRequest, w.Code)
+}
+
+func (s *SubscriptionHandlerSuite) TestDelete() {
+	s.mockSubscriptionService.EXPECT().
+		Delete(gomock.Any(), "user-1", "sub-1").
+		Return(nil)
+
+	w, response := s.serve(http.MethodDelete, "/report/subscriptions/sub-1", nil)
+	s.Equal(http.StatusOK, w.Code)
+	s.Equal("SUBSCRIPTION_DELETED", response.Code)
+}
+
+func (s *SubscriptionHandlerSuite) TestDeleteNotFound() {
+	s.mockSubscriptionService.EXPECT().
+		Delete(gomock.Any(), "user-1", "sub-1").
+		Return(usecases.ErrSubscriptionNotFound)
+
+	w, _ := s.serve(http.MethodDelete, "/report/subscriptions/sub-1", nil)
+	s.Equal(http.StatusNotFound, w.Code)
+}
@@ -20,7 +20,10 @@ import (
 	"github.com/vnFuhung2903/vcs-report-service/pkg/env"
 	"github.com/vnFuhung2903/vcs-report-service/pkg/logger"
 	"github.com/vnFuhung2903/vcs-report-service/pkg/middlewares"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/i18n"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/notifiers"
 	"github.com/vnFuhung2903/vcs-report-service/usecases/services"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/templates"
 	"github.com/vnFuhung2903/vcs-report-service/usecases/workers"
 	"go.uber.org/zap"
 )
@@ -53,29 +56,97 @@ func main() {
 	redisRawClient := databases.NewRedisFactory(env.RedisEnv).ConnectRedis()
 	defer redisRawClient.Close()
 	redisClient := interfaces.NewRedisClient(redisRawClient)
+	redisLock := interfaces.NewRedisLock(redisRawClient)
+	idempotencyStore := interfaces.NewIdempotencyStore(redisRawClient)
+
+	mailDialer := interfaces.NewMailDialer(env.GomailEnv)
 
 	jwtMiddleware := middlewares.NewJWTMiddleware(env.AuthEnv)
+	idempotencyMiddleware := middlewares.NewIdempotencyMiddleware(idempotencyStore, env.IdempotencyEnv)
+
+	templateRegistry, err := templates.NewTemplateRegistry(env.ReportEnv.TemplateDir)
+	if err != nil {
+		log.Fatalf("Failed to load report templates: %v", err)
+	}
+
+	localizer, err := i18n.NewLocalizer(env.ReportEnv.Locale, env.ReportEnv.Timezone)
+	if err != nil {
+		log.Fatalf("Failed to load report locale: %v", err)
+	}
+
+	reportService := services.NewReportService(esClient, redisClient, mailDialer, logger, templateRegistry, env.GomailEnv, env.ReportEnv, env.RetryEnv)
+	httpClient := &http.Client{
+		Timeout: 10 * time.Second,
+		// Targets are checked against the allowed hosts before delivery, so a
+		// redirect elsewhere is never followed.
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	notificationService := notifiers.NewNotificationService(map[string]notifiers.INotifier{
+		notifiers.ChannelEmail:   notifiers.NewEmailNotifier(reportService),
+		notifiers.ChannelSlack:   notifiers.NewSlackNotifier(httpClient, env.RetryEnv, localizer),
+		notifiers.ChannelTeams:   notifiers.NewTeamsNotifier(httpClient, env.RetryEnv, localizer),
+		notifiers.ChannelWebhook: notifiers.NewWebhookNotifier(httpClient, env.RetryEnv, localizer),
+	}, logger)
+	runService := services.NewReportRunService(redisClient, logger, env.ReportEnv)
+	reportHandler := api.NewReportHandler(reportService, notificationService, runService, jwtMiddleware, idempotencyMiddleware, env.ReportEnv, logger)
+	runHandler := api.NewReportRunHandler(runService, jwtMiddleware)
+	deadLetterService := services.NewDeadLetterService(redisClient, logger, env.ReportEnv)
+	deadLetterHandler := api.NewDeadLetterHandler(deadLetterService, reportService, notificationService, runService, jwtMiddleware, idempotencyMiddleware, logger)
+	jobService := services.NewReportJobService(redisClient, logger, env.ReportEnv)
+	jobHandler := api.NewReportJobHandler(jobService, jwtMiddleware, idempotencyMiddleware, env.ReportEnv)
+
+	subscriptionService := services.NewSubscriptionService(redisClient, logger, templateRegistry, env.ReportEnv)
+	subscriptionHandler := api.NewSubscriptionHandler(subscriptionService, jwtMiddleware)
 
-	reportService := services.NewReportService(esClient, redisClient, logger, env.GomailEnv)
-	reportHandler := api.NewReportHandler(reportService, jwtMiddleware)
+	reportSchedules := make([]workers.ReportSchedule, 0, len(env.ReportEnv.Schedules))
+	for _, scheduleEnv := range env.ReportEnv.Schedules {
+		reportSchedule, err := workers.NewReportSchedule(scheduleEnv)
+		if err != nil {
+			log.Fatalf("Failed to load report schedule %s: %v", scheduleEnv.Name, err)
+		}
+		if !templateRegistry.Has(scheduleEnv.Template) {
+			log.Fatalf("Failed to load report schedule %s: report template %q does not exist", scheduleEnv.Name, scheduleEnv.Template)
+		}
+		reportSchedules = append(reportSchedules, reportSchedule)
+	}
+	if len(reportSchedules) == 0 {
+		logger.Warn("no report schedules configured, scheduled reports are disabled")
+	}
 
 	reportWorker := workers.NewReportkWorker(
 		reportService,
-		"hung29032004@gmail.com",
+		subscriptionService,
+		runService,
+		deadLetterService,
+		notificationService,
+		redisLock,
 		logger,
-		24*time.Hour,
+		reportSchedules,
+		env.ReportEnv,
 	)
 	reportWorker.Start()
 	defer reportWorker.Stop()
 
+	jobWorker := workers.NewReportJobWorker(jobService, reportService, notificationService, runService, redisLock, logger, env.ReportEnv)
+	if err := jobWorker.Start(); err != nil {
+		log.Fatalf("Failed to start report job worker: %v", err)
+	}
+	defer jobWorker.Stop()
+
 	r := gin.Default()
 	r.Use(cors.New(cors.Config{
 		AllowOrigins: []string{"http://report.localhost", "http://swagger.localhost", "http://frontend.localhost"},
 		AllowMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders: []string{"Origin", "Content-Type", "Authorization"},
+		AllowHeaders: []string{"Origin", "Content-Type", "Authorization", middlewares.IdempotencyKeyHeader},
 	}))
 
 	reportHandler.SetupRoutes(r)
+	subscriptionHandler.SetupRoutes(r)
+	runHandler.SetupRoutes(r)
+	deadLetterHandler.SetupRoutes(r)
+	jobHandler.SetupRoutes(r)
 	r.GET("/swagger/*any", swagger.WrapHandler(swaggerFiles.Handler))
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
@@ -0,0 +1,15 @@
+package dto
+
+import "github.com/vnFuhung2903/vcs-report-service/entities"
+
+type DeadLetterListRequest struct {
+	Cursor uint64 `form:"cursor"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=500"`
+}
+
+// DeadLetterPage holds one page of dead letters and the cursor of the next
+// one, which is 0 after the last page.
+type DeadLetterPage struct {
+	Letters    []entities.DeadLetter `json:"letters"`
+	NextCursor uint64                `json:"next_cursor"`
+}
@@ -2,19 +2,76 @@ package dto
 
 import (
 	"time"
+
+	"github.com/vnFuhung2903/vcs-report-service/entities"
 )
 
 type ReportRequest struct {
-	StartTime string `form:"start_time" binding:"required"`
-	EndTime   string `form:"end_time"`
-	Email     string `form:"email" binding:"required,email"`
+	Range            string   `form:"range"`
+	StartTime        string   `form:"start_time" binding:"required_without=Range"`
+	EndTime          string   `form:"end_time"`
+	Timezone         string   `form:"tz"`
+	ContainerIds     []string `form:"container_id"`
+	ContainerPattern string   `form:"container_pattern"`
+	Labels           string   `form:"labels"`
+	Email            string   `form:"email" binding:"required,email"`
+}
+
+type NotifyRequest struct {
+	Range            string   `form:"range"`
+	StartTime        string   `form:"start_time" binding:"required_without=Range"`
+	EndTime          string   `form:"end_time"`
+	Timezone         string   `form:"tz"`
+	ContainerIds     []string `form:"container_id"`
+	ContainerPattern string   `form:"container_pattern"`
+	Labels           string   `form:"labels"`
+	Channel          string   `form:"channel" binding:"required,oneof=email slack teams webhook"`
+	Target           string   `form:"target" binding:"required"`
+}
+
+type ReportQueryRequest struct {
+	Range            string   `form:"range"`
+	StartTime        string   `form:"start_time" binding:"required_without=Range"`
+	EndTime          string   `form:"end_time"`
+	Timezone         string   `form:"tz"`
+	ContainerIds     []string `form:"container_id"`
+	ContainerPattern string   `form:"container_pattern"`
+	Labels           string   `form:"labels"`
+	Format           string   `form:"format" binding:"omitempty,oneof=json csv"`
 }
 
 type ReportResponse struct {
-	ContainerCount    int       `json:"container_count"`
-	ContainerOnCount  int       `json:"container_on_count"`
-	ContainerOffCount int       `json:"container_off_count"`
-	TotalUptime       float64   `json:"total_uptime"`
-	StartTime         time.Time `json:"start_time"`
-	EndTime           time.Time `json:"end_time"`
+	ContainerCount    int               `json:"container_count"`
+	ContainerOnCount  int               `json:"container_on_count"`
+	ContainerOffCount int               `json:"container_off_count"`
+	TotalUptime       float64           `json:"total_uptime"`
+	Availability      float64           `json:"availability"`
+	SLATarget         float64           `json:"sla_target"`
+	SLABreachedCount  int               `json:"sla_breached_count"`
+	StartTime         time.Time         `json:"start_time"`
+	EndTime           time.Time         `json:"end_time"`
+	Containers        []ContainerReport `json:"containers"`
+	Template          string            `json:"template,omitempty"`
+	// Aggregated marks reports computed by Elasticsearch aggregations, which
+	// carry neither transitions nor timelines.
+	Aggregated bool `json:"aggregated,omitempty"`
+}
+
+type ContainerReport struct {
+	ContainerId   string                   `json:"container_id"`
+	Status        entities.ContainerStatus `json:"status"`
+	UptimeHours   float64                  `json:"uptime_hours"`
+	DowntimeHours float64                  `json:"downtime_hours"`
+	Transitions   int                      `json:"transitions"`
+	Availability  float64                  `json:"availability"`
+	SLABreached   bool                     `json:"sla_breached"`
+	FirstSeen     time.Time                `json:"first_seen"`
+	LastSeen      time.Time                `json:"last_seen"`
+	Timeline      []StatusSegment          `json:"timeline,omitempty"`
+}
+
+type StatusSegment struct {
+	Status entities.ContainerStatus `json:"status"`
+	Start  time.Time                `json:"start"`
+	End    time.Time                `json:"end"`
 }
@@ -0,0 +1,13 @@
+package dto
+
+import "github.com/vnFuhung2903/vcs-report-service/entities"
+
+type ReportJobRequest struct {
+	Range     string                   `json:"range"`
+	StartTime string                   `json:"start_time" binding:"required_without=Range"`
+	EndTime   string                   `json:"end_time"`
+	Timezone  string                   `json:"tz"`
+	Channel   string                   `json:"channel" binding:"required,oneof=email slack teams webhook"`
+	Target    string                   `json:"target" binding:"required"`
+	Filter    entities.ContainerFilter `json:"filter"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+type RunListRequest struct {
+	Limit    int    `form:"limit" binding:"omitempty,min=1,max=500"`
+	Status   string `form:"status" binding:"omitempty,oneof=running succeeded partial failed"`
+	Trigger  string `form:"trigger" binding:"omitempty,oneof=schedule catchup replay api"`
+	Schedule string `form:"schedule"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+import "github.com/vnFuhung2903/vcs-report-service/entities"
+
+type SubscriptionRequest struct {
+	Name       string                   `json:"name" binding:"required"`
+	Schedule   string                   `json:"schedule" binding:"required"`
+	Timezone   string                   `json:"timezone"`
+	Window     string                   `json:"window"`
+	Channel    string                   `json:"channel" binding:"required,oneof=email slack teams webhook"`
+	Recipients []string                 `json:"recipients" binding:"required,min=1,dive,required"`
+	Template   string                   `json:"template"`
+	Filter     entities.ContainerFilter `json:"filter"`
+}
@@ -1,5 +1,13 @@
 package entities
 
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
 type ContainerStatus string
 
 const (
@@ -7,7 +15,60 @@ const (
 	ContainerOff ContainerStatus = "OFF"
 )
 
+var ErrInvalidContainerFilter = errors.New("invalid container filter")
+
 type ContainerWithStatus struct {
-	ContainerId string          `json:"container_id"`
-	Status      ContainerStatus `json:"status"`
+	ContainerId string            `json:"container_id"`
+	Status      ContainerStatus   `json:"status"`
+	Labels      map[string]string `json:"labels,omitempty"`
+}
+
+// ContainerFilter scopes a report to part of the fleet. A container has to
+// satisfy every criterion that is set, so the zero filter matches them all.
+// Pattern is an ID prefix, or a glob such as "api-*-eu" when it contains
+// wildcards. Labels only match containers whose metadata carries them.
+type ContainerFilter struct {
+	Ids     []string          `json:"ids,omitempty"`
+	Pattern string            `json:"pattern,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+func (f ContainerFilter) IsZero() bool {
+	return len(f.Ids) == 0 && f.Pattern == "" && len(f.Labels) == 0
+}
+
+func (f ContainerFilter) Validate() error {
+	if _, err := path.Match(f.Pattern, ""); err != nil {
+		return fmt.Errorf("%w: pattern %q: %v", ErrInvalidContainerFilter, f.Pattern, err)
+	}
+	if slices.Contains(f.Ids, "") {
+		return fmt.Errorf("%w: empty container id", ErrInvalidContainerFilter)
+	}
+	for key := range f.Labels {
+		if key == "" {
+			return fmt.Errorf("%w: empty label key", ErrInvalidContainerFilter)
+		}
+	}
+	return nil
+}
+
+func (f ContainerFilter) Matches(container ContainerWithStatus) bool {
+	if len(f.Ids) > 0 && !slices.Contains(f.Ids, container.ContainerId) {
+		return false
+	}
+	if f.Pattern != "" {
+		if strings.ContainsAny(f.Pattern, `*?[\`) {
+			if matched, _ := path.Match(f.Pattern, container.ContainerId); !matched {
+				return false
+			}
+		} else if !strings.HasPrefix(container.ContainerId, f.Pattern) {
+			return false
+		}
+	}
+	for key, value := range f.Labels {
+		if label, ok := container.Labels[key]; !ok || label != value {
+			return false
+		}
+	}
+	return true
 }
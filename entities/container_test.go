@@ -0,0 +1,70 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ContainerFilterSuite struct {
+	suite.Suite
+	containers []ContainerWithStatus
+}
+
+func (s *ContainerFilterSuite) SetupTest() {
+	s.containers = []ContainerWithStatus{
+		{ContainerId: "api-eu-1", Status: ContainerOn, Labels: map[string]string{"team": "payments", "env": "prod"}},
+		{ContainerId: "api-us-1", Status: ContainerOn, Labels: map[string]string{"team": "search", "env": "prod"}},
+		{ContainerId: "worker-eu-1", Status: ContainerOff, Labels: map[string]string{"team": "payments"}},
+		{ContainerId: "api-eu-2", Status: ContainerOff},
+	}
+}
+
+func TestContainerFilterSuite(t *testing.T) {
+	suite.Run(t, new(ContainerFilterSuite))
+}
+
+func (s *ContainerFilterSuite) matching(filter ContainerFilter) []string {
+	var ids []string
+	for _, container := range s.containers {
+		if filter.Matches(container) {
+			ids = append(ids, container.ContainerId)
+		}
+	}
+	return ids
+}
+
+func (s *ContainerFilterSuite) TestMatches() {
+	tests := []struct {
+		name     string
+		filter   ContainerFilter
+		expected []string
+	}{
+		{"zero", ContainerFilter{}, []string{"api-eu-1", "api-us-1", "worker-eu-1", "api-eu-2"}},
+		{"ids", ContainerFilter{Ids: []string{"api-us-1", "worker-eu-1", "unknown"}}, []string{"api-us-1", "worker-eu-1"}},
+		{"prefix", ContainerFilter{Pattern: "api-eu"}, []string{"api-eu-1", "api-eu-2"}},
+		{"glob", ContainerFilter{Pattern: "*-eu-?"}, []string{"api-eu-1", "worker-eu-1", "api-eu-2"}},
+		{"labels", ContainerFilter{Labels: map[string]string{"team": "payments"}}, []string{"api-eu-1", "worker-eu-1"}},
+		{"combined", ContainerFilter{Pattern: "api-", Labels: map[string]string{"team": "payments", "env": "prod"}}, []string{"api-eu-1"}},
+		{"none", ContainerFilter{Ids: []string{"api-us-1"}, Pattern: "worker-"}, nil},
+	}
+
+	for _, test := range tests {
+		s.Equal(test.expected, s.matching(test.filter), test.name)
+	}
+}
+
+func (s *ContainerFilterSuite) TestIsZero() {
+	s.True(ContainerFilter{}.IsZero())
+	s.True(ContainerFilter{Ids: []string{}, Labels: map[string]string{}}.IsZero())
+	s.False(ContainerFilter{Pattern: "api-"}.IsZero())
+}
+
+func (s *ContainerFilterSuite) TestValidate() {
+	s.NoError(ContainerFilter{}.Validate())
+	s.NoError(ContainerFilter{Ids: []string{"api-eu-1"}, Pattern: "api-[a-z]*", Labels: map[string]string{"team": ""}}.Validate())
+
+	s.ErrorIs(ContainerFilter{Pattern: "api-["}.Validate(), ErrInvalidContainerFilter)
+	s.ErrorIs(ContainerFilter{Ids: []string{""}}.Validate(), ErrInvalidContainerFilter)
+	s.ErrorIs(ContainerFilter{Labels: map[string]string{"": "payments"}}.Validate(), ErrInvalidContainerFilter)
+}
@@ -0,0 +1,20 @@
+package entities
+
+import "time"
+
+type DeadLetter struct {
+	Id        string          `json:"id"`
+	RunId     string          `json:"run_id,omitempty"`
+	Schedule  string          `json:"schedule,omitempty"`
+	Owner     string          `json:"owner,omitempty"`
+	Channel   string          `json:"channel"`
+	Target    string          `json:"target"`
+	Template  string          `json:"template,omitempty"`
+	Filter    ContainerFilter `json:"filter,omitzero"`
+	StartTime time.Time       `json:"start_time"`
+	EndTime   time.Time       `json:"end_time"`
+	Error     string          `json:"error"`
+	Attempts  int             `json:"attempts"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
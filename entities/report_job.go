@@ -0,0 +1,28 @@
+package entities
+
+import "time"
+
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+)
+
+type ReportJob struct {
+	Id         string          `json:"id"`
+	Owner      string          `json:"owner"`
+	Channel    string          `json:"channel"`
+	Target     string          `json:"target"`
+	Filter     ContainerFilter `json:"filter,omitzero"`
+	StartTime  time.Time       `json:"start_time"`
+	EndTime    time.Time       `json:"end_time"`
+	Status     JobStatus       `json:"status"`
+	Result     *ReportRun      `json:"result,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	StartedAt  time.Time       `json:"started_at"`
+	FinishedAt time.Time       `json:"finished_at"`
+}
@@ -0,0 +1,54 @@
+package entities
+
+import "time"
+
+type RunTrigger string
+
+const (
+	RunTriggerSchedule RunTrigger = "schedule"
+	RunTriggerCatchUp  RunTrigger = "catchup"
+	RunTriggerReplay   RunTrigger = "replay"
+	RunTriggerAPI      RunTrigger = "api"
+)
+
+// RunOwnerSystem owns the runs of the schedules loaded from the
+// configuration, which no user created.
+const RunOwnerSystem = "system"
+
+type RunStatus string
+
+const (
+	RunRunning   RunStatus = "running"
+	RunSucceeded RunStatus = "succeeded"
+	RunPartial   RunStatus = "partial"
+	RunFailed    RunStatus = "failed"
+)
+
+type ReportRun struct {
+	Id               string          `json:"id"`
+	Trigger          RunTrigger      `json:"trigger"`
+	Owner            string          `json:"owner,omitempty"`
+	Schedule         string          `json:"schedule,omitempty"`
+	Channel          string          `json:"channel"`
+	Recipients       []string        `json:"recipients"`
+	Template         string          `json:"template,omitempty"`
+	Filter           ContainerFilter `json:"filter,omitzero"`
+	FailedRecipients []string        `json:"failed_recipients,omitempty"`
+	StartTime        time.Time       `json:"start_time"`
+	EndTime          time.Time       `json:"end_time"`
+	Status           RunStatus       `json:"status"`
+	Stats            *ReportRunStats `json:"stats,omitempty"`
+	Error            string          `json:"error,omitempty"`
+	StartedAt        time.Time       `json:"started_at"`
+	FinishedAt       time.Time       `json:"finished_at"`
+	DurationMs       int64           `json:"duration_ms"`
+}
+
+type ReportRunStats struct {
+	ContainerCount    int     `json:"container_count"`
+	ContainerOnCount  int     `json:"container_on_count"`
+	ContainerOffCount int     `json:"container_off_count"`
+	TotalUptime       float64 `json:"total_uptime"`
+	Availability      float64 `json:"availability"`
+	SLABreachedCount  int     `json:"sla_breached_count"`
+}
@@ -0,0 +1,18 @@
+package entities
+
+import "time"
+
+type ReportSubscription struct {
+	Id         string          `json:"id"`
+	Owner      string          `json:"owner"`
+	Name       string          `json:"name"`
+	Schedule   string          `json:"schedule"`
+	Timezone   string          `json:"timezone"`
+	Window     string          `json:"window"`
+	Channel    string          `json:"channel"`
+	Recipients []string        `json:"recipients"`
+	Template   string          `json:"template,omitempty"`
+	Filter     ContainerFilter `json:"filter,omitzero"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
@@ -2,6 +2,9 @@ package interfaces
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
 
 	"github.com/elastic/go-elasticsearch/esapi"
 	"github.com/elastic/go-elasticsearch/v8"
@@ -9,6 +12,8 @@ import (
 
 type IElasticsearchClient interface {
 	Do(ctx context.Context, req esapi.Request) (*esapi.Response, error)
+	OpenPointInTime(ctx context.Context, index string, keepAlive string) (string, error)
+	ClosePointInTime(ctx context.Context, id string) error
 }
 
 type elasticsearchClient struct {
@@ -22,3 +27,44 @@ func NewElasticsearchClient(client *elasticsearch.Client) *elasticsearchClient {
 func (c *elasticsearchClient) Do(ctx context.Context, req esapi.Request) (*esapi.Response, error) {
 	return req.Do(ctx, c.client)
 }
+
+func (c *elasticsearchClient) OpenPointInTime(ctx context.Context, index string, keepAlive string) (string, error) {
+	res, err := c.client.OpenPointInTime([]string{index}, keepAlive, c.client.OpenPointInTime.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return "", fmt.Errorf("failed to open point in time: %s", res.Status())
+	}
+
+	var parsed struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&parsed); err != nil {
+		return "", err
+	}
+	return parsed.ID, nil
+}
+
+func (c *elasticsearchClient) ClosePointInTime(ctx context.Context, id string) error {
+	body, err := json.Marshal(map[string]string{"id": id})
+	if err != nil {
+		return err
+	}
+
+	res, err := c.client.ClosePointInTime(
+		c.client.ClosePointInTime.WithBody(strings.NewReader(string(body))),
+		c.client.ClosePointInTime.WithContext(ctx),
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("failed to close point in time: %s", res.Status())
+	}
+	return nil
+}
@@ -2,6 +2,9 @@ package interfaces
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/elastic/go-elasticsearch/esapi"
@@ -21,3 +24,58 @@ func TestElasticsearchClient(t *testing.T) {
 	_, err = esClient.Do(context.Background(), req)
 	assert.NoError(t, err)
 }
+
+func newFakeElasticsearchClient(t *testing.T, handler http.HandlerFunc) IElasticsearchClient {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		w.Header().Set("Content-Type", "application/json")
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	es, err := elasticsearch.NewClient(elasticsearch.Config{Addresses: []string{server.URL}})
+	assert.NoError(t, err)
+	return NewElasticsearchClient(es)
+}
+
+func TestElasticsearchClientPointInTime(t *testing.T) {
+	var closedId string
+	esClient := newFakeElasticsearchClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/sms_container/_pit":
+			assert.Equal(t, "1m", r.URL.Query().Get("keep_alive"))
+			_, _ = w.Write([]byte(`{"id":"pit-id"}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/_pit":
+			var body struct {
+				ID string `json:"id"`
+			}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			closedId = body.ID
+			_, _ = w.Write([]byte(`{"succeeded":true,"num_freed":1}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	id, err := esClient.OpenPointInTime(context.Background(), "sms_container", "1m")
+	assert.NoError(t, err)
+	assert.Equal(t, "pit-id", id)
+
+	err = esClient.ClosePointInTime(context.Background(), id)
+	assert.NoError(t, err)
+	assert.Equal(t, "pit-id", closedId)
+}
+
+func TestElasticsearchClientPointInTimeError(t *testing.T) {
+	esClient := newFakeElasticsearchClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":{"type":"index_not_found_exception"},"status":404}`))
+	})
+
+	id, err := esClient.OpenPointInTime(context.Background(), "sms_container", "1m")
+	assert.Error(t, err)
+	assert.Empty(t, id)
+
+	err = esClient.ClosePointInTime(context.Background(), "pit-id")
+	assert.Error(t, err)
+}
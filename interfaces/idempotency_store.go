@@ -0,0 +1,133 @@
+package interfaces
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// reserveScript stores the pending record only when the key is unused and
+// otherwise returns the record already stored under it.
+var reserveScript = redis.NewScript(`
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return false
+end
+return redis.call("GET", KEYS[1])
+`)
+
+// ownReservationLua guards the scripts below: they only act while the key
+// still holds the reservation made with the token in ARGV[1].
+const ownReservationLua = `
+local stored = redis.call("GET", KEYS[1])
+if not stored or cjson.decode(stored).token ~= ARGV[1] then
+	return 0
+end
+`
+
+var renewReservationScript = redis.NewScript(ownReservationLua + `
+return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+`)
+
+var completeScript = redis.NewScript(ownReservationLua + `
+redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3])
+return 1
+`)
+
+var releaseReservationScript = redis.NewScript(ownReservationLua + `
+return redis.call("DEL", KEYS[1])
+`)
+
+var ErrReservationLost = errors.New("idempotency key reservation is no longer held")
+
+// IdempotencyRecord is what is kept under an idempotency key: the fingerprint
+// of the request that claimed it and, once that request completed, its
+// response. Token identifies the reservation while the request is in flight.
+type IdempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"`
+	Token       string `json:"token,omitempty"`
+	Completed   bool   `json:"completed"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+type IIdempotencyStore interface {
+	Reserve(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) (*IdempotencyRecord, error)
+	Renew(ctx context.Context, key string, token string, ttl time.Duration) error
+	Complete(ctx context.Context, key string, token string, record IdempotencyRecord, ttl time.Duration) error
+	Release(ctx context.Context, key string, token string) error
+}
+
+type idempotencyStore struct {
+	client *redis.Client
+}
+
+func NewIdempotencyStore(client *redis.Client) IIdempotencyStore {
+	return &idempotencyStore{client: client}
+}
+
+// Reserve claims key for record and returns nil. When the key was already
+// claimed it returns the stored record instead, leaving it untouched.
+func (s *idempotencyStore) Reserve(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) (*IdempotencyRecord, error) {
+	val, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := reserveScript.Run(ctx, s.client, []string{key}, string(val), ttl.Milliseconds()).Text()
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var stored IdempotencyRecord
+	if err := json.Unmarshal([]byte(existing), &stored); err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+// Renew extends the reservation made with token. It returns
+// ErrReservationLost when the reservation expired or was completed.
+func (s *idempotencyStore) Renew(ctx context.Context, key string, token string, ttl time.Duration) error {
+	renewed, err := renewReservationScript.Run(ctx, s.client, []string{key}, token, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return err
+	}
+	if renewed == 0 {
+		return ErrReservationLost
+	}
+	return nil
+}
+
+// Complete stores the response of the request that reserved key with token.
+// It returns ErrReservationLost without writing anything when the
+// reservation expired, so a late request cannot overwrite the result of the
+// request that claimed the key after it.
+func (s *idempotencyStore) Complete(ctx context.Context, key string, token string, record IdempotencyRecord, ttl time.Duration) error {
+	record.Token = ""
+	record.Completed = true
+	val, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	completed, err := completeScript.Run(ctx, s.client, []string{key}, token, string(val), ttl.Milliseconds()).Int64()
+	if err != nil {
+		return err
+	}
+	if completed == 0 {
+		return ErrReservationLost
+	}
+	return nil
+}
+
+// Release drops the reservation made with token and leaves the key alone
+// once it belongs to another request.
+func (s *idempotencyStore) Release(ctx context.Context, key string, token string) error {
+	return releaseReservationScript.Run(ctx, s.client, []string{key}, token).Err()
+}
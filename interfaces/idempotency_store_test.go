@@ -0,0 +1,137 @@
+package interfaces
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/suite"
+)
+
+type IdempotencyStoreSuite struct {
+	suite.Suite
+	miniRedis   *miniredis.Miniredis
+	redisClient *redis.Client
+	store       IIdempotencyStore
+	ctx         context.Context
+}
+
+func (s *IdempotencyStoreSuite) SetupTest() {
+	var err error
+	s.miniRedis, err = miniredis.Run()
+	s.Require().NoError(err)
+
+	s.redisClient = redis.NewClient(&redis.Options{Addr: s.miniRedis.Addr()})
+	s.store = NewIdempotencyStore(s.redisClient)
+	s.ctx = context.Background()
+}
+
+func (s *IdempotencyStoreSuite) TearDownTest() {
+	s.redisClient.Close()
+	s.miniRedis.Close()
+}
+
+func TestIdempotencyStoreSuite(t *testing.T) {
+	suite.Run(t, new(IdempotencyStoreSuite))
+}
+
+func (s *IdempotencyStoreSuite) TestReserve() {
+	existing, err := s.store.Reserve(s.ctx, "idempotency:key-1", IdempotencyRecord{Fingerprint: "abc", Token: "token-1"}, time.Hour)
+	s.NoError(err)
+	s.Nil(existing)
+	s.Equal(time.Hour, s.miniRedis.TTL("idempotency:key-1"))
+
+	existing, err = s.store.Reserve(s.ctx, "idempotency:key-1", IdempotencyRecord{Fingerprint: "def", Token: "token-2"}, time.Hour)
+	s.NoError(err)
+	s.Require().NotNil(existing)
+	s.Equal("abc", existing.Fingerprint)
+	s.False(existing.Completed)
+}
+
+func (s *IdempotencyStoreSuite) TestComplete() {
+	_, err := s.store.Reserve(s.ctx, "idempotency:key-1", IdempotencyRecord{Fingerprint: "abc", Token: "token-1"}, time.Hour)
+	s.Require().NoError(err)
+
+	s.NoError(s.store.Complete(s.ctx, "idempotency:key-1", "token-1", IdempotencyRecord{
+		Fingerprint: "abc",
+		StatusCode:  200,
+		ContentType: "application/json",
+		Body:        []byte(`{"success":true}`),
+	}, 2*time.Hour))
+	s.Equal(2*time.Hour, s.miniRedis.TTL("idempotency:key-1"))
+
+	existing, err := s.store.Reserve(s.ctx, "idempotency:key-1", IdempotencyRecord{Fingerprint: "abc", Token: "token-1"}, time.Hour)
+	s.NoError(err)
+	s.Require().NotNil(existing)
+	s.True(existing.Completed)
+	s.Equal(200, existing.StatusCode)
+	s.Equal(`{"success":true}`, string(existing.Body))
+}
+
+func (s *IdempotencyStoreSuite) TestRelease() {
+	_, err := s.store.Reserve(s.ctx, "idempotency:key-1", IdempotencyRecord{Fingerprint: "abc", Token: "token-1"}, time.Hour)
+	s.Require().NoError(err)
+
+	s.NoError(s.store.Release(s.ctx, "idempotency:key-1", "token-1"))
+
+	existing, err := s.store.Reserve(s.ctx, "idempotency:key-1", IdempotencyRecord{Fingerprint: "def", Token: "token-2"}, time.Hour)
+	s.NoError(err)
+	s.Nil(existing)
+}
+
+func (s *IdempotencyStoreSuite) TestReleaseKeepsSuccessor() {
+	_, err := s.store.Reserve(s.ctx, "idempotency:key-1", IdempotencyRecord{Fingerprint: "abc", Token: "token-2"}, time.Hour)
+	s.Require().NoError(err)
+
+	s.NoError(s.store.Release(s.ctx, "idempotency:key-1", "token-1"))
+	s.True(s.miniRedis.Exists("idempotency:key-1"))
+}
+
+func (s *IdempotencyStoreSuite) TestRenew() {
+	_, err := s.store.Reserve(s.ctx, "idempotency:key-1", IdempotencyRecord{Fingerprint: "abc", Token: "token-1"}, time.Minute)
+	s.Require().NoError(err)
+
+	s.NoError(s.store.Renew(s.ctx, "idempotency:key-1", "token-1", time.Hour))
+	s.Equal(time.Hour, s.miniRedis.TTL("idempotency:key-1"))
+
+	s.ErrorIs(s.store.Renew(s.ctx, "idempotency:key-1", "token-2", time.Hour), ErrReservationLost)
+	s.ErrorIs(s.store.Renew(s.ctx, "idempotency:key-2", "token-1", time.Hour), ErrReservationLost)
+}
+
+func (s *IdempotencyStoreSuite) TestCompleteAfterReservationLost() {
+	_, err := s.store.Reserve(s.ctx, "idempotency:key-1", IdempotencyRecord{Fingerprint: "abc", Token: "token-1"}, time.Second)
+	s.Require().NoError(err)
+	s.miniRedis.FastForward(2 * time.Second)
+	_, err = s.store.Reserve(s.ctx, "idempotency:key-1", IdempotencyRecord{Fingerprint: "abc", Token: "token-2"}, time.Minute)
+	s.Require().NoError(err)
+	s.Require().NoError(s.store.Complete(s.ctx, "idempotency:key-1", "token-2", IdempotencyRecord{Fingerprint: "abc", StatusCode: 200}, time.Hour))
+
+	err = s.store.Complete(s.ctx, "idempotency:key-1", "token-1", IdempotencyRecord{Fingerprint: "abc", StatusCode: 201}, time.Hour)
+	s.ErrorIs(err, ErrReservationLost)
+
+	existing, err := s.store.Reserve(s.ctx, "idempotency:key-1", IdempotencyRecord{Fingerprint: "abc", Token: "token-3"}, time.Minute)
+	s.NoError(err)
+	s.Require().NotNil(existing)
+	s.Equal(200, existing.StatusCode)
+	s.ErrorIs(s.store.Renew(s.ctx, "idempotency:key-1", "token-2", time.Hour), ErrReservationLost)
+}
+
+func (s *IdempotencyStoreSuite) TestExpiry() {
+	_, err := s.store.Reserve(s.ctx, "idempotency:key-1", IdempotencyRecord{Fingerprint: "abc", Token: "token-1"}, time.Second)
+	s.Require().NoError(err)
+
+	s.miniRedis.FastForward(2 * time.Second)
+
+	existing, err := s.store.Reserve(s.ctx, "idempotency:key-1", IdempotencyRecord{Fingerprint: "def", Token: "token-2"}, time.Second)
+	s.NoError(err)
+	s.Nil(existing)
+}
+
+func (s *IdempotencyStoreSuite) TestReserveError() {
+	s.miniRedis.Close()
+
+	_, err := s.store.Reserve(s.ctx, "idempotency:key-1", IdempotencyRecord{Fingerprint: "abc", Token: "token-1"}, time.Hour)
+	s.Error(err)
+}
@@ -0,0 +1,132 @@
+package interfaces
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"time"
+
+	"github.com/vnFuhung2903/vcs-report-service/pkg/env"
+	"gopkg.in/gomail.v2"
+)
+
+// ErrMailRejected marks a permanent 5xx reply of the SMTP server, such as an
+// unknown mailbox, which sending again cannot fix.
+var ErrMailRejected = errors.New("mail rejected")
+
+type IMailDialer interface {
+	DialAndSend(messages ...*gomail.Message) error
+}
+
+type mailDialer struct {
+	host     string
+	port     int
+	tlsMode  string
+	username string
+	password string
+	timeout  time.Duration
+}
+
+func NewMailDialer(env env.GomailEnv) IMailDialer {
+	return &mailDialer{
+		host:     env.MailHost,
+		port:     env.MailPort,
+		tlsMode:  env.MailTLSMode,
+		username: env.MailUsername,
+		password: env.MailPassword,
+		timeout:  env.MailTimeout,
+	}
+}
+
+// DialAndSend sends messages, returning an error that wraps ErrMailRejected
+// when the server refused one with a permanent reply.
+func (d *mailDialer) DialAndSend(messages ...*gomail.Message) error {
+	// gomail flattens the error of the send function into text, so keep it
+	// to tell permanent replies apart.
+	var sendErr error
+	err := gomail.Send(gomail.SendFunc(func(from string, to []string, msg io.WriterTo) error {
+		sendErr = d.send(from, to, msg)
+		return sendErr
+	}), messages...)
+
+	var reply *textproto.Error
+	if err != nil && errors.As(sendErr, &reply) && reply.Code >= 500 {
+		return fmt.Errorf("%w: %v", ErrMailRejected, err)
+	}
+	return err
+}
+
+// send delivers msg over a fresh SMTP session. The timeout bounds both
+// connecting and the whole conversation so that an unresponsive server
+// cannot block the caller.
+func (d *mailDialer) send(from string, to []string, msg io.WriterTo) error {
+	address := net.JoinHostPort(d.host, strconv.Itoa(d.port))
+	tlsConfig := &tls.Config{ServerName: d.host}
+	dialer := &net.Dialer{Timeout: d.timeout}
+
+	var conn net.Conn
+	var err error
+	if d.tlsMode == "tls" {
+		conn, err = tls.DialWithDialer(dialer, "tcp", address, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", address)
+	}
+	if err != nil {
+		return err
+	}
+	if d.timeout > 0 {
+		if err := conn.SetDeadline(time.Now().Add(d.timeout)); err != nil {
+			conn.Close()
+			return err
+		}
+	}
+
+	client, err := smtp.NewClient(conn, d.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if d.tlsMode == "starttls" {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("smtp server does not support STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+
+	if d.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", d.username, d.password, d.host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	for _, recipient := range to {
+		if err := client.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := msg.WriteTo(writer); err != nil {
+		writer.Close()
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
@@ -0,0 +1,232 @@
+package interfaces
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/env"
+	"gopkg.in/gomail.v2"
+)
+
+type fakeSMTPServer struct {
+	listener   net.Listener
+	from       string
+	recipients []string
+	data       string
+	rcptReply  string
+	done       chan struct{}
+}
+
+// newFakeSMTPServer accepts one session. A non-empty rcptReply is sent to
+// every RCPT command instead of accepting the recipient.
+func newFakeSMTPServer(rcptReply string) (*fakeSMTPServer, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	server := &fakeSMTPServer{listener: listener, rcptReply: rcptReply, done: make(chan struct{})}
+	go server.serve()
+	return server, nil
+}
+
+func (f *fakeSMTPServer) serve() {
+	defer close(f.done)
+
+	conn, err := f.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 fake ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250-fake")
+			reply("250 8BITMIME")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			f.from = strings.Trim(strings.Fields(strings.TrimPrefix(command, "MAIL FROM:"))[0], "<>")
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			if f.rcptReply != "" {
+				reply(f.rcptReply)
+				continue
+			}
+			f.recipients = append(f.recipients, strings.Trim(strings.TrimPrefix(command, "RCPT TO:"), "<>"))
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			f.data = data.String()
+			reply("250 OK")
+		case command == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+func (f *fakeSMTPServer) port() int {
+	return f.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (f *fakeSMTPServer) Close() {
+	f.listener.Close()
+}
+
+type MailDialerSuite struct {
+	suite.Suite
+	server *fakeSMTPServer
+}
+
+func (s *MailDialerSuite) SetupTest() {
+	var err error
+	s.server, err = newFakeSMTPServer("")
+	s.Require().NoError(err)
+}
+
+func (s *MailDialerSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func TestMailDialerSuite(t *testing.T) {
+	suite.Run(t, new(MailDialerSuite))
+}
+
+func (s *MailDialerSuite) newMessage() *gomail.Message {
+	message := gomail.NewMessage()
+	message.SetAddressHeader("From", "reports@example.com", "VCS Reports")
+	message.SetHeader("To", "recipient@example.com")
+	message.SetHeader("Subject", "Test report")
+	message.SetBody("text/plain", "hello")
+	return message
+}
+
+func (s *MailDialerSuite) TestDialAndSendWithoutTLS() {
+	dialer := NewMailDialer(env.GomailEnv{
+		MailHost:    "127.0.0.1",
+		MailPort:    s.server.port(),
+		MailTLSMode: "none",
+	})
+
+	err := dialer.DialAndSend(s.newMessage())
+	s.NoError(err)
+
+	<-s.server.done
+	s.Equal("reports@example.com", s.server.from)
+	s.Equal([]string{"recipient@example.com"}, s.server.recipients)
+	s.Contains(s.server.data, "Subject: Test report")
+	s.Contains(s.server.data, "hello")
+}
+
+func (s *MailDialerSuite) TestDialAndSendRejected() {
+	server, err := newFakeSMTPServer("550 5.1.1 Mailbox unavailable")
+	s.Require().NoError(err)
+	defer server.Close()
+
+	dialer := NewMailDialer(env.GomailEnv{
+		MailHost:    "127.0.0.1",
+		MailPort:    server.port(),
+		MailTLSMode: "none",
+	})
+
+	err = dialer.DialAndSend(s.newMessage())
+	s.ErrorIs(err, ErrMailRejected)
+	s.Contains(err.Error(), "Mailbox unavailable")
+}
+
+func (s *MailDialerSuite) TestDialAndSendTransientReply() {
+	server, err := newFakeSMTPServer("451 4.3.0 Try again later")
+	s.Require().NoError(err)
+	defer server.Close()
+
+	dialer := NewMailDialer(env.GomailEnv{
+		MailHost:    "127.0.0.1",
+		MailPort:    server.port(),
+		MailTLSMode: "none",
+	})
+
+	err = dialer.DialAndSend(s.newMessage())
+	s.Error(err)
+	s.NotErrorIs(err, ErrMailRejected)
+}
+
+func (s *MailDialerSuite) TestDialAndSendRequiresStartTLS() {
+	dialer := NewMailDialer(env.GomailEnv{
+		MailHost:    "127.0.0.1",
+		MailPort:    s.server.port(),
+		MailTLSMode: "starttls",
+	})
+
+	err := dialer.DialAndSend(s.newMessage())
+	s.Error(err)
+	s.Contains(err.Error(), "STARTTLS")
+}
+
+func (s *MailDialerSuite) TestDialAndSendConnectionRefused() {
+	port := s.server.port()
+	s.server.Close()
+
+	dialer := NewMailDialer(env.GomailEnv{
+		MailHost:    "127.0.0.1",
+		MailPort:    port,
+		MailTLSMode: "none",
+	})
+
+	err := dialer.DialAndSend(s.newMessage())
+	s.Error(err)
+	s.Contains(err.Error(), strconv.Itoa(port))
+}
+
+func (s *MailDialerSuite) TestDialAndSendTimesOut() {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	s.Require().NoError(err)
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			defer conn.Close()
+			time.Sleep(time.Second)
+		}
+	}()
+
+	dialer := NewMailDialer(env.GomailEnv{
+		MailHost:    "127.0.0.1",
+		MailPort:    listener.Addr().(*net.TCPAddr).Port,
+		MailTLSMode: "none",
+		MailTimeout: 100 * time.Millisecond,
+	})
+
+	started := time.Now()
+	err = dialer.DialAndSend(s.newMessage())
+	s.Error(err)
+	s.Contains(err.Error(), "i/o timeout")
+	s.Less(time.Since(started), time.Second)
+}
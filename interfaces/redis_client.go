@@ -3,13 +3,58 @@ package interfaces
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/vnFuhung2903/vcs-report-service/entities"
 )
 
+// fencedHSetScript writes the hash field only while the lock at KEYS[1] still
+// holds the fencing token of the writer. A lease that expired, or was taken
+// over by a holder with a newer token, can no longer write.
+var fencedHSetScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+redis.call("HSET", KEYS[2], ARGV[2], ARGV[3])
+return 1
+`)
+
+// maxHSetScript writes the hash field only when the new value sorts after the
+// stored one, so that concurrent writers can never move it backwards. With a
+// lock key in KEYS[2] it also requires the lock to still hold the fencing
+// token of the writer.
+var maxHSetScript = redis.NewScript(`
+if #KEYS > 1 and redis.call("GET", KEYS[2]) ~= ARGV[3] then
+	return -1
+end
+local current = redis.call("HGET", KEYS[1], ARGV[1])
+if current and current >= ARGV[2] then
+	return 0
+end
+redis.call("HSET", KEYS[1], ARGV[1], ARGV[2])
+return 1
+`)
+
 type IRedisClient interface {
 	Get(ctx context.Context, key string) ([]entities.ContainerWithStatus, error)
+	HGet(ctx context.Context, key string, field string) (string, error)
+	HGetAll(ctx context.Context, key string) (map[string]string, error)
+	HSet(ctx context.Context, key string, field string, value string) error
+	HSetFenced(ctx context.Context, lease *Lease, key string, field string, value string) error
+	HSetMax(ctx context.Context, lease *Lease, key string, field string, value string) error
+	HMGet(ctx context.Context, key string, fields ...string) ([]string, error)
+	HScan(ctx context.Context, key string, cursor uint64, count int64) (map[string]string, uint64, error)
+	HDel(ctx context.Context, key string, field string) error
+	ZAdd(ctx context.Context, key string, score float64, member string) error
+	ZRevRange(ctx context.Context, key string, start int64, stop int64) ([]string, error)
+	ZRangeByScore(ctx context.Context, key string, min string, max string) ([]string, error)
+	ZRem(ctx context.Context, key string, members ...string) error
+	LPush(ctx context.Context, key string, value string) error
+	BRPop(ctx context.Context, timeout time.Duration, key string) (string, error)
+	BLMove(ctx context.Context, timeout time.Duration, source string, destination string) (string, error)
+	LMove(ctx context.Context, source string, destination string) (string, error)
+	LRem(ctx context.Context, key string, value string) error
 }
 
 type redisClient struct {
@@ -34,3 +79,161 @@ func (c *redisClient) Get(ctx context.Context, key string) ([]entities.Container
 	}
 	return result, nil
 }
+
+func (c *redisClient) HGet(ctx context.Context, key string, field string) (string, error) {
+	val, err := c.client.HGet(ctx, key, field).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return val, err
+}
+
+func (c *redisClient) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return c.client.HGetAll(ctx, key).Result()
+}
+
+func (c *redisClient) HSet(ctx context.Context, key string, field string, value string) error {
+	return c.client.HSet(ctx, key, field, value).Err()
+}
+
+// HSetFenced behaves like HSet when lease is nil and otherwise returns
+// ErrLeaseLost instead of writing once lease is no longer current.
+func (c *redisClient) HSetFenced(ctx context.Context, lease *Lease, key string, field string, value string) error {
+	if lease == nil {
+		return c.HSet(ctx, key, field, value)
+	}
+
+	written, err := fencedHSetScript.Run(ctx, c.client, []string{lease.Key, key}, lease.Token, field, value).Int64()
+	if err != nil {
+		return err
+	}
+	if written == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// HSetMax stores value in the hash field unless the stored value already
+// sorts at or after it, comparing both as strings, so values must use a
+// fixed-width sortable encoding. Like HSetFenced, it returns ErrLeaseLost once
+// lease, when given, is no longer current.
+func (c *redisClient) HSetMax(ctx context.Context, lease *Lease, key string, field string, value string) error {
+	keys := []string{key}
+	args := []interface{}{field, value}
+	if lease != nil {
+		keys = append(keys, lease.Key)
+		args = append(args, lease.Token)
+	}
+
+	written, err := maxHSetScript.Run(ctx, c.client, keys, args...).Int64()
+	if err != nil {
+		return err
+	}
+	if written < 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// HMGet returns the values of fields in order, with an empty string for every
+// field that does not exist.
+func (c *redisClient) HMGet(ctx context.Context, key string, fields ...string) ([]string, error) {
+	vals, err := c.client.HMGet(ctx, key, fields...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, len(vals))
+	for i, val := range vals {
+		if str, ok := val.(string); ok {
+			result[i] = str
+		}
+	}
+	return result, nil
+}
+
+// HScan returns about count fields of the hash key from cursor on, with the
+// cursor to continue from, which is 0 once the whole hash has been read.
+func (c *redisClient) HScan(ctx context.Context, key string, cursor uint64, count int64) (map[string]string, uint64, error) {
+	pairs, next, err := c.client.HScan(ctx, key, cursor, "", count).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	values := make(map[string]string, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		values[pairs[i]] = pairs[i+1]
+	}
+	return values, next, nil
+}
+
+func (c *redisClient) HDel(ctx context.Context, key string, field string) error {
+	return c.client.HDel(ctx, key, field).Err()
+}
+
+func (c *redisClient) ZAdd(ctx context.Context, key string, score float64, member string) error {
+	return c.client.ZAdd(ctx, key, redis.Z{Score: score, Member: member}).Err()
+}
+
+// ZRevRange returns the members ranked start to stop, highest score first.
+func (c *redisClient) ZRevRange(ctx context.Context, key string, start int64, stop int64) ([]string, error) {
+	return c.client.ZRevRange(ctx, key, start, stop).Result()
+}
+
+// ZRangeByScore returns the members scored between min and max, lowest score
+// first. Both bounds accept the Redis syntax, such as "-inf" or "(100".
+func (c *redisClient) ZRangeByScore(ctx context.Context, key string, min string, max string) ([]string, error) {
+	return c.client.ZRangeByScore(ctx, key, &redis.ZRangeBy{Min: min, Max: max}).Result()
+}
+
+func (c *redisClient) ZRem(ctx context.Context, key string, members ...string) error {
+	args := make([]interface{}, len(members))
+	for i, member := range members {
+		args[i] = member
+	}
+	return c.client.ZRem(ctx, key, args...).Err()
+}
+
+func (c *redisClient) LPush(ctx context.Context, key string, value string) error {
+	return c.client.LPush(ctx, key, value).Err()
+}
+
+// BRPop waits up to timeout for a value at the tail of the list key and
+// returns an empty string when none arrived in time.
+func (c *redisClient) BRPop(ctx context.Context, timeout time.Duration, key string) (string, error) {
+	val, err := c.client.BRPop(ctx, timeout, key).Result()
+	if err == redis.Nil {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	return val[1], nil
+}
+
+// BLMove waits up to timeout for a value at the tail of the list source and
+// atomically pushes it to the head of destination, so that the value is never
+// held only by the caller. It returns an empty string when none arrived in
+// time.
+func (c *redisClient) BLMove(ctx context.Context, timeout time.Duration, source string, destination string) (string, error) {
+	val, err := c.client.BLMove(ctx, source, destination, "RIGHT", "LEFT", timeout).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return val, err
+}
+
+// LMove moves the value at the tail of source to the tail of destination,
+// where it is the next one taken, and returns an empty string when source is
+// empty.
+func (c *redisClient) LMove(ctx context.Context, source string, destination string) (string, error) {
+	val, err := c.client.LMove(ctx, source, destination, "RIGHT", "RIGHT").Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return val, err
+}
+
+// LRem removes every occurrence of value from the list key.
+func (c *redisClient) LRem(ctx context.Context, key string, value string) error {
+	return c.client.LRem(ctx, key, 0, value).Err()
+}
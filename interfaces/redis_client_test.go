@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/alicebob/miniredis/v2"
 	"github.com/redis/go-redis/v9"
@@ -103,3 +104,165 @@ func (s *RedisClientSuite) TestGetContextCancellation() {
 	s.Nil(result)
 	s.Contains(err.Error(), "context canceled")
 }
+
+func (s *RedisClientSuite) TestHashOperations() {
+	ctx := context.Background()
+
+	val, err := s.client.HGet(ctx, "test-hash", "missing")
+	s.NoError(err)
+	s.Empty(val)
+
+	s.NoError(s.client.HSet(ctx, "test-hash", "field-1", "value-1"))
+	s.NoError(s.client.HSet(ctx, "test-hash", "field-2", "value-2"))
+
+	val, err = s.client.HGet(ctx, "test-hash", "field-1")
+	s.NoError(err)
+	s.Equal("value-1", val)
+
+	values, err := s.client.HGetAll(ctx, "test-hash")
+	s.NoError(err)
+	s.Equal(map[string]string{"field-1": "value-1", "field-2": "value-2"}, values)
+
+	fields, err := s.client.HMGet(ctx, "test-hash", "field-2", "missing", "field-1")
+	s.NoError(err)
+	s.Equal([]string{"value-2", "", "value-1"}, fields)
+
+	scanned := map[string]string{}
+	for cursor := uint64(0); ; {
+		values, next, err := s.client.HScan(ctx, "test-hash", cursor, 1)
+		s.Require().NoError(err)
+		for field, val := range values {
+			scanned[field] = val
+		}
+		if next == 0 {
+			break
+		}
+		cursor = next
+	}
+	s.Equal(map[string]string{"field-1": "value-1", "field-2": "value-2"}, scanned)
+
+	s.NoError(s.client.HDel(ctx, "test-hash", "field-1"))
+	values, err = s.client.HGetAll(ctx, "test-hash")
+	s.NoError(err)
+	s.Equal(map[string]string{"field-2": "value-2"}, values)
+}
+
+func (s *RedisClientSuite) TestHSetFenced() {
+	ctx := context.Background()
+	lock := NewRedisLock(s.redisClient)
+
+	s.NoError(s.client.HSetFenced(ctx, nil, "test-hash", "field-1", "unfenced"))
+
+	first, err := lock.Acquire(ctx, "test-lock", time.Second)
+	s.Require().NoError(err)
+	s.Require().NotNil(first)
+	s.NoError(s.client.HSetFenced(ctx, first, "test-hash", "field-1", "first"))
+
+	s.miniRedis.FastForward(2 * time.Second)
+	s.ErrorIs(s.client.HSetFenced(ctx, first, "test-hash", "field-1", "expired"), ErrLeaseLost)
+
+	second, err := lock.Acquire(ctx, "test-lock", time.Second)
+	s.Require().NoError(err)
+	s.Require().NotNil(second)
+	s.ErrorIs(s.client.HSetFenced(ctx, first, "test-hash", "field-1", "stale"), ErrLeaseLost)
+	s.NoError(s.client.HSetFenced(ctx, second, "test-hash", "field-1", "second"))
+
+	val, err := s.client.HGet(ctx, "test-hash", "field-1")
+	s.NoError(err)
+	s.Equal("second", val)
+}
+
+func (s *RedisClientSuite) TestHSetMax() {
+	ctx := context.Background()
+	lock := NewRedisLock(s.redisClient)
+
+	s.NoError(s.client.HSetMax(ctx, nil, "test-hash", "field-1", "2024-01-02T00:00:00Z"))
+	s.NoError(s.client.HSetMax(ctx, nil, "test-hash", "field-1", "2024-01-01T00:00:00Z"))
+	val, err := s.client.HGet(ctx, "test-hash", "field-1")
+	s.NoError(err)
+	s.Equal("2024-01-02T00:00:00Z", val)
+
+	lease, err := lock.Acquire(ctx, "test-lock", time.Second)
+	s.Require().NoError(err)
+	s.Require().NotNil(lease)
+	s.NoError(s.client.HSetMax(ctx, lease, "test-hash", "field-1", "2024-01-03T00:00:00Z"))
+
+	s.miniRedis.FastForward(2 * time.Second)
+	s.ErrorIs(s.client.HSetMax(ctx, lease, "test-hash", "field-1", "2024-01-04T00:00:00Z"), ErrLeaseLost)
+
+	val, err = s.client.HGet(ctx, "test-hash", "field-1")
+	s.NoError(err)
+	s.Equal("2024-01-03T00:00:00Z", val)
+}
+
+func (s *RedisClientSuite) TestSortedSetOperations() {
+	ctx := context.Background()
+
+	s.NoError(s.client.ZAdd(ctx, "test-zset", 3, "member-3"))
+	s.NoError(s.client.ZAdd(ctx, "test-zset", 1, "member-1"))
+	s.NoError(s.client.ZAdd(ctx, "test-zset", 2, "member-2"))
+
+	members, err := s.client.ZRevRange(ctx, "test-zset", 0, 1)
+	s.NoError(err)
+	s.Equal([]string{"member-3", "member-2"}, members)
+
+	members, err = s.client.ZRangeByScore(ctx, "test-zset", "-inf", "(3")
+	s.NoError(err)
+	s.Equal([]string{"member-1", "member-2"}, members)
+
+	s.NoError(s.client.ZRem(ctx, "test-zset", "member-1", "member-2"))
+	members, err = s.client.ZRevRange(ctx, "test-zset", 0, -1)
+	s.NoError(err)
+	s.Equal([]string{"member-3"}, members)
+}
+
+func (s *RedisClientSuite) TestListOperations() {
+	ctx := context.Background()
+
+	s.NoError(s.client.LPush(ctx, "test-list", "value-1"))
+	s.NoError(s.client.LPush(ctx, "test-list", "value-2"))
+
+	val, err := s.client.BRPop(ctx, time.Second, "test-list")
+	s.NoError(err)
+	s.Equal("value-1", val)
+
+	val, err = s.client.BRPop(ctx, time.Second, "test-list")
+	s.NoError(err)
+	s.Equal("value-2", val)
+
+	val, err = s.client.BRPop(ctx, time.Second, "test-list")
+	s.NoError(err)
+	s.Empty(val)
+}
+
+func (s *RedisClientSuite) TestListMoveOperations() {
+	ctx := context.Background()
+
+	s.NoError(s.client.LPush(ctx, "test-queue", "value-1"))
+	s.NoError(s.client.LPush(ctx, "test-queue", "value-2"))
+
+	val, err := s.client.BLMove(ctx, time.Second, "test-queue", "test-processing")
+	s.NoError(err)
+	s.Equal("value-1", val)
+	val, err = s.client.BLMove(ctx, time.Second, "test-queue", "test-processing")
+	s.NoError(err)
+	s.Equal("value-2", val)
+
+	val, err = s.client.BLMove(ctx, 100*time.Millisecond, "test-queue", "test-processing")
+	s.NoError(err)
+	s.Empty(val)
+
+	s.NoError(s.client.LRem(ctx, "test-processing", "value-2"))
+	s.NoError(s.client.LPush(ctx, "test-queue", "value-3"))
+
+	val, err = s.client.LMove(ctx, "test-processing", "test-queue")
+	s.NoError(err)
+	s.Equal("value-1", val)
+	val, err = s.client.LMove(ctx, "test-processing", "test-queue")
+	s.NoError(err)
+	s.Empty(val)
+
+	val, err = s.client.BRPop(ctx, time.Second, "test-queue")
+	s.NoError(err)
+	s.Equal("value-1", val)
+}
@@ -0,0 +1,93 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const fencingKey = "report_lock_fencing"
+
+var ErrLeaseLost = errors.New("lease is no longer held")
+
+// acquireScript sets the lock only when it is free and hands out the next
+// fencing token, so tokens strictly increase across every successful lease.
+// The token is stored as the lock value, which lets fenced writes check that
+// the lease they were made under is still the current one.
+var acquireScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	return 0
+end
+local token = redis.call("INCR", KEYS[2])
+redis.call("SET", KEYS[1], token, "PX", ARGV[1])
+return token
+`)
+
+var renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+type Lease struct {
+	Key   string
+	Token int64
+}
+
+type IRedisLock interface {
+	Acquire(ctx context.Context, key string, ttl time.Duration) (*Lease, error)
+	Renew(ctx context.Context, lease *Lease, ttl time.Duration) error
+	Release(ctx context.Context, lease *Lease) error
+}
+
+type redisLock struct {
+	client *redis.Client
+}
+
+func NewRedisLock(client *redis.Client) IRedisLock {
+	return &redisLock{client: client}
+}
+
+// Acquire returns a nil lease without error when another holder owns the key.
+func (l *redisLock) Acquire(ctx context.Context, key string, ttl time.Duration) (*Lease, error) {
+	token, err := acquireScript.Run(ctx, l.client, []string{key, fencingKey}, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return nil, err
+	}
+	if token == 0 {
+		return nil, nil
+	}
+	return &Lease{Key: key, Token: token}, nil
+}
+
+func (l *redisLock) Renew(ctx context.Context, lease *Lease, ttl time.Duration) error {
+	renewed, err := renewScript.Run(ctx, l.client, []string{lease.Key}, lease.Token, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return err
+	}
+	if renewed == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+func (l *redisLock) Release(ctx context.Context, lease *Lease) error {
+	released, err := releaseScript.Run(ctx, l.client, []string{lease.Key}, lease.Token).Int64()
+	if err != nil {
+		return err
+	}
+	if released == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
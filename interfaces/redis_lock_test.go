@@ -0,0 +1,105 @@
+package interfaces
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/suite"
+)
+
+type RedisLockSuite struct {
+	suite.Suite
+	miniRedis   *miniredis.Miniredis
+	redisClient *redis.Client
+	lock        IRedisLock
+	ctx         context.Context
+}
+
+func (s *RedisLockSuite) SetupTest() {
+	var err error
+	s.miniRedis, err = miniredis.Run()
+	s.Require().NoError(err)
+
+	s.redisClient = redis.NewClient(&redis.Options{Addr: s.miniRedis.Addr()})
+	s.lock = NewRedisLock(s.redisClient)
+	s.ctx = context.Background()
+}
+
+func (s *RedisLockSuite) TearDownTest() {
+	s.redisClient.Close()
+	s.miniRedis.Close()
+}
+
+func TestRedisLockSuite(t *testing.T) {
+	suite.Run(t, new(RedisLockSuite))
+}
+
+func (s *RedisLockSuite) TestAcquire() {
+	lease, err := s.lock.Acquire(s.ctx, "report_lock:daily", time.Minute)
+	s.NoError(err)
+	s.Require().NotNil(lease)
+	s.Equal("report_lock:daily", lease.Key)
+	s.Equal(int64(1), lease.Token)
+	s.Equal(time.Minute, s.miniRedis.TTL("report_lock:daily"))
+	value, err := s.miniRedis.Get("report_lock:daily")
+	s.NoError(err)
+	s.Equal("1", value)
+
+	other, err := s.lock.Acquire(s.ctx, "report_lock:daily", time.Minute)
+	s.NoError(err)
+	s.Nil(other)
+}
+
+func (s *RedisLockSuite) TestAcquireAfterExpiry() {
+	first, err := s.lock.Acquire(s.ctx, "report_lock:daily", time.Second)
+	s.Require().NoError(err)
+	s.Require().NotNil(first)
+
+	s.miniRedis.FastForward(2 * time.Second)
+
+	second, err := s.lock.Acquire(s.ctx, "report_lock:daily", time.Second)
+	s.NoError(err)
+	s.Require().NotNil(second)
+	s.Greater(second.Token, first.Token)
+
+	s.ErrorIs(s.lock.Renew(s.ctx, first, time.Second), ErrLeaseLost)
+	s.ErrorIs(s.lock.Release(s.ctx, first), ErrLeaseLost)
+	s.True(s.miniRedis.Exists("report_lock:daily"))
+}
+
+func (s *RedisLockSuite) TestRenew() {
+	lease, err := s.lock.Acquire(s.ctx, "report_lock:daily", time.Second)
+	s.Require().NoError(err)
+	s.Require().NotNil(lease)
+
+	s.NoError(s.lock.Renew(s.ctx, lease, time.Minute))
+	s.Equal(time.Minute, s.miniRedis.TTL("report_lock:daily"))
+}
+
+func (s *RedisLockSuite) TestRelease() {
+	lease, err := s.lock.Acquire(s.ctx, "report_lock:daily", time.Minute)
+	s.Require().NoError(err)
+	s.Require().NotNil(lease)
+
+	s.NoError(s.lock.Release(s.ctx, lease))
+	s.False(s.miniRedis.Exists("report_lock:daily"))
+
+	next, err := s.lock.Acquire(s.ctx, "report_lock:daily", time.Minute)
+	s.NoError(err)
+	s.Require().NotNil(next)
+	s.Equal(int64(2), next.Token)
+}
+
+func (s *RedisLockSuite) TestRedisError() {
+	s.miniRedis.Close()
+
+	lease, err := s.lock.Acquire(s.ctx, "report_lock:daily", time.Minute)
+	s.Error(err)
+	s.Nil(lease)
+
+	s.Error(s.lock.Renew(s.ctx, &Lease{Key: "report_lock:daily"}, time.Minute))
+	s.Error(s.lock.Release(s.ctx, &Lease{Key: "report_lock:daily"}))
+}
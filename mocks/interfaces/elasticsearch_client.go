@@ -35,6 +35,20 @@ func (m *MockIElasticsearchClient) EXPECT() *MockIElasticsearchClientMockRecorde
 	return m.recorder
 }
 
+// ClosePointInTime mocks base method.
+func (m *MockIElasticsearchClient) ClosePointInTime(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClosePointInTime", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClosePointInTime indicates an expected call of ClosePointInTime.
+func (mr *MockIElasticsearchClientMockRecorder) ClosePointInTime(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClosePointInTime", reflect.TypeOf((*MockIElasticsearchClient)(nil).ClosePointInTime), ctx, id)
+}
+
 // Do mocks base method.
 func (m *MockIElasticsearchClient) Do(ctx context.Context, req esapi.Request) (*esapi.Response, error) {
 	m.ctrl.T.Helper()
@@ -49,3 +63,18 @@ func (mr *MockIElasticsearchClientMockRecorder) Do(ctx, req interface{}) *gomock
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockIElasticsearchClient)(nil).Do), ctx, req)
 }
+
+// OpenPointInTime mocks base method.
+func (m *MockIElasticsearchClient) OpenPointInTime(ctx context.Context, index, keepAlive string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenPointInTime", ctx, index, keepAlive)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenPointInTime indicates an expected call of OpenPointInTime.
+func (mr *MockIElasticsearchClientMockRecorder) OpenPointInTime(ctx, index, keepAlive interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenPointInTime", reflect.TypeOf((*MockIElasticsearchClient)(nil).OpenPointInTime), ctx, index, keepAlive)
+}
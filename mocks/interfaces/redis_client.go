@@ -7,9 +7,11 @@ package interfaces
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	entities "github.com/vnFuhung2903/vcs-report-service/entities"
+	interfaces "github.com/vnFuhung2903/vcs-report-service/interfaces"
 )
 
 // MockIRedisClient is a mock of IRedisClient interface.
@@ -35,6 +37,36 @@ func (m *MockIRedisClient) EXPECT() *MockIRedisClientMockRecorder {
 	return m.recorder
 }
 
+// BLMove mocks base method.
+func (m *MockIRedisClient) BLMove(ctx context.Context, timeout time.Duration, source, destination string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BLMove", ctx, timeout, source, destination)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BLMove indicates an expected call of BLMove.
+func (mr *MockIRedisClientMockRecorder) BLMove(ctx, timeout, source, destination interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BLMove", reflect.TypeOf((*MockIRedisClient)(nil).BLMove), ctx, timeout, source, destination)
+}
+
+// BRPop mocks base method.
+func (m *MockIRedisClient) BRPop(ctx context.Context, timeout time.Duration, key string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BRPop", ctx, timeout, key)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BRPop indicates an expected call of BRPop.
+func (mr *MockIRedisClientMockRecorder) BRPop(ctx, timeout, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BRPop", reflect.TypeOf((*MockIRedisClient)(nil).BRPop), ctx, timeout, key)
+}
+
 // Get mocks base method.
 func (m *MockIRedisClient) Get(ctx context.Context, key string) ([]entities.ContainerWithStatus, error) {
 	m.ctrl.T.Helper()
@@ -49,3 +81,231 @@ func (mr *MockIRedisClientMockRecorder) Get(ctx, key interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockIRedisClient)(nil).Get), ctx, key)
 }
+
+// HDel mocks base method.
+func (m *MockIRedisClient) HDel(ctx context.Context, key, field string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HDel", ctx, key, field)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HDel indicates an expected call of HDel.
+func (mr *MockIRedisClientMockRecorder) HDel(ctx, key, field interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HDel", reflect.TypeOf((*MockIRedisClient)(nil).HDel), ctx, key, field)
+}
+
+// HGet mocks base method.
+func (m *MockIRedisClient) HGet(ctx context.Context, key, field string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HGet", ctx, key, field)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HGet indicates an expected call of HGet.
+func (mr *MockIRedisClientMockRecorder) HGet(ctx, key, field interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HGet", reflect.TypeOf((*MockIRedisClient)(nil).HGet), ctx, key, field)
+}
+
+// HGetAll mocks base method.
+func (m *MockIRedisClient) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HGetAll", ctx, key)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HGetAll indicates an expected call of HGetAll.
+func (mr *MockIRedisClientMockRecorder) HGetAll(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HGetAll", reflect.TypeOf((*MockIRedisClient)(nil).HGetAll), ctx, key)
+}
+
+// HMGet mocks base method.
+func (m *MockIRedisClient) HMGet(ctx context.Context, key string, fields ...string) ([]string, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range fields {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "HMGet", varargs...)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HMGet indicates an expected call of HMGet.
+func (mr *MockIRedisClientMockRecorder) HMGet(ctx, key interface{}, fields ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, key}, fields...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HMGet", reflect.TypeOf((*MockIRedisClient)(nil).HMGet), varargs...)
+}
+
+// HScan mocks base method.
+func (m *MockIRedisClient) HScan(ctx context.Context, key string, cursor uint64, count int64) (map[string]string, uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HScan", ctx, key, cursor, count)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(uint64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// HScan indicates an expected call of HScan.
+func (mr *MockIRedisClientMockRecorder) HScan(ctx, key, cursor, count interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HScan", reflect.TypeOf((*MockIRedisClient)(nil).HScan), ctx, key, cursor, count)
+}
+
+// HSet mocks base method.
+func (m *MockIRedisClient) HSet(ctx context.Context, key, field, value string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HSet", ctx, key, field, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HSet indicates an expected call of HSet.
+func (mr *MockIRedisClientMockRecorder) HSet(ctx, key, field, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HSet", reflect.TypeOf((*MockIRedisClient)(nil).HSet), ctx, key, field, value)
+}
+
+// HSetFenced mocks base method.
+func (m *MockIRedisClient) HSetFenced(ctx context.Context, lease *interfaces.Lease, key, field, value string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HSetFenced", ctx, lease, key, field, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HSetFenced indicates an expected call of HSetFenced.
+func (mr *MockIRedisClientMockRecorder) HSetFenced(ctx, lease, key, field, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HSetFenced", reflect.TypeOf((*MockIRedisClient)(nil).HSetFenced), ctx, lease, key, field, value)
+}
+
+// HSetMax mocks base method.
+func (m *MockIRedisClient) HSetMax(ctx context.Context, lease *interfaces.Lease, key, field, value string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HSetMax", ctx, lease, key, field, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// HSetMax indicates an expected call of HSetMax.
+func (mr *MockIRedisClientMockRecorder) HSetMax(ctx, lease, key, field, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HSetMax", reflect.TypeOf((*MockIRedisClient)(nil).HSetMax), ctx, lease, key, field, value)
+}
+
+// LMove mocks base method.
+func (m *MockIRedisClient) LMove(ctx context.Context, source, destination string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LMove", ctx, source, destination)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LMove indicates an expected call of LMove.
+func (mr *MockIRedisClientMockRecorder) LMove(ctx, source, destination interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LMove", reflect.TypeOf((*MockIRedisClient)(nil).LMove), ctx, source, destination)
+}
+
+// LPush mocks base method.
+func (m *MockIRedisClient) LPush(ctx context.Context, key, value string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LPush", ctx, key, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LPush indicates an expected call of LPush.
+func (mr *MockIRedisClientMockRecorder) LPush(ctx, key, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LPush", reflect.TypeOf((*MockIRedisClient)(nil).LPush), ctx, key, value)
+}
+
+// LRem mocks base method.
+func (m *MockIRedisClient) LRem(ctx context.Context, key, value string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LRem", ctx, key, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LRem indicates an expected call of LRem.
+func (mr *MockIRedisClientMockRecorder) LRem(ctx, key, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LRem", reflect.TypeOf((*MockIRedisClient)(nil).LRem), ctx, key, value)
+}
+
+// ZAdd mocks base method.
+func (m *MockIRedisClient) ZAdd(ctx context.Context, key string, score float64, member string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZAdd", ctx, key, score, member)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ZAdd indicates an expected call of ZAdd.
+func (mr *MockIRedisClientMockRecorder) ZAdd(ctx, key, score, member interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZAdd", reflect.TypeOf((*MockIRedisClient)(nil).ZAdd), ctx, key, score, member)
+}
+
+// ZRangeByScore mocks base method.
+func (m *MockIRedisClient) ZRangeByScore(ctx context.Context, key, min, max string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZRangeByScore", ctx, key, min, max)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZRangeByScore indicates an expected call of ZRangeByScore.
+func (mr *MockIRedisClientMockRecorder) ZRangeByScore(ctx, key, min, max interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRangeByScore", reflect.TypeOf((*MockIRedisClient)(nil).ZRangeByScore), ctx, key, min, max)
+}
+
+// ZRem mocks base method.
+func (m *MockIRedisClient) ZRem(ctx context.Context, key string, members ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, key}
+	for _, a := range members {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ZRem", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ZRem indicates an expected call of ZRem.
+func (mr *MockIRedisClientMockRecorder) ZRem(ctx, key interface{}, members ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, key}, members...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRem", reflect.TypeOf((*MockIRedisClient)(nil).ZRem), varargs...)
+}
+
+// ZRevRange mocks base method.
+func (m *MockIRedisClient) ZRevRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ZRevRange", ctx, key, start, stop)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ZRevRange indicates an expected call of ZRevRange.
+func (mr *MockIRedisClientMockRecorder) ZRevRange(ctx, key, start, stop interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ZRevRange", reflect.TypeOf((*MockIRedisClient)(nil).ZRevRange), ctx, key, start, stop)
+}
@@ -11,6 +11,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	dto "github.com/vnFuhung2903/vcs-report-service/dto"
+	entities "github.com/vnFuhung2903/vcs-report-service/entities"
 )
 
 // MockIReportService is a mock of IReportService interface.
@@ -36,14 +37,27 @@ func (m *MockIReportService) EXPECT() *MockIReportServiceMockRecorder {
 	return m.recorder
 }
 
+// AggregateReportStatistic mocks base method.
+func (m *MockIReportService) AggregateReportStatistic(ctx context.Context, startTime, endTime time.Time, filter entities.ContainerFilter) (dto.ReportResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AggregateReportStatistic", ctx, startTime, endTime, filter)
+	ret0, _ := ret[0].(dto.ReportResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AggregateReportStatistic indicates an expected call of AggregateReportStatistic.
+func (mr *MockIReportServiceMockRecorder) AggregateReportStatistic(ctx, startTime, endTime, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AggregateReportStatistic", reflect.TypeOf((*MockIReportService)(nil).AggregateReportStatistic), ctx, startTime, endTime, filter)
+}
+
 // CalculateReportStatistic mocks base method.
-func (m *MockIReportService) CalculateReportStatistic(statusList, overlapStatusList map[string][]dto.EsStatus, startTime, endTime time.Time) (int, int, float64) {
+func (m *MockIReportService) CalculateReportStatistic(statusList, overlapStatusList map[string][]dto.EsStatus, startTime, endTime time.Time) dto.ReportResponse {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CalculateReportStatistic", statusList, overlapStatusList, startTime, endTime)
-	ret0, _ := ret[0].(int)
-	ret1, _ := ret[1].(int)
-	ret2, _ := ret[2].(float64)
-	return ret0, ret1, ret2
+	ret0, _ := ret[0].(dto.ReportResponse)
+	return ret0
 }
 
 // CalculateReportStatistic indicates an expected call of CalculateReportStatistic.
@@ -52,31 +66,46 @@ func (mr *MockIReportServiceMockRecorder) CalculateReportStatistic(statusList, o
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CalculateReportStatistic", reflect.TypeOf((*MockIReportService)(nil).CalculateReportStatistic), statusList, overlapStatusList, startTime, endTime)
 }
 
+// GenerateReport mocks base method.
+func (m *MockIReportService) GenerateReport(ctx context.Context, startTime, endTime time.Time, filter entities.ContainerFilter) (dto.ReportResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateReport", ctx, startTime, endTime, filter)
+	ret0, _ := ret[0].(dto.ReportResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateReport indicates an expected call of GenerateReport.
+func (mr *MockIReportServiceMockRecorder) GenerateReport(ctx, startTime, endTime, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateReport", reflect.TypeOf((*MockIReportService)(nil).GenerateReport), ctx, startTime, endTime, filter)
+}
+
 // GetEsStatus mocks base method.
-func (m *MockIReportService) GetEsStatus(ctx context.Context, limit int, startTime, endTime time.Time, order dto.SortOrder) (map[string][]dto.EsStatus, error) {
+func (m *MockIReportService) GetEsStatus(ctx context.Context, limit int, startTime, endTime time.Time, order dto.SortOrder, filter entities.ContainerFilter) (map[string][]dto.EsStatus, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEsStatus", ctx, limit, startTime, endTime, order)
+	ret := m.ctrl.Call(m, "GetEsStatus", ctx, limit, startTime, endTime, order, filter)
 	ret0, _ := ret[0].(map[string][]dto.EsStatus)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetEsStatus indicates an expected call of GetEsStatus.
-func (mr *MockIReportServiceMockRecorder) GetEsStatus(ctx, limit, startTime, endTime, order interface{}) *gomock.Call {
+func (mr *MockIReportServiceMockRecorder) GetEsStatus(ctx, limit, startTime, endTime, order, filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEsStatus", reflect.TypeOf((*MockIReportService)(nil).GetEsStatus), ctx, limit, startTime, endTime, order)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEsStatus", reflect.TypeOf((*MockIReportService)(nil).GetEsStatus), ctx, limit, startTime, endTime, order, filter)
 }
 
 // SendEmail mocks base method.
-func (m *MockIReportService) SendEmail(ctx context.Context, to string, totalCount, onCount, offCount int, totalUptime float64, startTime, endTime time.Time) error {
+func (m *MockIReportService) SendEmail(ctx context.Context, to string, report dto.ReportResponse) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SendEmail", ctx, to, totalCount, onCount, offCount, totalUptime, startTime, endTime)
+	ret := m.ctrl.Call(m, "SendEmail", ctx, to, report)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SendEmail indicates an expected call of SendEmail.
-func (mr *MockIReportServiceMockRecorder) SendEmail(ctx, to, totalCount, onCount, offCount, totalUptime, startTime, endTime interface{}) *gomock.Call {
+func (mr *MockIReportServiceMockRecorder) SendEmail(ctx, to, report interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendEmail", reflect.TypeOf((*MockIReportService)(nil).SendEmail), ctx, to, totalCount, onCount, offCount, totalUptime, startTime, endTime)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendEmail", reflect.TypeOf((*MockIReportService)(nil).SendEmail), ctx, to, report)
 }
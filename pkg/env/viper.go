@@ -1,11 +1,23 @@
 package env
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/robfig/cron/v3"
 	"github.com/spf13/viper"
+	"github.com/vnFuhung2903/vcs-report-service/entities"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/timerange"
 )
 
+var ErrInvalidReportTarget = errors.New("invalid report target")
+
 type AuthEnv struct {
 	JWTSecret string
 }
@@ -15,8 +27,14 @@ type ElasticsearchEnv struct {
 }
 
 type GomailEnv struct {
+	MailHost     string
+	MailPort     int
+	MailTLSMode  string
 	MailUsername string
 	MailPassword string
+	MailFrom     string
+	MailFromName string
+	MailTimeout  time.Duration
 }
 
 type RedisEnv struct {
@@ -25,6 +43,67 @@ type RedisEnv struct {
 	RedisDb       int
 }
 
+type ReportEnv struct {
+	SLATarget           float64
+	StatisticMode       string
+	Schedule            string
+	Timezone            string
+	Window              string
+	Schedules           []ReportScheduleEnv
+	SubscriptionRefresh time.Duration
+	LockTTL             time.Duration
+	RunRetention        time.Duration
+	MaxCatchUp          int
+	JobWorkers          int
+	JobRetention        time.Duration
+	DeadLetterLimit     int
+	EmailAttachments    []string
+	TemplateDir         string
+	Locale              string
+	Recipients          []RecipientPreferenceEnv
+	TargetHosts         TargetHostsEnv
+}
+
+// TargetHostsEnv lists the hosts each webhook-based channel may deliver
+// reports to. An entry such as "*.webhook.office.com" allows every subdomain
+// of webhook.office.com.
+type TargetHostsEnv struct {
+	Slack   []string
+	Teams   []string
+	Webhook []string
+}
+
+type ReportScheduleEnv struct {
+	Name       string                   `json:"name"`
+	Schedule   string                   `json:"schedule"`
+	Timezone   string                   `json:"timezone"`
+	Window     string                   `json:"window"`
+	Channel    string                   `json:"channel"`
+	Recipients []string                 `json:"recipients"`
+	Template   string                   `json:"template"`
+	Filter     entities.ContainerFilter `json:"filter"`
+}
+
+// RecipientPreferenceEnv sets the language and timezone a report is rendered
+// in for one recipient.
+type RecipientPreferenceEnv struct {
+	Address  string `json:"address"`
+	Locale   string `json:"locale"`
+	Timezone string `json:"timezone"`
+}
+
+type RetryEnv struct {
+	Attempts   int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	Jitter     float64
+}
+
+type IdempotencyEnv struct {
+	TTL         time.Duration
+	InFlightTTL time.Duration
+}
+
 type LoggerEnv struct {
 	Level      string
 	FilePath   string
@@ -38,6 +117,9 @@ type Env struct {
 	ElasticsearchEnv ElasticsearchEnv
 	GomailEnv        GomailEnv
 	RedisEnv         RedisEnv
+	ReportEnv        ReportEnv
+	RetryEnv         RetryEnv
+	IdempotencyEnv   IdempotencyEnv
 	LoggerEnv        LoggerEnv
 }
 
@@ -46,9 +128,35 @@ func LoadEnv() (*Env, error) {
 	v.AutomaticEnv()
 
 	v.SetDefault("ELASTICSEARCH_ADDRESS", "http://localhost:9200")
+	v.SetDefault("MAIL_HOST", "smtp.gmail.com")
+	v.SetDefault("MAIL_PORT", 587)
+	v.SetDefault("MAIL_TLS_MODE", "starttls")
+	v.SetDefault("MAIL_TIMEOUT", "30s")
 	v.SetDefault("REDIS_ADDRESS", "localhost:6379")
 	v.SetDefault("REDIS_PASSWORD", "")
 	v.SetDefault("REDIS_DB", 0)
+	v.SetDefault("REPORT_SLA_TARGET", 99.9)
+	v.SetDefault("REPORT_STATISTIC_MODE", "memory")
+	v.SetDefault("REPORT_SCHEDULE", "0 0 * * *")
+	v.SetDefault("REPORT_TIMEZONE", "UTC")
+	v.SetDefault("REPORT_WINDOW", "day")
+	v.SetDefault("REPORT_LOCALE", "en")
+	v.SetDefault("REPORT_SLACK_HOSTS", "hooks.slack.com")
+	v.SetDefault("REPORT_TEAMS_HOSTS", "*.webhook.office.com")
+	v.SetDefault("REPORT_CHANNEL", "email")
+	v.SetDefault("REPORT_SUBSCRIPTION_REFRESH", "1m")
+	v.SetDefault("REPORT_LOCK_TTL", "30s")
+	v.SetDefault("REPORT_RUN_RETENTION", "720h")
+	v.SetDefault("REPORT_MAX_CATCHUP", 3)
+	v.SetDefault("REPORT_JOB_WORKERS", 4)
+	v.SetDefault("REPORT_JOB_RETENTION", "24h")
+	v.SetDefault("REPORT_DEAD_LETTER_LIMIT", 1000)
+	v.SetDefault("RETRY_ATTEMPTS", 3)
+	v.SetDefault("RETRY_BACKOFF", "1s")
+	v.SetDefault("RETRY_MAX_BACKOFF", "30s")
+	v.SetDefault("RETRY_JITTER", 0.2)
+	v.SetDefault("IDEMPOTENCY_TTL", "24h")
+	v.SetDefault("IDEMPOTENCY_INFLIGHT_TTL", "1m")
 	v.SetDefault("ZAP_LEVEL", "info")
 	v.SetDefault("ZAP_FILEPATH", "./logs/app.log")
 	v.SetDefault("ZAP_MAXSIZE", 100)
@@ -70,11 +178,25 @@ func LoadEnv() (*Env, error) {
 	}
 
 	gomailEnv := GomailEnv{
+		MailHost:     v.GetString("MAIL_HOST"),
+		MailPort:     v.GetInt("MAIL_PORT"),
+		MailTLSMode:  v.GetString("MAIL_TLS_MODE"),
 		MailUsername: v.GetString("MAIL_USERNAME"),
 		MailPassword: v.GetString("MAIL_PASSWORD"),
+		MailFrom:     v.GetString("MAIL_FROM"),
+		MailFromName: v.GetString("MAIL_FROM_NAME"),
+		MailTimeout:  v.GetDuration("MAIL_TIMEOUT"),
 	}
-	if gomailEnv.MailUsername == "" {
-		return nil, errors.New("redis environment variables are empty")
+	if gomailEnv.MailFrom == "" {
+		gomailEnv.MailFrom = gomailEnv.MailUsername
+	}
+	if gomailEnv.MailHost == "" || gomailEnv.MailPort <= 0 || gomailEnv.MailFrom == "" || gomailEnv.MailTimeout <= 0 || !slices.Contains([]string{"starttls", "tls", "none"}, gomailEnv.MailTLSMode) {
+		return nil, errors.New("gomail environment variables are empty or invalid")
+	}
+	// The SMTP client refuses to send credentials in the clear to anything but
+	// localhost, so every send would fail at runtime.
+	if gomailEnv.MailTLSMode == "none" && gomailEnv.MailUsername != "" && !isLocalhost(gomailEnv.MailHost) {
+		return nil, errors.New("mail credentials require MAIL_TLS_MODE starttls or tls unless MAIL_HOST is localhost")
 	}
 
 	redisEnv := RedisEnv{
@@ -86,6 +208,129 @@ func LoadEnv() (*Env, error) {
 		return nil, errors.New("redis environment variables are empty")
 	}
 
+	reportEnv := ReportEnv{
+		SLATarget:           v.GetFloat64("REPORT_SLA_TARGET"),
+		StatisticMode:       v.GetString("REPORT_STATISTIC_MODE"),
+		Schedule:            v.GetString("REPORT_SCHEDULE"),
+		Timezone:            v.GetString("REPORT_TIMEZONE"),
+		Window:              v.GetString("REPORT_WINDOW"),
+		SubscriptionRefresh: v.GetDuration("REPORT_SUBSCRIPTION_REFRESH"),
+		LockTTL:             v.GetDuration("REPORT_LOCK_TTL"),
+		RunRetention:        v.GetDuration("REPORT_RUN_RETENTION"),
+		MaxCatchUp:          v.GetInt("REPORT_MAX_CATCHUP"),
+		JobWorkers:          v.GetInt("REPORT_JOB_WORKERS"),
+		JobRetention:        v.GetDuration("REPORT_JOB_RETENTION"),
+		DeadLetterLimit:     v.GetInt("REPORT_DEAD_LETTER_LIMIT"),
+		EmailAttachments:    splitList(v.GetString("REPORT_EMAIL_ATTACHMENTS")),
+		TemplateDir:         v.GetString("REPORT_TEMPLATE_DIR"),
+		Locale:              v.GetString("REPORT_LOCALE"),
+		TargetHosts: TargetHostsEnv{
+			Slack:   splitList(v.GetString("REPORT_SLACK_HOSTS")),
+			Teams:   splitList(v.GetString("REPORT_TEAMS_HOSTS")),
+			Webhook: splitList(v.GetString("REPORT_WEBHOOK_HOSTS")),
+		},
+	}
+	if reportEnv.SLATarget <= 0 || reportEnv.SLATarget > 100 || !slices.Contains([]string{"memory", "aggregation"}, reportEnv.StatisticMode) || !validReportWindow(reportEnv.Window) || reportEnv.SubscriptionRefresh <= 0 || reportEnv.LockTTL <= 0 || reportEnv.RunRetention < 0 || reportEnv.MaxCatchUp < 0 || reportEnv.JobWorkers < 1 || reportEnv.JobRetention < 0 || reportEnv.DeadLetterLimit < 0 {
+		return nil, errors.New("report environment variables are invalid")
+	}
+	for _, attachment := range reportEnv.EmailAttachments {
+		if !slices.Contains([]string{"csv", "pdf"}, attachment) {
+			return nil, fmt.Errorf("report email attachment %q is not supported", attachment)
+		}
+	}
+	if _, err := cron.ParseStandard(reportEnv.Schedule); err != nil {
+		return nil, fmt.Errorf("report schedule is invalid: %w", err)
+	}
+	if _, err := time.LoadLocation(reportEnv.Timezone); err != nil {
+		return nil, fmt.Errorf("report timezone is invalid: %w", err)
+	}
+	if !validLocale(reportEnv.Locale) {
+		return nil, fmt.Errorf("report locale %q is not supported", reportEnv.Locale)
+	}
+
+	// Without REPORT_SCHEDULES the daily default report keeps being sent, to
+	// REPORT_RECIPIENTS or else to the sending mailbox. REPORT_SCHEDULES=[]
+	// turns scheduled reports off.
+	if schedules := v.GetString("REPORT_SCHEDULES"); schedules != "" {
+		if err := json.Unmarshal([]byte(schedules), &reportEnv.Schedules); err != nil {
+			return nil, fmt.Errorf("report schedules are invalid: %w", err)
+		}
+	} else {
+		recipients := splitList(v.GetString("REPORT_RECIPIENTS"))
+		if len(recipients) == 0 {
+			recipients = []string{gomailEnv.MailFrom}
+		}
+		reportEnv.Schedules = []ReportScheduleEnv{{
+			Name:       "default",
+			Channel:    v.GetString("REPORT_CHANNEL"),
+			Recipients: recipients,
+		}}
+	}
+	names := make([]string, 0, len(reportEnv.Schedules))
+	for i := range reportEnv.Schedules {
+		schedule := &reportEnv.Schedules[i]
+		if schedule.Schedule == "" {
+			schedule.Schedule = reportEnv.Schedule
+		}
+		if schedule.Timezone == "" {
+			schedule.Timezone = reportEnv.Timezone
+		}
+		if schedule.Window == "" {
+			schedule.Window = reportEnv.Window
+		}
+		if schedule.Channel == "" {
+			schedule.Channel = "email"
+		}
+		if err := ValidateReportSchedule(*schedule, reportEnv.TargetHosts); err != nil {
+			return nil, err
+		}
+		if slices.Contains(names, schedule.Name) {
+			return nil, fmt.Errorf("report schedule %q is defined more than once", schedule.Name)
+		}
+		names = append(names, schedule.Name)
+	}
+
+	if preferences := v.GetString("REPORT_RECIPIENT_PREFERENCES"); preferences != "" {
+		if err := json.Unmarshal([]byte(preferences), &reportEnv.Recipients); err != nil {
+			return nil, fmt.Errorf("report recipient preferences are invalid: %w", err)
+		}
+	}
+	addresses := make([]string, 0, len(reportEnv.Recipients))
+	for i := range reportEnv.Recipients {
+		recipient := &reportEnv.Recipients[i]
+		if recipient.Locale == "" {
+			recipient.Locale = reportEnv.Locale
+		}
+		if recipient.Timezone == "" {
+			recipient.Timezone = reportEnv.Timezone
+		}
+		if err := validateRecipientPreference(*recipient); err != nil {
+			return nil, err
+		}
+		if slices.Contains(addresses, strings.ToLower(recipient.Address)) {
+			return nil, fmt.Errorf("report recipient %q has preferences defined more than once", recipient.Address)
+		}
+		addresses = append(addresses, strings.ToLower(recipient.Address))
+	}
+
+	retryEnv := RetryEnv{
+		Attempts:   v.GetInt("RETRY_ATTEMPTS"),
+		Backoff:    v.GetDuration("RETRY_BACKOFF"),
+		MaxBackoff: v.GetDuration("RETRY_MAX_BACKOFF"),
+		Jitter:     v.GetFloat64("RETRY_JITTER"),
+	}
+	if retryEnv.Attempts < 1 || retryEnv.Backoff <= 0 || retryEnv.MaxBackoff < retryEnv.Backoff || retryEnv.Jitter < 0 || retryEnv.Jitter > 1 {
+		return nil, errors.New("retry environment variables are invalid")
+	}
+
+	idempotencyEnv := IdempotencyEnv{
+		TTL:         v.GetDuration("IDEMPOTENCY_TTL"),
+		InFlightTTL: v.GetDuration("IDEMPOTENCY_INFLIGHT_TTL"),
+	}
+	if idempotencyEnv.TTL <= 0 || idempotencyEnv.InFlightTTL <= 0 {
+		return nil, errors.New("idempotency environment variables are invalid")
+	}
+
 	loggerEnv := LoggerEnv{
 		Level:      v.GetString("ZAP_LEVEL"),
 		FilePath:   v.GetString("ZAP_FILEPATH"),
@@ -102,6 +347,128 @@ func LoadEnv() (*Env, error) {
 		ElasticsearchEnv: elasticsearchEnv,
 		GomailEnv:        gomailEnv,
 		RedisEnv:         redisEnv,
+		ReportEnv:        reportEnv,
+		RetryEnv:         retryEnv,
+		IdempotencyEnv:   idempotencyEnv,
 		LoggerEnv:        loggerEnv,
 	}, nil
 }
+
+func ValidateReportSchedule(schedule ReportScheduleEnv, targetHosts TargetHostsEnv) error {
+	if schedule.Name == "" {
+		return errors.New("report schedule name is empty")
+	}
+	if _, err := cron.ParseStandard(schedule.Schedule); err != nil {
+		return fmt.Errorf("report schedule %q is invalid: %w", schedule.Name, err)
+	}
+	if _, err := time.LoadLocation(schedule.Timezone); err != nil {
+		return fmt.Errorf("report schedule %q timezone is invalid: %w", schedule.Name, err)
+	}
+	if !validReportWindow(schedule.Window) {
+		return fmt.Errorf("report schedule %q window is invalid: %s", schedule.Name, schedule.Window)
+	}
+	if !slices.Contains([]string{"email", "slack", "teams", "webhook"}, schedule.Channel) {
+		return fmt.Errorf("report schedule %q channel is invalid: %s", schedule.Name, schedule.Channel)
+	}
+	if len(schedule.Recipients) == 0 {
+		return fmt.Errorf("report schedule %q has no recipients", schedule.Name)
+	}
+	for _, recipient := range schedule.Recipients {
+		if err := ValidateReportTarget(targetHosts, schedule.Channel, recipient); err != nil {
+			return fmt.Errorf("report schedule %q: %w", schedule.Name, err)
+		}
+	}
+	if err := schedule.Filter.Validate(); err != nil {
+		return fmt.Errorf("report schedule %q filter is invalid: %w", schedule.Name, err)
+	}
+	return nil
+}
+
+// ValidateReportTarget checks that channel may deliver to target: an email
+// address for email, otherwise an https URL on one of the hosts allowed for
+// the channel, so that callers cannot make the service post to arbitrary or
+// internal addresses.
+func ValidateReportTarget(targetHosts TargetHostsEnv, channel string, target string) error {
+	var allowed []string
+	switch channel {
+	case "email":
+		if address, err := mail.ParseAddress(target); err != nil || address.Address != target {
+			return fmt.Errorf("%w: %q is not an email address", ErrInvalidReportTarget, target)
+		}
+		return nil
+	case "slack":
+		allowed = targetHosts.Slack
+	case "teams":
+		allowed = targetHosts.Teams
+	case "webhook":
+		allowed = targetHosts.Webhook
+	default:
+		return fmt.Errorf("%w: channel %q is not supported", ErrInvalidReportTarget, channel)
+	}
+
+	targetURL, err := url.Parse(target)
+	if err != nil || targetURL.Scheme != "https" || targetURL.Host == "" || targetURL.User != nil {
+		return fmt.Errorf("%w: %s target must be an https URL", ErrInvalidReportTarget, channel)
+	}
+	if !allowedHost(allowed, targetURL.Hostname()) {
+		return fmt.Errorf("%w: host %q is not allowed for %s", ErrInvalidReportTarget, targetURL.Hostname(), channel)
+	}
+	return nil
+}
+
+func allowedHost(allowed []string, host string) bool {
+	host = strings.ToLower(host)
+	for _, pattern := range allowed {
+		pattern = strings.ToLower(pattern)
+		if domain, ok := strings.CutPrefix(pattern, "*."); ok {
+			if strings.HasSuffix(host, "."+domain) {
+				return true
+			}
+		} else if host == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+func validateRecipientPreference(recipient RecipientPreferenceEnv) error {
+	if recipient.Address == "" {
+		return errors.New("report recipient address is empty")
+	}
+	if !validLocale(recipient.Locale) {
+		return fmt.Errorf("report recipient %q locale is not supported: %s", recipient.Address, recipient.Locale)
+	}
+	if _, err := time.LoadLocation(recipient.Timezone); err != nil {
+		return fmt.Errorf("report recipient %q timezone is invalid: %w", recipient.Address, err)
+	}
+	return nil
+}
+
+func isLocalhost(host string) bool {
+	return host == "localhost" || host == "127.0.0.1" || host == "::1"
+}
+
+func validLocale(locale string) bool {
+	return slices.Contains([]string{"en", "vi"}, locale)
+}
+
+func validReportWindow(window string) bool {
+	if window == "schedule" || window == "day" {
+		return true
+	}
+	if length, err := time.ParseDuration(window); err == nil {
+		return length > 0
+	}
+	_, _, err := timerange.Resolve(window, time.Now(), time.UTC)
+	return err == nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -3,8 +3,10 @@ package env
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
+	"github.com/vnFuhung2903/vcs-report-service/entities"
 )
 
 type ViperSuite struct {
@@ -19,11 +21,45 @@ func (suite *ViperSuite) SetupTest() {
 	envVars := []string{
 		"JWT_SECRET_KEY",
 		"ELASTICSEARCH_ADDRESS",
+		"MAIL_HOST",
+		"MAIL_PORT",
+		"MAIL_TLS_MODE",
 		"MAIL_USERNAME",
 		"MAIL_PASSWORD",
+		"MAIL_FROM",
+		"MAIL_FROM_NAME",
+		"MAIL_TIMEOUT",
 		"REDIS_ADDRESS",
 		"REDIS_PASSWORD",
 		"REDIS_DB",
+		"REPORT_SLA_TARGET",
+		"REPORT_STATISTIC_MODE",
+		"REPORT_SCHEDULE",
+		"REPORT_TIMEZONE",
+		"REPORT_WINDOW",
+		"REPORT_CHANNEL",
+		"REPORT_RECIPIENTS",
+		"REPORT_SCHEDULES",
+		"REPORT_SUBSCRIPTION_REFRESH",
+		"REPORT_LOCK_TTL",
+		"REPORT_RUN_RETENTION",
+		"REPORT_MAX_CATCHUP",
+		"REPORT_JOB_WORKERS",
+		"REPORT_JOB_RETENTION",
+		"REPORT_DEAD_LETTER_LIMIT",
+		"REPORT_EMAIL_ATTACHMENTS",
+		"REPORT_TEMPLATE_DIR",
+		"REPORT_LOCALE",
+		"REPORT_RECIPIENT_PREFERENCES",
+		"REPORT_SLACK_HOSTS",
+		"REPORT_TEAMS_HOSTS",
+		"REPORT_WEBHOOK_HOSTS",
+		"RETRY_ATTEMPTS",
+		"RETRY_BACKOFF",
+		"RETRY_MAX_BACKOFF",
+		"RETRY_JITTER",
+		"IDEMPOTENCY_TTL",
+		"IDEMPOTENCY_INFLIGHT_TTL",
 		"ZAP_LEVEL",
 		"ZAP_FILEPATH",
 		"ZAP_MAXSIZE",
@@ -62,8 +98,47 @@ func (suite *ViperSuite) TestLoadEnv() {
 
 	suite.Equal("test_jwt_secret", env.AuthEnv.JWTSecret)
 
+	suite.Equal("smtp.gmail.com", env.GomailEnv.MailHost)
+	suite.Equal(587, env.GomailEnv.MailPort)
+	suite.Equal("starttls", env.GomailEnv.MailTLSMode)
+	suite.Equal(30*time.Second, env.GomailEnv.MailTimeout)
 	suite.Equal("test@example.com", env.GomailEnv.MailUsername)
 	suite.Equal("test_password", env.GomailEnv.MailPassword)
+	suite.Equal("test@example.com", env.GomailEnv.MailFrom)
+	suite.Equal("", env.GomailEnv.MailFromName)
+
+	suite.Equal(99.9, env.ReportEnv.SLATarget)
+	suite.Equal("memory", env.ReportEnv.StatisticMode)
+	suite.Equal("0 0 * * *", env.ReportEnv.Schedule)
+	suite.Equal("UTC", env.ReportEnv.Timezone)
+	suite.Equal("day", env.ReportEnv.Window)
+	suite.Equal([]ReportScheduleEnv{{
+		Name:       "default",
+		Schedule:   "0 0 * * *",
+		Timezone:   "UTC",
+		Window:     "day",
+		Channel:    "email",
+		Recipients: []string{"test@example.com"},
+	}}, env.ReportEnv.Schedules)
+	suite.Equal(time.Minute, env.ReportEnv.SubscriptionRefresh)
+	suite.Equal(30*time.Second, env.ReportEnv.LockTTL)
+	suite.Equal(720*time.Hour, env.ReportEnv.RunRetention)
+	suite.Equal(3, env.ReportEnv.MaxCatchUp)
+	suite.Equal(4, env.ReportEnv.JobWorkers)
+	suite.Equal(24*time.Hour, env.ReportEnv.JobRetention)
+	suite.Equal(1000, env.ReportEnv.DeadLetterLimit)
+	suite.Empty(env.ReportEnv.EmailAttachments)
+	suite.Empty(env.ReportEnv.TemplateDir)
+	suite.Equal("en", env.ReportEnv.Locale)
+	suite.Empty(env.ReportEnv.Recipients)
+
+	suite.Equal(3, env.RetryEnv.Attempts)
+	suite.Equal(time.Second, env.RetryEnv.Backoff)
+	suite.Equal(30*time.Second, env.RetryEnv.MaxBackoff)
+	suite.Equal(0.2, env.RetryEnv.Jitter)
+
+	suite.Equal(24*time.Hour, env.IdempotencyEnv.TTL)
+	suite.Equal(time.Minute, env.IdempotencyEnv.InFlightTTL)
 
 	suite.Equal("info", env.LoggerEnv.Level)
 	suite.Equal("/tmp/app.log", env.LoggerEnv.FilePath)
@@ -111,6 +186,66 @@ func (suite *ViperSuite) TestLoadEnvInvalidGomailValues() {
 	suite.Nil(env)
 }
 
+func (suite *ViperSuite) TestLoadEnvCustomGomailValues() {
+	envContent := map[string]string{
+		"JWT_SECRET_KEY": "test_jwt_secret",
+		"MAIL_HOST":      "relay.internal",
+		"MAIL_PORT":      "25",
+		"MAIL_TLS_MODE":  "none",
+		"MAIL_FROM":      "reports@example.com",
+		"MAIL_FROM_NAME": "VCS Reports",
+		"MAIL_TIMEOUT":   "5s",
+	}
+
+	suite.createEnvVars(envContent)
+	env, err := LoadEnv()
+	suite.NoError(err)
+
+	suite.Equal("relay.internal", env.GomailEnv.MailHost)
+	suite.Equal(25, env.GomailEnv.MailPort)
+	suite.Equal("none", env.GomailEnv.MailTLSMode)
+	suite.Equal("", env.GomailEnv.MailUsername)
+	suite.Equal("reports@example.com", env.GomailEnv.MailFrom)
+	suite.Equal("VCS Reports", env.GomailEnv.MailFromName)
+	suite.Equal(5*time.Second, env.GomailEnv.MailTimeout)
+
+	suite.createEnvVars(map[string]string{"MAIL_TLS_MODE": "ssl"})
+	env, err = LoadEnv()
+
+	suite.Error(err)
+	suite.Nil(env)
+
+	suite.createEnvVars(map[string]string{"MAIL_TLS_MODE": "none", "MAIL_TIMEOUT": "0s"})
+	env, err = LoadEnv()
+
+	suite.Error(err)
+	suite.Nil(env)
+}
+
+func (suite *ViperSuite) TestLoadEnvMailCredentialsRequireTLS() {
+	suite.createEnvVars(map[string]string{
+		"JWT_SECRET_KEY": "test_jwt_secret",
+		"MAIL_HOST":      "relay.internal",
+		"MAIL_TLS_MODE":  "none",
+		"MAIL_USERNAME":  "test@example.com",
+		"MAIL_PASSWORD":  "test_password",
+	})
+	env, err := LoadEnv()
+
+	suite.EqualError(err, "mail credentials require MAIL_TLS_MODE starttls or tls unless MAIL_HOST is localhost")
+	suite.Nil(env)
+
+	suite.createEnvVars(map[string]string{"MAIL_HOST": "localhost"})
+	env, err = LoadEnv()
+	suite.NoError(err)
+	suite.Equal("none", env.GomailEnv.MailTLSMode)
+
+	suite.createEnvVars(map[string]string{"MAIL_HOST": "relay.internal", "MAIL_TLS_MODE": "starttls"})
+	env, err = LoadEnv()
+	suite.NoError(err)
+	suite.Equal("starttls", env.GomailEnv.MailTLSMode)
+}
+
 func (suite *ViperSuite) TestLoadEnvInvalidRedisValues() {
 	envContent := map[string]string{
 		"JWT_SECRET_KEY": "test_jwt_secret",
@@ -125,3 +260,381 @@ func (suite *ViperSuite) TestLoadEnvInvalidRedisValues() {
 	suite.Error(err)
 	suite.Nil(env)
 }
+
+func (suite *ViperSuite) TestLoadEnvInvalidRetryValues() {
+	tests := []map[string]string{
+		{"RETRY_ATTEMPTS": "0"},
+		{"RETRY_BACKOFF": "0s"},
+		{"RETRY_BACKOFF": "1m", "RETRY_MAX_BACKOFF": "30s"},
+		{"RETRY_JITTER": "1.5"},
+	}
+
+	for _, tt := range tests {
+		suite.SetupTest()
+		suite.createEnvVars(map[string]string{
+			"JWT_SECRET_KEY": "test_jwt_secret",
+			"MAIL_USERNAME":  "test@example.com",
+		})
+		suite.createEnvVars(tt)
+		env, err := LoadEnv()
+
+		suite.EqualError(err, "retry environment variables are invalid")
+		suite.Nil(env)
+	}
+}
+
+func (suite *ViperSuite) TestLoadEnvInvalidIdempotencyValues() {
+	suite.createEnvVars(map[string]string{
+		"JWT_SECRET_KEY":  "test_jwt_secret",
+		"MAIL_USERNAME":   "test@example.com",
+		"IDEMPOTENCY_TTL": "0s",
+	})
+	env, err := LoadEnv()
+
+	suite.EqualError(err, "idempotency environment variables are invalid")
+	suite.Nil(env)
+}
+
+func (suite *ViperSuite) TestLoadEnvInvalidReportValues() {
+	envContent := map[string]string{
+		"JWT_SECRET_KEY":    "test_jwt_secret",
+		"MAIL_USERNAME":     "test@example.com",
+		"MAIL_PASSWORD":     "test_password",
+		"REPORT_SLA_TARGET": "120",
+	}
+
+	suite.createEnvVars(envContent)
+	env, err := LoadEnv()
+
+	suite.Error(err)
+	suite.Nil(env)
+
+	suite.createEnvVars(map[string]string{
+		"REPORT_SLA_TARGET":     "99.5",
+		"REPORT_STATISTIC_MODE": "sampling",
+	})
+	env, err = LoadEnv()
+
+	suite.Error(err)
+	suite.Nil(env)
+
+	suite.createEnvVars(map[string]string{
+		"REPORT_STATISTIC_MODE": "memory",
+		"REPORT_SCHEDULE":       "0 8 * * MONDAY-FRIDAY",
+	})
+	env, err = LoadEnv()
+
+	suite.Error(err)
+	suite.Nil(env)
+
+	suite.createEnvVars(map[string]string{
+		"REPORT_SCHEDULE": "0 8 * * MON-FRI",
+		"REPORT_TIMEZONE": "Mars/Olympus_Mons",
+	})
+	env, err = LoadEnv()
+
+	suite.Error(err)
+	suite.Nil(env)
+
+	suite.createEnvVars(map[string]string{
+		"REPORT_TIMEZONE": "UTC",
+		"REPORT_WINDOW":   "week",
+	})
+	env, err = LoadEnv()
+
+	suite.Error(err)
+	suite.Nil(env)
+
+	suite.createEnvVars(map[string]string{
+		"REPORT_WINDOW":               "schedule",
+		"REPORT_SUBSCRIPTION_REFRESH": "0s",
+	})
+	env, err = LoadEnv()
+
+	suite.Error(err)
+	suite.Nil(env)
+
+	suite.createEnvVars(map[string]string{
+		"REPORT_SUBSCRIPTION_REFRESH": "30s",
+		"REPORT_LOCK_TTL":             "-1s",
+	})
+	env, err = LoadEnv()
+
+	suite.Error(err)
+	suite.Nil(env)
+
+	suite.createEnvVars(map[string]string{
+		"REPORT_LOCK_TTL":      "1m",
+		"REPORT_RUN_RETENTION": "-1h",
+	})
+	env, err = LoadEnv()
+
+	suite.Error(err)
+	suite.Nil(env)
+
+	suite.createEnvVars(map[string]string{
+		"REPORT_RUN_RETENTION": "0s",
+		"REPORT_MAX_CATCHUP":   "-1",
+	})
+	env, err = LoadEnv()
+
+	suite.Error(err)
+	suite.Nil(env)
+
+	suite.createEnvVars(map[string]string{
+		"REPORT_MAX_CATCHUP": "0",
+		"REPORT_JOB_WORKERS": "0",
+	})
+	env, err = LoadEnv()
+
+	suite.Error(err)
+	suite.Nil(env)
+
+	suite.createEnvVars(map[string]string{
+		"REPORT_JOB_WORKERS":   "1",
+		"REPORT_JOB_RETENTION": "-1h",
+	})
+	env, err = LoadEnv()
+
+	suite.Error(err)
+	suite.Nil(env)
+
+	suite.createEnvVars(map[string]string{
+		"REPORT_JOB_RETENTION":     "0s",
+		"REPORT_DEAD_LETTER_LIMIT": "-1",
+	})
+	env, err = LoadEnv()
+
+	suite.Error(err)
+	suite.Nil(env)
+
+	suite.createEnvVars(map[string]string{"REPORT_DEAD_LETTER_LIMIT": "0"})
+	env, err = LoadEnv()
+
+	suite.NoError(err)
+	suite.Equal("0 8 * * MON-FRI", env.ReportEnv.Schedule)
+	suite.Equal("schedule", env.ReportEnv.Window)
+	suite.Equal(time.Minute, env.ReportEnv.LockTTL)
+	suite.Equal(time.Duration(0), env.ReportEnv.RunRetention)
+	suite.Equal(0, env.ReportEnv.MaxCatchUp)
+	suite.Equal(1, env.ReportEnv.JobWorkers)
+	suite.Equal(time.Duration(0), env.ReportEnv.JobRetention)
+	suite.Equal(0, env.ReportEnv.DeadLetterLimit)
+}
+
+func (suite *ViperSuite) TestLoadEnvReportRecipients() {
+	envContent := map[string]string{
+		"JWT_SECRET_KEY":    "test_jwt_secret",
+		"MAIL_USERNAME":     "test@example.com",
+		"REPORT_RECIPIENTS": "ops@example.com, dev@example.com",
+		"REPORT_TIMEZONE":   "Asia/Ho_Chi_Minh",
+	}
+
+	suite.createEnvVars(envContent)
+	env, err := LoadEnv()
+	suite.NoError(err)
+
+	suite.Equal([]ReportScheduleEnv{{
+		Name:       "default",
+		Schedule:   "0 0 * * *",
+		Timezone:   "Asia/Ho_Chi_Minh",
+		Window:     "day",
+		Channel:    "email",
+		Recipients: []string{"ops@example.com", "dev@example.com"},
+	}}, env.ReportEnv.Schedules)
+}
+
+func (suite *ViperSuite) TestLoadEnvReportSchedulesDisabled() {
+	suite.createEnvVars(map[string]string{
+		"JWT_SECRET_KEY":    "test_jwt_secret",
+		"MAIL_USERNAME":     "test@example.com",
+		"REPORT_RECIPIENTS": "ignored@example.com",
+		"REPORT_SCHEDULES":  "[]",
+	})
+	env, err := LoadEnv()
+	suite.NoError(err)
+
+	suite.Empty(env.ReportEnv.Schedules)
+}
+
+func (suite *ViperSuite) TestLoadEnvReportEmailAttachments() {
+	suite.createEnvVars(map[string]string{
+		"JWT_SECRET_KEY":           "test_jwt_secret",
+		"MAIL_USERNAME":            "test@example.com",
+		"REPORT_EMAIL_ATTACHMENTS": " csv , pdf",
+	})
+	env, err := LoadEnv()
+	suite.NoError(err)
+	suite.Equal([]string{"csv", "pdf"}, env.ReportEnv.EmailAttachments)
+
+	suite.createEnvVars(map[string]string{"REPORT_EMAIL_ATTACHMENTS": "csv,xlsx"})
+	env, err = LoadEnv()
+	suite.EqualError(err, `report email attachment "xlsx" is not supported`)
+	suite.Nil(env)
+}
+
+func (suite *ViperSuite) TestLoadEnvReportRecipientPreferences() {
+	suite.createEnvVars(map[string]string{
+		"JWT_SECRET_KEY":  "test_jwt_secret",
+		"MAIL_USERNAME":   "test@example.com",
+		"REPORT_TIMEZONE": "Europe/Berlin",
+		"REPORT_RECIPIENT_PREFERENCES": `[
+			{"address": "ops@example.vn", "locale": "vi", "timezone": "Asia/Ho_Chi_Minh"},
+			{"address": "ops@example.de"}
+		]`,
+	})
+	env, err := LoadEnv()
+	suite.NoError(err)
+
+	suite.Equal([]RecipientPreferenceEnv{
+		{Address: "ops@example.vn", Locale: "vi", Timezone: "Asia/Ho_Chi_Minh"},
+		{Address: "ops@example.de", Locale: "en", Timezone: "Europe/Berlin"},
+	}, env.ReportEnv.Recipients)
+}
+
+func (suite *ViperSuite) TestLoadEnvInvalidReportLocalization() {
+	suite.createEnvVars(map[string]string{
+		"JWT_SECRET_KEY": "test_jwt_secret",
+		"MAIL_USERNAME":  "test@example.com",
+		"REPORT_LOCALE":  "fr",
+	})
+	env, err := LoadEnv()
+	suite.EqualError(err, `report locale "fr" is not supported`)
+	suite.Nil(env)
+
+	invalidPreferences := []string{
+		`{"address": "ops@example.com"}`,
+		`[{"locale": "vi"}]`,
+		`[{"address": "ops@example.com", "locale": "fr"}]`,
+		`[{"address": "ops@example.com", "timezone": "Mars/Olympus_Mons"}]`,
+		`[{"address": "ops@example.com"}, {"address": "OPS@example.com", "locale": "vi"}]`,
+	}
+	for _, preferences := range invalidPreferences {
+		suite.createEnvVars(map[string]string{
+			"REPORT_LOCALE":                "vi",
+			"REPORT_RECIPIENT_PREFERENCES": preferences,
+		})
+		env, err := LoadEnv()
+
+		suite.Error(err, preferences)
+		suite.Nil(env)
+	}
+}
+
+func (suite *ViperSuite) TestLoadEnvReportSchedules() {
+	envContent := map[string]string{
+		"JWT_SECRET_KEY":       "test_jwt_secret",
+		"MAIL_USERNAME":        "test@example.com",
+		"REPORT_RECIPIENTS":    "ignored@example.com",
+		"REPORT_WEBHOOK_HOSTS": "example.com",
+		"REPORT_SCHEDULES": `[
+			{"name": "daily", "recipients": ["ops@example.com"], "template": "executive"},
+			{"name": "standup", "schedule": "0 8 * * MON-FRI", "window": "schedule", "channel": "slack", "recipients": ["https://hooks.slack.com/services/T000/B000/XXX"]},
+			{"name": "hourly", "schedule": "0 * * * *", "window": "1h", "channel": "webhook", "recipients": ["https://example.com/hook"]},
+			{"name": "monthly", "schedule": "0 8 1 * *", "window": "last_month", "recipients": ["ops@example.com"]},
+			{"name": "rolling", "schedule": "0 8 * * MON", "window": "now-7d/d..now/d", "recipients": ["ops@example.com"]},
+			{"name": "payments", "recipients": ["payments@example.com"], "filter": {"pattern": "pay-*", "labels": {"team": "payments"}}}
+		]`,
+	}
+
+	suite.createEnvVars(envContent)
+	env, err := LoadEnv()
+	suite.NoError(err)
+
+	suite.Len(env.ReportEnv.Schedules, 6)
+	suite.Equal(ReportScheduleEnv{
+		Name:       "daily",
+		Schedule:   "0 0 * * *",
+		Timezone:   "UTC",
+		Window:     "day",
+		Channel:    "email",
+		Recipients: []string{"ops@example.com"},
+		Template:   "executive",
+	}, env.ReportEnv.Schedules[0])
+	suite.Equal("0 8 * * MON-FRI", env.ReportEnv.Schedules[1].Schedule)
+	suite.Equal("slack", env.ReportEnv.Schedules[1].Channel)
+	suite.Equal("1h", env.ReportEnv.Schedules[2].Window)
+	suite.Equal("last_month", env.ReportEnv.Schedules[3].Window)
+	suite.Equal("now-7d/d..now/d", env.ReportEnv.Schedules[4].Window)
+	suite.Equal(entities.ContainerFilter{Pattern: "pay-*", Labels: map[string]string{"team": "payments"}}, env.ReportEnv.Schedules[5].Filter)
+}
+
+func (suite *ViperSuite) TestLoadEnvInvalidReportSchedules() {
+	invalidSchedules := []string{
+		`{"name": "daily"}`,
+		`[{"recipients": ["ops@example.com"]}]`,
+		`[{"name": "daily", "schedule": "daily", "recipients": ["ops@example.com"]}]`,
+		`[{"name": "daily", "window": "-1h", "recipients": ["ops@example.com"]}]`,
+		`[{"name": "daily", "window": "last_year", "recipients": ["ops@example.com"]}]`,
+		`[{"name": "daily", "window": "now-7q", "recipients": ["ops@example.com"]}]`,
+		`[{"name": "daily", "channel": "sms", "recipients": ["+84000000000"]}]`,
+		`[{"name": "daily", "recipients": []}]`,
+		`[{"name": "daily", "recipients": ["not an email"]}]`,
+		`[{"name": "daily", "channel": "slack", "recipients": ["http://hooks.slack.com/services/T000/B000/XXX"]}]`,
+		`[{"name": "daily", "channel": "webhook", "recipients": ["https://169.254.169.254/latest/meta-data"]}]`,
+		`[{"name": "daily", "recipients": ["ops@example.com"], "filter": {"pattern": "api-["}}]`,
+		`[{"name": "daily", "recipients": ["ops@example.com"], "filter": {"labels": {"": "payments"}}}]`,
+		`[{"name": "daily", "recipients": ["a@example.com"]}, {"name": "daily", "recipients": ["b@example.com"]}]`,
+	}
+
+	for _, schedules := range invalidSchedules {
+		suite.createEnvVars(map[string]string{
+			"JWT_SECRET_KEY":   "test_jwt_secret",
+			"MAIL_USERNAME":    "test@example.com",
+			"REPORT_SCHEDULES": schedules,
+		})
+		env, err := LoadEnv()
+
+		suite.Error(err, schedules)
+		suite.Nil(env)
+	}
+}
+
+func (suite *ViperSuite) TestLoadEnvReportTargetHosts() {
+	suite.createEnvVars(map[string]string{
+		"JWT_SECRET_KEY":       "test_jwt_secret",
+		"MAIL_USERNAME":        "test@example.com",
+		"REPORT_WEBHOOK_HOSTS": "example.com, *.example.org",
+	})
+	env, err := LoadEnv()
+	suite.NoError(err)
+
+	suite.Equal(TargetHostsEnv{
+		Slack:   []string{"hooks.slack.com"},
+		Teams:   []string{"*.webhook.office.com"},
+		Webhook: []string{"example.com", "*.example.org"},
+	}, env.ReportEnv.TargetHosts)
+}
+
+func (suite *ViperSuite) TestValidateReportTarget() {
+	targetHosts := TargetHostsEnv{
+		Slack:   []string{"hooks.slack.com"},
+		Teams:   []string{"*.webhook.office.com"},
+		Webhook: []string{"example.com"},
+	}
+
+	valid := []struct{ channel, target string }{
+		{"email", "ops@example.com"},
+		{"slack", "https://hooks.slack.com/services/T000/B000/XXX"},
+		{"teams", "https://contoso.webhook.office.com/webhookb2/XXX"},
+		{"webhook", "https://EXAMPLE.com/hook"},
+	}
+	for _, test := range valid {
+		suite.NoError(ValidateReportTarget(targetHosts, test.channel, test.target), test.target)
+	}
+
+	invalid := []struct{ channel, target string }{
+		{"email", "Ops <ops@example.com>"},
+		{"email", "https://hooks.slack.com/services/T000/B000/XXX"},
+		{"slack", "http://hooks.slack.com/services/T000/B000/XXX"},
+		{"slack", "https://hooks.slack.com.evil.com/services"},
+		{"slack", "https://user@hooks.slack.com/services"},
+		{"teams", "https://webhook.office.com/webhookb2/XXX"},
+		{"webhook", "https://127.0.0.1/hook"},
+		{"webhook", "https://sub.example.com/hook"},
+		{"sms", "+84000000000"},
+	}
+	for _, test := range invalid {
+		suite.ErrorIs(ValidateReportTarget(targetHosts, test.channel, test.target), ErrInvalidReportTarget, test.target)
+	}
+}
@@ -0,0 +1,196 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vnFuhung2903/vcs-report-service/interfaces"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/env"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	idempotencyStoreKeyPrefix = "idempotency:"
+)
+
+type IIdempotencyMiddleware interface {
+	Handle() gin.HandlerFunc
+}
+
+type idempotencyMiddleware struct {
+	store       interfaces.IIdempotencyStore
+	ttl         time.Duration
+	inFlightTTL time.Duration
+}
+
+func NewIdempotencyMiddleware(store interfaces.IIdempotencyStore, env env.IdempotencyEnv) IIdempotencyMiddleware {
+	return &idempotencyMiddleware{
+		store:       store,
+		ttl:         env.TTL,
+		inFlightTTL: env.InFlightTTL,
+	}
+}
+
+// Handle makes the request idempotent when it carries an Idempotency-Key
+// header. Keys are scoped to the authenticated user, so it must run after
+// RequireScope. The first request with a key is processed and its response
+// kept for the configured TTL; repeats of the same request get that response
+// back without being processed again. While the request is in flight the key
+// only lives for the shorter in-flight TTL and is renewed until the handler
+// returns, so a crashed instance does not block the key for the full TTL
+// while a slow send keeps it. Server errors release the key so that the
+// request can be retried.
+func (m *idempotencyMiddleware) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid idempotency key"})
+			c.Abort()
+			return
+		}
+
+		fingerprint, err := requestFingerprint(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			c.Abort()
+			return
+		}
+
+		token, err := reservationToken()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check idempotency key"})
+			c.Abort()
+			return
+		}
+
+		storeKey := idempotencyStoreKeyPrefix + c.GetString("userId") + ":" + key
+		existing, err := m.store.Reserve(c.Request.Context(), storeKey, interfaces.IdempotencyRecord{Fingerprint: fingerprint, Token: token}, m.inFlightTTL)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check idempotency key"})
+			c.Abort()
+			return
+		}
+		if existing != nil {
+			m.replay(c, *existing, fingerprint)
+			return
+		}
+
+		ctx := context.WithoutCancel(c.Request.Context())
+		done := make(chan struct{})
+		renewed := make(chan struct{})
+		go func() {
+			defer close(renewed)
+			m.renew(ctx, storeKey, token, done)
+		}()
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+		close(done)
+		<-renewed
+
+		if recorder.Status() >= http.StatusInternalServerError {
+			m.store.Release(ctx, storeKey, token)
+			return
+		}
+
+		err = m.store.Complete(ctx, storeKey, token, interfaces.IdempotencyRecord{
+			Fingerprint: fingerprint,
+			StatusCode:  recorder.Status(),
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		}, m.ttl)
+		if err != nil {
+			m.store.Release(ctx, storeKey, token)
+		}
+	}
+}
+
+// renew keeps the reservation alive until done is closed or the reservation
+// is lost.
+func (m *idempotencyMiddleware) renew(ctx context.Context, key string, token string, done <-chan struct{}) {
+	ticker := time.NewTicker(m.inFlightTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := m.store.Renew(ctx, key, token, m.inFlightTTL); errors.Is(err, interfaces.ErrReservationLost) {
+				return
+			}
+		}
+	}
+}
+
+func (m *idempotencyMiddleware) replay(c *gin.Context, record interfaces.IdempotencyRecord, fingerprint string) {
+	switch {
+	case record.Fingerprint != fingerprint:
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency key was used for a different request"})
+	case !record.Completed:
+		c.JSON(http.StatusConflict, gin.H{"error": "Request with this idempotency key is still in progress"})
+	default:
+		c.Header(IdempotentReplayedHeader, "true")
+		c.Data(record.StatusCode, record.ContentType, record.Body)
+	}
+	c.Abort()
+}
+
+// reservationToken tells this request's reservation apart from any later
+// reservation of the same key.
+func reservationToken() (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
+
+// requestFingerprint identifies a request by its method, path, query and
+// body, leaving the body readable for the handler.
+func requestFingerprint(c *gin.Context) (string, error) {
+	var body []byte
+	if c.Request.Body != nil {
+		var err error
+		body, err = io.ReadAll(c.Request.Body)
+		if err != nil {
+			return "", err
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(c.Request.Method + "\n" + c.Request.URL.Path + "\n" + c.Request.URL.Query().Encode() + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
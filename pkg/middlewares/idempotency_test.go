@@ -0,0 +1,234 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/vnFuhung2903/vcs-report-service/interfaces"
+	mockInterfaces "github.com/vnFuhung2903/vcs-report-service/mocks/interfaces"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/env"
+)
+
+type IdempotencyMiddlewareSuite struct {
+	suite.Suite
+	ctrl        *gomock.Controller
+	miniRedis   *miniredis.Miniredis
+	redisClient *redis.Client
+	router      *gin.Engine
+	calls       int
+	status      int
+}
+
+func (s *IdempotencyMiddlewareSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+
+	var err error
+	s.miniRedis, err = miniredis.Run()
+	s.Require().NoError(err)
+	s.redisClient = redis.NewClient(&redis.Options{Addr: s.miniRedis.Addr()})
+
+	s.calls = 0
+	s.status = http.StatusOK
+	s.router = s.newRouter(interfaces.NewIdempotencyStore(s.redisClient))
+}
+
+func (s *IdempotencyMiddlewareSuite) TearDownTest() {
+	s.redisClient.Close()
+	s.miniRedis.Close()
+	s.ctrl.Finish()
+}
+
+func TestIdempotencyMiddlewareSuite(t *testing.T) {
+	suite.Run(t, new(IdempotencyMiddlewareSuite))
+}
+
+func (s *IdempotencyMiddlewareSuite) newRouter(store interfaces.IIdempotencyStore) *gin.Engine {
+	middleware := NewIdempotencyMiddleware(store, env.IdempotencyEnv{TTL: time.Hour, InFlightTTL: time.Minute})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("userId", c.GetHeader("X-User"))
+		c.Next()
+	})
+	handler := func(c *gin.Context) {
+		s.calls++
+		c.JSON(s.status, gin.H{"call": s.calls})
+	}
+	router.GET("/report/mail", middleware.Handle(), handler)
+	router.POST("/report/jobs", middleware.Handle(), handler)
+	return router
+}
+
+func (s *IdempotencyMiddlewareSuite) serve(method string, path string, key string, user string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	req.Header.Set("X-User", user)
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func (s *IdempotencyMiddlewareSuite) callOf(w *httptest.ResponseRecorder) int {
+	var response map[string]int
+	s.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	return response["call"]
+}
+
+func (s *IdempotencyMiddlewareSuite) TestWithoutKey() {
+	s.serve(http.MethodGet, "/report/mail?email=a@example.com", "", "user-1", "")
+	s.serve(http.MethodGet, "/report/mail?email=a@example.com", "", "user-1", "")
+
+	s.Equal(2, s.calls)
+}
+
+func (s *IdempotencyMiddlewareSuite) TestReplaysCompletedRequest() {
+	first := s.serve(http.MethodGet, "/report/mail?email=a@example.com&start_time=2024-01-01", "key-1", "user-1", "")
+	s.Equal(http.StatusOK, first.Code)
+	s.Empty(first.Header().Get(IdempotentReplayedHeader))
+
+	second := s.serve(http.MethodGet, "/report/mail?start_time=2024-01-01&email=a@example.com", "key-1", "user-1", "")
+	s.Equal(http.StatusOK, second.Code)
+	s.Equal("true", second.Header().Get(IdempotentReplayedHeader))
+	s.Equal("application/json; charset=utf-8", second.Header().Get("Content-Type"))
+	s.Equal(first.Body.String(), second.Body.String())
+	s.Equal(1, s.calls)
+}
+
+func (s *IdempotencyMiddlewareSuite) TestReplaysClientErrors() {
+	s.status = http.StatusBadRequest
+	s.serve(http.MethodPost, "/report/jobs", "key-1", "user-1", `{"channel":"fax"}`)
+	w := s.serve(http.MethodPost, "/report/jobs", "key-1", "user-1", `{"channel":"fax"}`)
+
+	s.Equal(http.StatusBadRequest, w.Code)
+	s.Equal(1, s.callOf(w))
+	s.Equal(1, s.calls)
+}
+
+func (s *IdempotencyMiddlewareSuite) TestKeysAreScopedToUser() {
+	s.serve(http.MethodGet, "/report/mail", "key-1", "user-1", "")
+	w := s.serve(http.MethodGet, "/report/mail", "key-1", "user-2", "")
+
+	s.Equal(2, s.callOf(w))
+	s.Equal(2, s.calls)
+}
+
+func (s *IdempotencyMiddlewareSuite) TestServerErrorReleasesKey() {
+	s.status = http.StatusInternalServerError
+	s.serve(http.MethodGet, "/report/mail", "key-1", "user-1", "")
+
+	s.status = http.StatusOK
+	w := s.serve(http.MethodGet, "/report/mail", "key-1", "user-1", "")
+	s.Equal(http.StatusOK, w.Code)
+	s.Equal(2, s.callOf(w))
+}
+
+func (s *IdempotencyMiddlewareSuite) TestDifferentRequest() {
+	s.serve(http.MethodPost, "/report/jobs", "key-1", "user-1", `{"target":"a@example.com"}`)
+	w := s.serve(http.MethodPost, "/report/jobs", "key-1", "user-1", `{"target":"b@example.com"}`)
+
+	s.Equal(http.StatusUnprocessableEntity, w.Code)
+	s.Equal(1, s.calls)
+}
+
+func (s *IdempotencyMiddlewareSuite) TestRequestInProgress() {
+	store := interfaces.NewIdempotencyStore(s.redisClient)
+	req := httptest.NewRequest(http.MethodGet, "/report/mail", nil)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = req
+	fingerprint, err := requestFingerprint(c)
+	s.Require().NoError(err)
+	_, err = store.Reserve(c.Request.Context(), "idempotency:user-1:key-1", interfaces.IdempotencyRecord{Fingerprint: fingerprint}, time.Hour)
+	s.Require().NoError(err)
+
+	w := s.serve(http.MethodGet, "/report/mail", "key-1", "user-1", "")
+	s.Equal(http.StatusConflict, w.Code)
+	s.Equal(0, s.calls)
+}
+
+func (s *IdempotencyMiddlewareSuite) TestInFlightTTL() {
+	var inFlight time.Duration
+	middleware := NewIdempotencyMiddleware(interfaces.NewIdempotencyStore(s.redisClient), env.IdempotencyEnv{TTL: time.Hour, InFlightTTL: time.Minute})
+	s.router.GET("/ttl", middleware.Handle(), func(c *gin.Context) {
+		inFlight = s.miniRedis.TTL("idempotency:user-1:key-1")
+		c.Status(http.StatusNoContent)
+	})
+
+	s.serve(http.MethodGet, "/ttl", "key-1", "user-1", "")
+	s.Equal(time.Minute, inFlight)
+	s.Equal(time.Hour, s.miniRedis.TTL("idempotency:user-1:key-1"))
+}
+
+func (s *IdempotencyMiddlewareSuite) TestRenewsReservationWhileInFlight() {
+	var inFlight bool
+	middleware := NewIdempotencyMiddleware(interfaces.NewIdempotencyStore(s.redisClient), env.IdempotencyEnv{TTL: time.Hour, InFlightTTL: 30 * time.Millisecond})
+	s.router.GET("/slow", middleware.Handle(), func(c *gin.Context) {
+		for i := 0; i < 4; i++ {
+			time.Sleep(20 * time.Millisecond)
+			s.miniRedis.FastForward(20 * time.Millisecond)
+		}
+		inFlight = s.miniRedis.Exists("idempotency:user-1:key-1")
+		c.Status(http.StatusNoContent)
+	})
+
+	s.serve(http.MethodGet, "/slow", "key-1", "user-1", "")
+	s.True(inFlight)
+	s.Equal(time.Hour, s.miniRedis.TTL("idempotency:user-1:key-1"))
+}
+
+func (s *IdempotencyMiddlewareSuite) TestBodyStaysReadable() {
+	var body []byte
+	middleware := NewIdempotencyMiddleware(interfaces.NewIdempotencyStore(s.redisClient), env.IdempotencyEnv{TTL: time.Hour, InFlightTTL: time.Minute})
+	s.router.POST("/echo", middleware.Handle(), func(c *gin.Context) {
+		body, _ = c.GetRawData()
+		c.Status(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/echo", bytes.NewBufferString(`{"target":"a@example.com"}`))
+	req.Header.Set(IdempotencyKeyHeader, "key-1")
+	s.router.ServeHTTP(httptest.NewRecorder(), req)
+
+	s.Equal(`{"target":"a@example.com"}`, string(body))
+}
+
+func (s *IdempotencyMiddlewareSuite) TestKeyTooLong() {
+	w := s.serve(http.MethodGet, "/report/mail", strings.Repeat("k", 256), "user-1", "")
+
+	s.Equal(http.StatusBadRequest, w.Code)
+	s.Equal(0, s.calls)
+}
+
+func (s *IdempotencyMiddlewareSuite) TestStoreError() {
+	store := mockInterfaces.NewMockIIdempotencyStore(s.ctrl)
+	store.EXPECT().Reserve(gomock.Any(), "idempotency:user-1:key-1", gomock.Any(), time.Minute).Return(nil, errors.New("redis connection failed"))
+	s.router = s.newRouter(store)
+
+	w := s.serve(http.MethodGet, "/report/mail", "key-1", "user-1", "")
+	s.Equal(http.StatusInternalServerError, w.Code)
+	s.Equal(0, s.calls)
+}
+
+func (s *IdempotencyMiddlewareSuite) TestCompleteErrorReleasesKey() {
+	store := mockInterfaces.NewMockIIdempotencyStore(s.ctrl)
+	store.EXPECT().Reserve(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+	store.EXPECT().Complete(gomock.Any(), "idempotency:user-1:key-1", gomock.Any(), gomock.Any(), time.Hour).Return(errors.New("redis connection failed"))
+	store.EXPECT().Release(gomock.Any(), "idempotency:user-1:key-1", gomock.Any()).Return(nil)
+	s.router = s.newRouter(store)
+
+	w := s.serve(http.MethodGet, "/report/mail", "key-1", "user-1", "")
+	s.Equal(http.StatusOK, w.Code)
+}
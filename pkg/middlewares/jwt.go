@@ -70,6 +70,7 @@ func (m *jwtMiddleware) RequireScope(requiredScope string) gin.HandlerFunc {
 			return
 		}
 
+		c.Set("scopes", tokens)
 		if sub, ok := claims["sub"].(string); ok {
 			c.Set("userId", sub)
 		} else {
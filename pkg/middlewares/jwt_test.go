@@ -64,6 +64,7 @@ func (s *JWTMiddlewareSuite) TestRequireScope() {
 		userId, exists := c.Get("userId")
 		s.True(exists)
 		s.Equal("123", userId)
+		s.Equal([]string{"read", "write"}, c.GetStringSlice("scopes"))
 		c.JSON(http.StatusOK, gin.H{"message": "success"})
 	})
 
@@ -351,6 +352,7 @@ func (s *JWTMiddlewareSuite) TestRequireScopeWithNonStringScopes() {
 		userId, exists := c.Get("userId")
 		s.True(exists)
 		s.Equal("123", userId)
+		s.Equal([]string{"read", "write"}, c.GetStringSlice("scopes"))
 		c.JSON(http.StatusOK, gin.H{"message": "success"})
 	})
 
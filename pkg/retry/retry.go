@@ -0,0 +1,116 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/vnFuhung2903/vcs-report-service/pkg/env"
+)
+
+type Policy struct {
+	Attempts   int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	Jitter     float64
+}
+
+func NewPolicy(retryEnv env.RetryEnv) Policy {
+	return Policy{
+		Attempts:   retryEnv.Attempts,
+		Backoff:    retryEnv.Backoff,
+		MaxBackoff: retryEnv.MaxBackoff,
+		Jitter:     retryEnv.Jitter,
+	}
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks err as not worth retrying, so that Do returns it at once.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+type attemptsError struct {
+	err      error
+	attempts int
+}
+
+func (e *attemptsError) Error() string {
+	return e.err.Error()
+}
+
+func (e *attemptsError) Unwrap() error {
+	return e.err
+}
+
+// Attempts returns how many times Do called its function before failing with
+// err: 1 for an error that did not come from Do and 0 for nil.
+func Attempts(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exhausted *attemptsError
+	if errors.As(err, &exhausted) {
+		return exhausted.attempts
+	}
+	return 1
+}
+
+// Do calls fn until it succeeds, the attempts are exhausted or ctx is done,
+// and returns the last error, from which Attempts recovers the number of
+// calls. A policy without attempts calls fn once, and an error wrapped with
+// Permanent stops the retries and is returned without that mark.
+func (p Policy) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	attempts := max(p.Attempts, 1)
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return &attemptsError{err: permanent.err, attempts: attempt}
+		}
+		if err == nil {
+			return nil
+		}
+		if attempt >= attempts {
+			return &attemptsError{err: err, attempts: attempt}
+		}
+
+		timer := time.NewTimer(p.Delay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return &attemptsError{err: err, attempts: attempt}
+		case <-timer.C:
+		}
+	}
+}
+
+// Delay returns how long to wait after the given failed attempt: Backoff
+// doubled for every previous attempt, capped at MaxBackoff and spread by up to
+// Jitter of its value in either direction.
+func (p Policy) Delay(attempt int) time.Duration {
+	delay := float64(p.Backoff) * math.Pow(2, float64(attempt-1))
+	if p.MaxBackoff > 0 {
+		delay = math.Min(delay, float64(p.MaxBackoff))
+	}
+	if p.Jitter > 0 {
+		delay *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+	return time.Duration(delay)
+}
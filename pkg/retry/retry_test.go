@@ -0,0 +1,128 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/vnFuhung2903/vcs-report-service/pkg/env"
+)
+
+type RetrySuite struct {
+	suite.Suite
+	ctx context.Context
+}
+
+func (s *RetrySuite) SetupTest() {
+	s.ctx = context.Background()
+}
+
+func TestRetrySuite(t *testing.T) {
+	suite.Run(t, new(RetrySuite))
+}
+
+func (s *RetrySuite) TestNewPolicy() {
+	policy := NewPolicy(env.RetryEnv{Attempts: 4, Backoff: time.Second, MaxBackoff: time.Minute, Jitter: 0.2})
+	s.Equal(Policy{Attempts: 4, Backoff: time.Second, MaxBackoff: time.Minute, Jitter: 0.2}, policy)
+}
+
+func (s *RetrySuite) TestDoSucceedsAfterRetries() {
+	calls := 0
+	err := Policy{Attempts: 3, Backoff: time.Millisecond}.Do(s.ctx, func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+
+	s.NoError(err)
+	s.Equal(3, calls)
+}
+
+func (s *RetrySuite) TestDoExhaustsAttempts() {
+	calls := 0
+	err := Policy{Attempts: 3, Backoff: time.Millisecond}.Do(s.ctx, func(ctx context.Context) error {
+		calls++
+		return errors.New("connection refused")
+	})
+
+	s.EqualError(err, "connection refused")
+	s.Equal(3, calls)
+	s.Equal(3, Attempts(err))
+}
+
+func (s *RetrySuite) TestDoWithoutAttempts() {
+	calls := 0
+	err := Policy{}.Do(s.ctx, func(ctx context.Context) error {
+		calls++
+		return errors.New("connection refused")
+	})
+
+	s.Error(err)
+	s.Equal(1, calls)
+}
+
+func (s *RetrySuite) TestDoStopsOnPermanentError() {
+	calls := 0
+	cause := errors.New("bad request")
+	err := Policy{Attempts: 3, Backoff: time.Millisecond}.Do(s.ctx, func(ctx context.Context) error {
+		calls++
+		return Permanent(cause)
+	})
+
+	s.ErrorIs(err, cause)
+	s.EqualError(err, "bad request")
+	s.Equal(1, calls)
+	s.Equal(1, Attempts(err))
+	s.NoError(Permanent(nil))
+}
+
+func (s *RetrySuite) TestDoStopsWhenContextDone() {
+	ctx, cancel := context.WithCancel(s.ctx)
+	calls := 0
+	err := Policy{Attempts: 5, Backoff: time.Hour}.Do(ctx, func(ctx context.Context) error {
+		calls++
+		cancel()
+		return errors.New("connection refused")
+	})
+
+	s.Error(err)
+	s.Equal(1, calls)
+}
+
+func (s *RetrySuite) TestDelay() {
+	policy := Policy{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+	s.Equal(time.Second, policy.Delay(1))
+	s.Equal(2*time.Second, policy.Delay(2))
+	s.Equal(4*time.Second, policy.Delay(3))
+	s.Equal(5*time.Second, policy.Delay(4))
+	s.Equal(5*time.Second, policy.Delay(64))
+
+	policy.Jitter = 0.5
+	for range 100 {
+		delay := policy.Delay(2)
+		s.GreaterOrEqual(delay, time.Second)
+		s.LessOrEqual(delay, 3*time.Second)
+	}
+}
+
+func (s *RetrySuite) TestAttempts() {
+	calls := 0
+	err := Policy{Attempts: 3, Backoff: time.Millisecond}.Do(s.ctx, func(ctx context.Context) error {
+		calls++
+		if calls == 2 {
+			return Permanent(errors.New("mailbox unavailable"))
+		}
+		return errors.New("connection refused")
+	})
+
+	s.EqualError(err, "mailbox unavailable")
+	s.Equal(2, Attempts(fmt.Errorf("send: %w", err)))
+	s.Equal(1, Attempts(errors.New("connection refused")))
+	s.Equal(0, Attempts(nil))
+}
@@ -0,0 +1,179 @@
+package timerange
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidExpression = errors.New("invalid time expression")
+
+// namedRanges maps every named range to the relative expression it stands for.
+var namedRanges = map[string]string{
+	"today":         "now/d..now",
+	"yesterday":     "now/d-1d..now/d",
+	"week_to_date":  "now/w..now",
+	"last_week":     "now/w-1w..now/w",
+	"month_to_date": "now/M..now",
+	"last_month":    "now/M-1M..now/M",
+}
+
+// toDateRanges maps every to-date range to the unit its period starts on and
+// to the full period before it, which stands in for the to-date range at the
+// very first instant of its period, when nothing has elapsed yet.
+var toDateRanges = map[string]struct {
+	unit     byte
+	previous string
+}{
+	"today":         {'d', "yesterday"},
+	"week_to_date":  {'w', "last_week"},
+	"month_to_date": {'M', "last_month"},
+}
+
+// Resolve returns the [start, end) window described by expr relative to now
+// in location. expr is a named range such as "yesterday" or "last_month", a
+// "<start>..<end>" pair of bounds, or a single bound ending at now, e.g.
+// "now-24h" or "now-7d/d". A to-date range such as "today" resolved exactly
+// at the start of its period covers the previous full period instead.
+func Resolve(expr string, now time.Time, location *time.Location) (time.Time, time.Time, error) {
+	now = now.In(location)
+	if toDate, ok := toDateRanges[expr]; ok {
+		if start, err := round(now, toDate.unit); err == nil && start.Equal(now) {
+			expr = toDate.previous
+		}
+	}
+	if named, ok := namedRanges[expr]; ok {
+		expr = named
+	}
+
+	start, end := expr, ""
+	if before, after, found := strings.Cut(expr, ".."); found {
+		start, end = before, after
+	}
+
+	startTime, err := ParseBound(start, now, location, false)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	endTime := now
+	if end != "" {
+		endTime, err = ParseBound(end, now, location, true)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+
+	if !startTime.Before(endTime) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: %q does not start before it ends", ErrInvalidExpression, expr)
+	}
+	return startTime, endTime, nil
+}
+
+// ParseBound parses one bound of a window: date math anchored at now such as
+// "now-7d/d", an RFC 3339 timestamp, which carries its own offset, or a date
+// in location. As the exclusive end of a window a date stands for the
+// midnight that follows it.
+func ParseBound(value string, now time.Time, location *time.Location, end bool) (time.Time, error) {
+	if operations, ok := strings.CutPrefix(value, "now"); ok {
+		return dateMath(now.In(location), operations)
+	}
+
+	if timestamp, err := time.Parse(time.RFC3339, value); err == nil {
+		return timestamp, nil
+	}
+
+	date, err := time.ParseInLocation(time.DateOnly, value, location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %q is neither a date (2006-01-02), an RFC 3339 timestamp nor relative to now", ErrInvalidExpression, value)
+	}
+	if end {
+		date = date.AddDate(0, 0, 1)
+	}
+	return date, nil
+}
+
+// dateMath applies the operations following "now" from left to right:
+// "+<n><unit>" and "-<n><unit>" shift t, "/<unit>" rounds it down to the
+// start of the unit. Units are y, M, w, d, h, m and s; weeks start on Monday.
+func dateMath(t time.Time, operations string) (time.Time, error) {
+	for operations != "" {
+		operator := operations[0]
+		switch operator {
+		case '/':
+			if len(operations) < 2 {
+				return time.Time{}, fmt.Errorf("%w: rounding without a unit", ErrInvalidExpression)
+			}
+			var err error
+			if t, err = round(t, operations[1]); err != nil {
+				return time.Time{}, err
+			}
+			operations = operations[2:]
+		case '+', '-':
+			digits := 1
+			for digits < len(operations) && operations[digits] >= '0' && operations[digits] <= '9' {
+				digits++
+			}
+			if digits == 1 || digits == len(operations) {
+				return time.Time{}, fmt.Errorf("%w: %q needs an amount and a unit", ErrInvalidExpression, operations)
+			}
+			amount, err := strconv.Atoi(operations[1:digits])
+			if err != nil {
+				return time.Time{}, fmt.Errorf("%w: %v", ErrInvalidExpression, err)
+			}
+			if operator == '-' {
+				amount = -amount
+			}
+			if t, err = shift(t, amount, operations[digits]); err != nil {
+				return time.Time{}, err
+			}
+			operations = operations[digits+1:]
+		default:
+			return time.Time{}, fmt.Errorf("%w: unexpected %q", ErrInvalidExpression, operator)
+		}
+	}
+	return t, nil
+}
+
+func shift(t time.Time, amount int, unit byte) (time.Time, error) {
+	switch unit {
+	case 'y':
+		return t.AddDate(amount, 0, 0), nil
+	case 'M':
+		return t.AddDate(0, amount, 0), nil
+	case 'w':
+		return t.AddDate(0, 0, 7*amount), nil
+	case 'd':
+		return t.AddDate(0, 0, amount), nil
+	case 'h':
+		return t.Add(time.Duration(amount) * time.Hour), nil
+	case 'm':
+		return t.Add(time.Duration(amount) * time.Minute), nil
+	case 's':
+		return t.Add(time.Duration(amount) * time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf("%w: unknown unit %q", ErrInvalidExpression, unit)
+}
+
+func round(t time.Time, unit byte) (time.Time, error) {
+	year, month, day := t.Date()
+	switch unit {
+	case 'y':
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, t.Location()), nil
+	case 'M':
+		return time.Date(year, month, 1, 0, 0, 0, 0, t.Location()), nil
+	case 'w':
+		sinceMonday := (int(t.Weekday()) + 6) % 7
+		return time.Date(year, month, day-sinceMonday, 0, 0, 0, 0, t.Location()), nil
+	case 'd':
+		return time.Date(year, month, day, 0, 0, 0, 0, t.Location()), nil
+	case 'h':
+		return time.Date(year, month, day, t.Hour(), 0, 0, 0, t.Location()), nil
+	case 'm':
+		return time.Date(year, month, day, t.Hour(), t.Minute(), 0, 0, t.Location()), nil
+	case 's':
+		return time.Date(year, month, day, t.Hour(), t.Minute(), t.Second(), 0, t.Location()), nil
+	}
+	return time.Time{}, fmt.Errorf("%w: unknown unit %q", ErrInvalidExpression, unit)
+}
@@ -0,0 +1,142 @@
+package timerange
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type TimeRangeSuite struct {
+	suite.Suite
+	saigon *time.Location
+	now    time.Time
+}
+
+func (s *TimeRangeSuite) SetupTest() {
+	var err error
+	s.saigon, err = time.LoadLocation("Asia/Ho_Chi_Minh")
+	s.Require().NoError(err)
+	// Wednesday 2024-03-13 02:30 in Saigon, still Tuesday in UTC.
+	s.now = time.Date(2024, 3, 12, 19, 30, 0, 0, time.UTC)
+}
+
+func TestTimeRangeSuite(t *testing.T) {
+	suite.Run(t, new(TimeRangeSuite))
+}
+
+func (s *TimeRangeSuite) at(year int, month time.Month, day int, hour int, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, s.saigon)
+}
+
+func (s *TimeRangeSuite) TestResolveNamedRanges() {
+	tests := []struct {
+		name  string
+		start time.Time
+		end   time.Time
+	}{
+		{"today", s.at(2024, 3, 13, 0, 0), s.at(2024, 3, 13, 2, 30)},
+		{"yesterday", s.at(2024, 3, 12, 0, 0), s.at(2024, 3, 13, 0, 0)},
+		{"week_to_date", s.at(2024, 3, 11, 0, 0), s.at(2024, 3, 13, 2, 30)},
+		{"last_week", s.at(2024, 3, 4, 0, 0), s.at(2024, 3, 11, 0, 0)},
+		{"month_to_date", s.at(2024, 3, 1, 0, 0), s.at(2024, 3, 13, 2, 30)},
+		{"last_month", s.at(2024, 2, 1, 0, 0), s.at(2024, 3, 1, 0, 0)},
+	}
+
+	for _, test := range tests {
+		start, end, err := Resolve(test.name, s.now, s.saigon)
+		s.NoError(err, test.name)
+		s.True(test.start.Equal(start), "%s: start %s", test.name, start)
+		s.True(test.end.Equal(end), "%s: end %s", test.name, end)
+	}
+}
+
+func (s *TimeRangeSuite) TestResolveToDateRangesAtPeriodStart() {
+	tests := []struct {
+		name  string
+		now   time.Time
+		start time.Time
+		end   time.Time
+	}{
+		{"today", s.at(2024, 3, 13, 0, 0), s.at(2024, 3, 12, 0, 0), s.at(2024, 3, 13, 0, 0)},
+		{"week_to_date", s.at(2024, 3, 11, 0, 0), s.at(2024, 3, 4, 0, 0), s.at(2024, 3, 11, 0, 0)},
+		{"month_to_date", s.at(2024, 3, 1, 0, 0), s.at(2024, 2, 1, 0, 0), s.at(2024, 3, 1, 0, 0)},
+		{"today", s.at(2024, 3, 13, 0, 0).Add(time.Nanosecond), s.at(2024, 3, 13, 0, 0), s.at(2024, 3, 13, 0, 0).Add(time.Nanosecond)},
+		{"month_to_date", s.at(2024, 3, 1, 0, 0).Add(time.Nanosecond), s.at(2024, 3, 1, 0, 0), s.at(2024, 3, 1, 0, 0).Add(time.Nanosecond)},
+	}
+
+	for _, test := range tests {
+		start, end, err := Resolve(test.name, test.now, s.saigon)
+		s.NoError(err, test.name)
+		s.True(test.start.Equal(start), "%s at %s: start %s", test.name, test.now, start)
+		s.True(test.end.Equal(end), "%s at %s: end %s", test.name, test.now, end)
+	}
+}
+
+func (s *TimeRangeSuite) TestResolveLastMonthFromLongMonth() {
+	start, end, err := Resolve("last_month", time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC), time.UTC)
+	s.NoError(err)
+	s.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), start)
+	s.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), end)
+}
+
+func (s *TimeRangeSuite) TestResolveExpressions() {
+	tests := []struct {
+		expr  string
+		start time.Time
+		end   time.Time
+	}{
+		{"now-24h", s.at(2024, 3, 12, 2, 30), s.at(2024, 3, 13, 2, 30)},
+		{"now-7d/d", s.at(2024, 3, 6, 0, 0), s.at(2024, 3, 13, 2, 30)},
+		{"now-1M/M..now/M", s.at(2024, 2, 1, 0, 0), s.at(2024, 3, 1, 0, 0)},
+		{"now/h-90m..now+1h/h", s.at(2024, 3, 13, 0, 30), s.at(2024, 3, 13, 3, 0)},
+		{"now/y", s.at(2024, 1, 1, 0, 0), s.at(2024, 3, 13, 2, 30)},
+		{"2024-03-01..2024-03-10", s.at(2024, 3, 1, 0, 0), s.at(2024, 3, 11, 0, 0)},
+		{"2024-03-12T00:00:00Z..now", time.Date(2024, 3, 12, 0, 0, 0, 0, time.UTC), s.at(2024, 3, 13, 2, 30)},
+	}
+
+	for _, test := range tests {
+		start, end, err := Resolve(test.expr, s.now, s.saigon)
+		s.NoError(err, test.expr)
+		s.True(test.start.Equal(start), "%s: start %s", test.expr, start)
+		s.True(test.end.Equal(end), "%s: end %s", test.expr, end)
+	}
+}
+
+func (s *TimeRangeSuite) TestResolveInvalidExpressions() {
+	for _, expr := range []string{
+		"",
+		"last_year",
+		"now-",
+		"now-7",
+		"now-d",
+		"now-7q",
+		"now/",
+		"now/q",
+		"now*2d",
+		"now+1d",
+		"now..now-1h",
+		"2024-03-01..soon",
+	} {
+		_, _, err := Resolve(expr, s.now, s.saigon)
+		s.ErrorIs(err, ErrInvalidExpression, expr)
+	}
+}
+
+func (s *TimeRangeSuite) TestParseBound() {
+	start, err := ParseBound("2024-03-10", s.now, s.saigon, false)
+	s.NoError(err)
+	s.True(s.at(2024, 3, 10, 0, 0).Equal(start))
+
+	end, err := ParseBound("2024-03-10", s.now, s.saigon, true)
+	s.NoError(err)
+	s.True(s.at(2024, 3, 11, 0, 0).Equal(end))
+
+	timestamp, err := ParseBound("2024-03-10T06:00:00+02:00", s.now, s.saigon, true)
+	s.NoError(err)
+	s.True(time.Date(2024, 3, 10, 4, 0, 0, 0, time.UTC).Equal(timestamp))
+
+	relative, err := ParseBound("now-1w/w", s.now, s.saigon, false)
+	s.NoError(err)
+	s.True(s.at(2024, 3, 4, 0, 0).Equal(relative))
+}
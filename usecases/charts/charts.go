@@ -0,0 +1,191 @@
+package charts
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/vnFuhung2903/vcs-report-service/dto"
+	"github.com/vnFuhung2903/vcs-report-service/entities"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/i18n"
+)
+
+// Charts are sized for the content column of the report email templates and
+// scale down with it on narrow screens.
+const chartWidth = 590.0
+
+const (
+	colorOn      = "#11998e"
+	colorOff     = "#ff416c"
+	colorPartial = "#f39c12"
+	colorNoData  = "#ecf0f1"
+	colorText    = "#2c3e50"
+	colorMuted   = "#7f8c8d"
+)
+
+// availabilityBuckets are narrower towards 100% since that is where SLA
+// targets sit and where most of a healthy fleet lands.
+var availabilityBuckets = []struct {
+	label string
+	lower float64
+	upper float64
+}{
+	{"< 50%", 0, 50},
+	{"50-90%", 50, 90},
+	{"90-95%", 90, 95},
+	{"95-99%", 95, 99},
+	{"99-99.9%", 99, 99.9},
+	{">= 99.9%", 99.9, 100},
+}
+
+// FleetBar renders the share of the window the fleet spent on and off as a
+// single stacked bar, labelled through localizer.
+func FleetBar(report dto.ReportResponse, localizer i18n.Localizer) template.HTML {
+	if report.ContainerCount == 0 {
+		return ""
+	}
+
+	var downtime float64
+	for _, container := range report.Containers {
+		downtime += container.DowntimeHours
+	}
+	up := chartWidth * clampPercent(report.Availability) / 100
+
+	svg := newSVG(60, localizer.T("chart.fleet"))
+	svg.rect(0, 0, chartWidth, 28, colorOff)
+	svg.rect(0, 0, up, 28, colorOn)
+	svg.text(0, 48, "start", colorText, localizer.T("chart.on", localizer.Percent(report.Availability), localizer.Number(report.TotalUptime, 2)))
+	svg.text(chartWidth, 48, "end", colorText, localizer.T("chart.off", localizer.Percent(100-report.Availability), localizer.Number(downtime, 2)))
+	return svg.html()
+}
+
+// AvailabilityHistogram renders how many containers fall in each
+// availability bucket, colouring buckets by where they sit against the SLA
+// target.
+func AvailabilityHistogram(report dto.ReportResponse, localizer i18n.Localizer) template.HTML {
+	if len(report.Containers) == 0 {
+		return ""
+	}
+
+	counts := make([]int, len(availabilityBuckets))
+	for _, container := range report.Containers {
+		for i, bucket := range availabilityBuckets {
+			if container.Availability < bucket.upper || i == len(availabilityBuckets)-1 {
+				counts[i]++
+				break
+			}
+		}
+	}
+	highest := 0
+	for _, count := range counts {
+		highest = max(highest, count)
+	}
+
+	const plotTop, plotHeight, barWidth = 20.0, 140.0, 60.0
+	slot := chartWidth / float64(len(availabilityBuckets))
+
+	svg := newSVG(190, localizer.T("chart.availability"))
+	svg.line(0, plotTop+plotHeight, chartWidth, plotTop+plotHeight, colorNoData)
+	for i, bucket := range availabilityBuckets {
+		color := colorOff
+		if bucket.lower >= report.SLATarget {
+			color = colorOn
+		} else if bucket.upper > report.SLATarget {
+			color = colorPartial
+		}
+
+		center := slot*float64(i) + slot/2
+		height := plotHeight * float64(counts[i]) / float64(highest)
+		svg.rect(center-barWidth/2, plotTop+plotHeight-height, barWidth, height, color)
+		svg.text(center, plotTop+plotHeight-height-5, "middle", colorText, localizer.Integer(counts[i]))
+		svg.text(center, plotTop+plotHeight+20, "middle", colorMuted, bucket.label)
+	}
+	return svg.html()
+}
+
+// Timelines renders one strip per container showing when it was on and off
+// during the report window, with the window bounds in the localizer's
+// timezone. Containers without a status timeline, which is the case for every
+// container of reports built with the aggregation statistic mode, are left
+// out.
+func Timelines(report dto.ReportResponse, localizer i18n.Localizer) template.HTML {
+	var containers []dto.ContainerReport
+	for _, container := range report.Containers {
+		if len(container.Timeline) > 0 {
+			containers = append(containers, container)
+		}
+	}
+	window := report.EndTime.Sub(report.StartTime)
+	if len(containers) == 0 || window <= 0 {
+		return ""
+	}
+
+	const labelWidth, rowHeight, stripHeight = 160.0, 18.0, 12.0
+	stripWidth := chartWidth - labelWidth
+	offset := func(t time.Time) float64 {
+		return labelWidth + stripWidth*float64(t.Sub(report.StartTime))/float64(window)
+	}
+
+	svg := newSVG(rowHeight*float64(len(containers))+20, localizer.T("chart.timeline"))
+	for i, container := range containers {
+		top := rowHeight * float64(i)
+		svg.text(0, top+stripHeight-1, "start", colorText, truncate(container.ContainerId, 24))
+		svg.rect(labelWidth, top, stripWidth, stripHeight, colorNoData)
+		for _, segment := range container.Timeline {
+			color := colorOff
+			if segment.Status == entities.ContainerOn {
+				color = colorOn
+			}
+			svg.rect(offset(segment.Start), top, offset(segment.End)-offset(segment.Start), stripHeight, color)
+		}
+	}
+	bottom := rowHeight*float64(len(containers)) + 12
+	svg.text(labelWidth, bottom, "start", colorMuted, localizer.DateTime(report.StartTime))
+	svg.text(chartWidth, bottom, "end", colorMuted, localizer.DateTime(report.EndTime))
+	return svg.html()
+}
+
+type svgBuilder struct {
+	b strings.Builder
+}
+
+func newSVG(height float64, title string) *svgBuilder {
+	svg := &svgBuilder{}
+	fmt.Fprintf(&svg.b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %.0f %.0f" width="100%%" role="img" aria-label="%s" font-family="Segoe UI, Tahoma, Geneva, Verdana, sans-serif" font-size="11">`, chartWidth, height, template.HTMLEscapeString(title))
+	return svg
+}
+
+func (s *svgBuilder) rect(x float64, y float64, width float64, height float64, fill string) {
+	if width <= 0 || height <= 0 {
+		return
+	}
+	fmt.Fprintf(&s.b, `<rect x="%.2f" y="%.2f" width="%.2f" height="%.2f" fill="%s"/>`, x, y, width, height, fill)
+}
+
+func (s *svgBuilder) line(x1 float64, y1 float64, x2 float64, y2 float64, stroke string) {
+	fmt.Fprintf(&s.b, `<line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f" stroke="%s"/>`, x1, y1, x2, y2, stroke)
+}
+
+func (s *svgBuilder) text(x float64, y float64, anchor string, fill string, content string) {
+	fmt.Fprintf(&s.b, `<text x="%.2f" y="%.2f" text-anchor="%s" fill="%s">%s</text>`, x, y, anchor, fill, template.HTMLEscapeString(content))
+}
+
+// html closes the document. The builder only ever writes escaped text, so
+// the result is safe to insert into the email template as is.
+func (s *svgBuilder) html() template.HTML {
+	s.b.WriteString("</svg>")
+	return template.HTML(s.b.String())
+}
+
+func clampPercent(value float64) float64 {
+	return max(0, min(100, value))
+}
+
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-3]) + "..."
+}
@@ -0,0 +1,116 @@
+package charts
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/vnFuhung2903/vcs-report-service/dto"
+	"github.com/vnFuhung2903/vcs-report-service/entities"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/i18n"
+)
+
+type ChartsSuite struct {
+	suite.Suite
+	report    dto.ReportResponse
+	localizer i18n.Localizer
+}
+
+func (s *ChartsSuite) SetupTest() {
+	localizer, err := i18n.NewLocalizer(i18n.LocaleEnglish, "UTC")
+	s.Require().NoError(err)
+	s.localizer = localizer
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.report = dto.ReportResponse{
+		ContainerCount:    3,
+		ContainerOnCount:  2,
+		ContainerOffCount: 1,
+		TotalUptime:       60,
+		Availability:      83.33,
+		SLATarget:         99.5,
+		StartTime:         startTime,
+		EndTime:           startTime.Add(24 * time.Hour),
+		Containers: []dto.ContainerReport{
+			{ContainerId: "api", Availability: 100, DowntimeHours: 0, Timeline: []dto.StatusSegment{
+				{Status: entities.ContainerOn, Start: startTime, End: startTime.Add(24 * time.Hour)},
+			}},
+			{ContainerId: "db<primary>", Availability: 99.7, DowntimeHours: 0.07, Timeline: []dto.StatusSegment{
+				{Status: entities.ContainerOn, Start: startTime, End: startTime.Add(12 * time.Hour)},
+				{Status: entities.ContainerOff, Start: startTime.Add(12 * time.Hour), End: startTime.Add(18 * time.Hour)},
+			}},
+			{ContainerId: "worker", Availability: 40, DowntimeHours: 12},
+		},
+	}
+}
+
+func TestChartsSuite(t *testing.T) {
+	suite.Run(t, new(ChartsSuite))
+}
+
+func (s *ChartsSuite) TestFleetBar() {
+	chart := string(FleetBar(s.report, s.localizer))
+
+	s.True(strings.HasPrefix(chart, "<svg "))
+	s.True(strings.HasSuffix(chart, "</svg>"))
+	s.Contains(chart, `<rect x="0.00" y="0.00" width="590.00" height="28.00" fill="#ff416c"/>`)
+	s.Contains(chart, `<rect x="0.00" y="0.00" width="491.65" height="28.00" fill="#11998e"/>`)
+	s.Contains(chart, "On 83.33% (60.00h)")
+	s.Contains(chart, "Off 16.67% (12.07h)")
+}
+
+func (s *ChartsSuite) TestFleetBarEmptyReport() {
+	s.Empty(FleetBar(dto.ReportResponse{}, s.localizer))
+}
+
+func (s *ChartsSuite) TestAvailabilityHistogram() {
+	chart := string(AvailabilityHistogram(s.report, s.localizer))
+
+	s.Contains(chart, `>&lt; 50%</text>`)
+	s.Contains(chart, `>&gt;= 99.9%</text>`)
+	// worker lands in the first bucket, db in the partially compliant one and
+	// api in the last, each bar reaching the top of the plot.
+	s.Contains(chart, `<rect x="19.17" y="20.00" width="60.00" height="140.00" fill="#ff416c"/>`)
+	s.Contains(chart, `<rect x="412.50" y="20.00" width="60.00" height="140.00" fill="#f39c12"/>`)
+	s.Contains(chart, `<rect x="510.83" y="20.00" width="60.00" height="140.00" fill="#11998e"/>`)
+	s.Equal(3, strings.Count(chart, "<rect "))
+}
+
+func (s *ChartsSuite) TestAvailabilityHistogramEmptyReport() {
+	s.Empty(AvailabilityHistogram(dto.ReportResponse{}, s.localizer))
+}
+
+func (s *ChartsSuite) TestTimelines() {
+	chart := string(Timelines(s.report, s.localizer))
+
+	s.Contains(chart, `viewBox="0 0 590 56"`)
+	s.Contains(chart, ">api</text>")
+	s.Contains(chart, ">db&lt;primary&gt;</text>")
+	s.NotContains(chart, ">worker</text>")
+	s.Contains(chart, `<rect x="160.00" y="18.00" width="215.00" height="12.00" fill="#11998e"/>`)
+	s.Contains(chart, `<rect x="375.00" y="18.00" width="107.50" height="12.00" fill="#ff416c"/>`)
+	s.Contains(chart, ">2024-01-01 00:00 UTC</text>")
+	s.Contains(chart, ">2024-01-02 00:00 UTC</text>")
+}
+
+func (s *ChartsSuite) TestTimelinesWithoutSeries() {
+	for i := range s.report.Containers {
+		s.report.Containers[i].Timeline = nil
+	}
+	s.Empty(Timelines(s.report, s.localizer))
+}
+
+func (s *ChartsSuite) TestLocalized() {
+	localizer, err := i18n.NewLocalizer(i18n.LocaleVietnamese, "Asia/Ho_Chi_Minh")
+	s.Require().NoError(err)
+
+	fleet := string(FleetBar(s.report, localizer))
+	s.Contains(fleet, `aria-label="Thời gian Hoạt động Toàn hệ thống"`)
+	s.Contains(fleet, "Hoạt động 83,33% (60,00 giờ)")
+	s.Contains(fleet, "Ngừng 16,67% (12,07 giờ)")
+
+	timeline := string(Timelines(s.report, localizer))
+	s.Contains(timeline, ">01/01/2024 07:00 +07</text>")
+	s.Contains(timeline, ">02/01/2024 07:00 +07</text>")
+}
@@ -0,0 +1,56 @@
+package exporters
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+
+	"github.com/vnFuhung2903/vcs-report-service/dto"
+)
+
+const MIMECSV = "text/csv"
+
+var csvHeader = []string{"container_id", "status", "uptime_hours", "downtime_hours", "availability"}
+
+type csvExporter struct{}
+
+func NewCSVExporter() IReportExporter {
+	return &csvExporter{}
+}
+
+func (e *csvExporter) Format() string {
+	return FormatCSV
+}
+
+func (e *csvExporter) ContentType() string {
+	return MIMECSV + "; charset=utf-8"
+}
+
+// Export renders one row per container, with hours and availability
+// percentages rounded to two decimals like in the email.
+func (e *csvExporter) Export(report dto.ReportResponse) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	if err := writer.Write(csvHeader); err != nil {
+		return nil, err
+	}
+	for _, container := range report.Containers {
+		err := writer.Write([]string{
+			container.ContainerId,
+			string(container.Status),
+			strconv.FormatFloat(container.UptimeHours, 'f', 2, 64),
+			strconv.FormatFloat(container.DowntimeHours, 'f', 2, 64),
+			strconv.FormatFloat(container.Availability, 'f', 2, 64),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
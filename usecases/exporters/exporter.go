@@ -0,0 +1,42 @@
+package exporters
+
+import (
+	"fmt"
+
+	"github.com/vnFuhung2903/vcs-report-service/dto"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/i18n"
+)
+
+const (
+	FormatCSV = "csv"
+	FormatPDF = "pdf"
+)
+
+type IReportExporter interface {
+	Format() string
+	ContentType() string
+	Export(report dto.ReportResponse) ([]byte, error)
+}
+
+// NewExporter returns the exporter rendering reports in format. Formats meant
+// to be read by people are translated and formatted through localizer.
+func NewExporter(format string, localizer i18n.Localizer) (IReportExporter, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVExporter(), nil
+	case FormatPDF:
+		return NewPDFExporter(localizer), nil
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+// FileName names the file holding report once exported by exporter, for
+// instance report_2024-01-01_2024-01-31.csv. The name is translated through
+// localizer and the dates are those of the window in its timezone, kept in
+// ISO order so that the name stays free of path separators.
+func FileName(exporter IReportExporter, report dto.ReportResponse, localizer i18n.Localizer) string {
+	location := localizer.Location()
+	name := localizer.T("export.file_name", report.StartTime.In(location).Format("2006-01-02"), report.EndTime.In(location).Format("2006-01-02"))
+	return name + "." + exporter.Format()
+}
@@ -0,0 +1,177 @@
+package exporters
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/vnFuhung2903/vcs-report-service/dto"
+	"github.com/vnFuhung2903/vcs-report-service/entities"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/i18n"
+)
+
+type ExporterSuite struct {
+	suite.Suite
+	report    dto.ReportResponse
+	localizer i18n.Localizer
+}
+
+func (s *ExporterSuite) SetupTest() {
+	localizer, err := i18n.NewLocalizer(i18n.LocaleEnglish, "UTC")
+	s.Require().NoError(err)
+	s.localizer = localizer
+	s.report = dto.ReportResponse{
+		ContainerCount: 2,
+		StartTime:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndTime:        time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC),
+		Containers: []dto.ContainerReport{
+			{ContainerId: "container-1", Status: entities.ContainerOn, UptimeHours: 743.6, DowntimeHours: 0.4, Availability: 99.9462},
+			{ContainerId: "web,1", Status: entities.ContainerOff, UptimeHours: 12, DowntimeHours: 732, Availability: 1.6129},
+		},
+	}
+}
+
+func TestExporterSuite(t *testing.T) {
+	suite.Run(t, new(ExporterSuite))
+}
+
+func (s *ExporterSuite) TestNewExporter() {
+	exporter, err := NewExporter("csv", s.localizer)
+	s.NoError(err)
+	s.Equal(FormatCSV, exporter.Format())
+
+	exporter, err = NewExporter("pdf", s.localizer)
+	s.NoError(err)
+	s.Equal(FormatPDF, exporter.Format())
+
+	_, err = NewExporter("xlsx", s.localizer)
+	s.EqualError(err, "unsupported export format: xlsx")
+}
+
+func (s *ExporterSuite) TestFileName() {
+	s.Equal("report_2024-01-01_2024-01-31.csv", FileName(NewCSVExporter(), s.report, s.localizer))
+
+	localizer, err := i18n.NewLocalizer(i18n.LocaleVietnamese, "Asia/Ho_Chi_Minh")
+	s.Require().NoError(err)
+	s.Equal("bao-cao_2024-01-01_2024-02-01.pdf", FileName(NewPDFExporter(localizer), s.report, localizer))
+}
+
+func (s *ExporterSuite) TestCSVExport() {
+	exporter := NewCSVExporter()
+	s.Equal("text/csv; charset=utf-8", exporter.ContentType())
+
+	data, err := exporter.Export(s.report)
+	s.NoError(err)
+
+	records, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	s.NoError(err)
+	s.Equal([][]string{
+		{"container_id", "status", "uptime_hours", "downtime_hours", "availability"},
+		{"container-1", "ON", "743.60", "0.40", "99.95"},
+		{"web,1", "OFF", "12.00", "732.00", "1.61"},
+	}, records)
+}
+
+func (s *ExporterSuite) TestCSVExportEmptyReport() {
+	data, err := NewCSVExporter().Export(dto.ReportResponse{})
+	s.NoError(err)
+	s.Equal("container_id,status,uptime_hours,downtime_hours,availability\n", string(data))
+}
+
+func (s *ExporterSuite) TestPDFExport() {
+	exporter := NewPDFExporter(s.localizer)
+	s.Equal("application/pdf", exporter.ContentType())
+	s.report.SLATarget = 99.9
+	s.report.Containers[1].SLABreached = true
+
+	data, err := exporter.Export(s.report)
+	s.NoError(err)
+	s.True(bytes.HasPrefix(data, []byte("%PDF-1.4\n")))
+	s.True(bytes.HasSuffix(data, []byte("%%EOF\n")))
+	s.Contains(string(data), "/Count 1")
+	s.Contains(string(data), "(Container Management System Report) Tj")
+	s.Contains(string(data), "(Reporting Period: 2024-01-01 00:00 UTC - 2024-01-31 23:59 UTC) Tj")
+	s.Contains(string(data), "(Uptime by Container) Tj")
+	s.Contains(string(data), "(container-1) Tj")
+	s.Contains(string(data), "(99.95%) Tj")
+	s.Contains(string(data), "(Breached) Tj")
+	s.assertXref(data)
+}
+
+func (s *ExporterSuite) TestPDFExportPaginates() {
+	s.report.Containers = nil
+	for i := range 120 {
+		s.report.Containers = append(s.report.Containers, dto.ContainerReport{ContainerId: fmt.Sprintf("container-%d", i), Availability: 100})
+	}
+
+	data, err := NewPDFExporter(s.localizer).Export(s.report)
+	s.NoError(err)
+	s.Contains(string(data), "(container-119) Tj")
+	s.Equal(5, strings.Count(string(data), "/Type /Page "))
+	s.assertXref(data)
+}
+
+func (s *ExporterSuite) TestPDFExportEscapesText() {
+	s.report.Containers = []dto.ContainerReport{{ContainerId: `web(1)\✓-a-very-long-container-identifier`}, {ContainerId: "máy-chủ-đà-nẵng"}}
+
+	data, err := NewPDFExporter(s.localizer).Export(s.report)
+	s.NoError(err)
+	s.Contains(string(data), `(web\(1\)\\?-a-very-long-containe...) Tj`)
+	s.Contains(string(data), "(may-chu-da-nang) Tj")
+}
+
+func (s *ExporterSuite) TestPDFExportLocalized() {
+	localizer, err := i18n.NewLocalizer(i18n.LocaleVietnamese, "Asia/Ho_Chi_Minh")
+	s.Require().NoError(err)
+	s.report.SLATarget = 99.9
+
+	data, err := NewPDFExporter(localizer).Export(s.report)
+	s.NoError(err)
+	s.Contains(string(data), "(Bao cao He thong Quan ly Container) Tj")
+	s.Contains(string(data), "(Ky bao cao: 01/01/2024 07:00 +07 - 01/02/2024 06:59 +07) Tj")
+	s.Contains(string(data), `(0 \(muc tieu 99,90%\)) Tj`)
+	s.Contains(string(data), "(743,60) Tj")
+	s.Contains(string(data), "(99,95%) Tj")
+	s.Contains(string(data), "(Dat) Tj")
+}
+
+// The standard fonts cannot render Vietnamese, so the PDF spells translated
+// text without its diacritics rather than with unreadable glyphs.
+func (s *ExporterSuite) TestPDFExportFoldsVietnameseDiacritics() {
+	localizer, err := i18n.NewLocalizer(i18n.LocaleVietnamese, "Asia/Ho_Chi_Minh")
+	s.Require().NoError(err)
+
+	data, err := NewPDFExporter(localizer).Export(s.report)
+	s.NoError(err)
+	for _, b := range data {
+		s.Require().Less(b, byte(0x80), "the PDF holds text outside ASCII")
+	}
+	s.Contains(string(data), "(Do san sang) Tj")
+	s.NotContains(string(data), "?")
+}
+
+// assertXref checks that every cross-reference entry points at the object it
+// indexes, which is what readers rely on to open the document.
+func (s *ExporterSuite) assertXref(data []byte) {
+	match := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(data)
+	s.Require().NotNil(match)
+	offset, err := strconv.Atoi(string(match[1]))
+	s.Require().NoError(err)
+
+	lines := strings.Split(string(data[offset:]), "\n")
+	s.Equal("xref", lines[0])
+	count, err := strconv.Atoi(strings.Fields(lines[1])[1])
+	s.Require().NoError(err)
+	for i := 1; i < count; i++ {
+		objectOffset, err := strconv.Atoi(lines[2+i][:10])
+		s.Require().NoError(err)
+		s.True(bytes.HasPrefix(data[objectOffset:], []byte(fmt.Sprintf("%d 0 obj", i))))
+	}
+}
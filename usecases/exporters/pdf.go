@@ -0,0 +1,154 @@
+package exporters
+
+import (
+	"github.com/vnFuhung2903/vcs-report-service/dto"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/i18n"
+)
+
+const MIMEPDF = "application/pdf"
+
+// Layout of the uptime chart: one bar per container, green for the share of
+// the window it was up and red for the rest.
+const (
+	chartBarX      = 210.0
+	chartBarWidth  = 270.0
+	chartRowHeight = 14.0
+	chartBarHeight = 9.0
+)
+
+var pdfTableColumns = []struct {
+	title string
+	x     float64
+}{
+	{"column.container", pdfMargin},
+	{"column.status", 230},
+	{"column.uptime", 290},
+	{"column.downtime", 360},
+	{"column.availability", 430},
+	{"column.sla", 500},
+}
+
+type pdfExporter struct {
+	localizer i18n.Localizer
+}
+
+// NewPDFExporter returns the exporter rendering reports as PDF, translated
+// and with numbers and dates formatted through localizer. The PDF only uses
+// the standard Helvetica fonts, which have no glyphs for most Vietnamese
+// letters, so translated text is written without its diacritics; the HTML
+// email keeps the full spelling.
+func NewPDFExporter(localizer i18n.Localizer) IReportExporter {
+	return &pdfExporter{localizer: localizer}
+}
+
+func (e *pdfExporter) Format() string {
+	return FormatPDF
+}
+
+func (e *pdfExporter) ContentType() string {
+	return MIMEPDF
+}
+
+// Export renders an A4 document with the report summary, an uptime chart and
+// the per-container table, continuing on further pages as needed.
+func (e *pdfExporter) Export(report dto.ReportResponse) ([]byte, error) {
+	doc := newPDFDocument()
+
+	doc.text(pdfMargin, doc.cursor+16, 16, true, e.localizer.T("report.title"))
+	doc.text(pdfMargin, doc.cursor+34, 10, false, e.localizer.T("report.period", e.localizer.DateTime(report.StartTime), e.localizer.DateTime(report.EndTime)))
+	doc.cursor += 60
+
+	e.writeSummary(doc, report)
+	e.writeChart(doc, report)
+	e.writeTable(doc, report)
+	return doc.bytes(), nil
+}
+
+func (e *pdfExporter) writeSummary(doc *pdfDocument, report dto.ReportResponse) {
+	localizer := e.localizer
+	facts := [][2]string{
+		{"stat.total", localizer.Integer(report.ContainerCount)},
+		{"stat.active", localizer.Integer(report.ContainerOnCount)},
+		{"stat.inactive", localizer.Integer(report.ContainerOffCount)},
+		{"stat.uptime", localizer.Number(report.TotalUptime, 2)},
+		{"stat.availability", localizer.Percent(report.Availability)},
+		{"stat.breaches", localizer.T("stat.breaches_target", localizer.Integer(report.SLABreachedCount), localizer.Percent(report.SLATarget))},
+	}
+
+	e.writeHeading(doc, localizer.T("section.summary"))
+	for _, fact := range facts {
+		doc.text(pdfMargin, doc.cursor+10, 10, false, localizer.T(fact[0]))
+		doc.text(chartBarX, doc.cursor+10, 10, true, fact[1])
+		doc.cursor += 15
+	}
+	doc.cursor += 15
+}
+
+func (e *pdfExporter) writeChart(doc *pdfDocument, report dto.ReportResponse) {
+	if len(report.Containers) == 0 {
+		return
+	}
+
+	e.writeHeading(doc, e.localizer.T("chart.container_uptime"))
+	for _, container := range report.Containers {
+		doc.reserve(chartRowHeight)
+		top := doc.cursor + (chartRowHeight-chartBarHeight)/2
+		up := chartBarWidth * clampPercent(container.Availability) / 100
+
+		doc.text(pdfMargin, doc.cursor+10, 9, false, truncate(container.ContainerId, 28))
+		doc.rect(chartBarX, top, chartBarWidth, chartBarHeight, pdfRed)
+		doc.rect(chartBarX, top, up, chartBarHeight, pdfGreen)
+		if report.SLATarget > 0 {
+			target := chartBarX + chartBarWidth*clampPercent(report.SLATarget)/100
+			doc.line(target, doc.cursor, target, doc.cursor+chartRowHeight, 1, pdfBlack)
+		}
+		doc.text(chartBarX+chartBarWidth+8, doc.cursor+10, 9, false, e.localizer.Percent(container.Availability))
+		doc.cursor += chartRowHeight
+	}
+	doc.cursor += 15
+}
+
+func (e *pdfExporter) writeTable(doc *pdfDocument, report dto.ReportResponse) {
+	e.writeHeading(doc, e.localizer.T("section.containers"))
+	e.writeTableHeader(doc)
+	for _, container := range report.Containers {
+		if doc.reserve(chartRowHeight) {
+			e.writeTableHeader(doc)
+		}
+
+		sla := e.localizer.T("sla.met")
+		if container.SLABreached {
+			sla = e.localizer.T("sla.breached")
+		}
+		cells := []string{
+			truncate(container.ContainerId, 32),
+			string(container.Status),
+			e.localizer.Number(container.UptimeHours, 2),
+			e.localizer.Number(container.DowntimeHours, 2),
+			e.localizer.Percent(container.Availability),
+			sla,
+		}
+		for i, cell := range cells {
+			doc.text(pdfTableColumns[i].x, doc.cursor+10, 9, false, cell)
+		}
+		doc.cursor += chartRowHeight
+	}
+}
+
+func (e *pdfExporter) writeHeading(doc *pdfDocument, title string) {
+	doc.reserve(40)
+	doc.text(pdfMargin, doc.cursor+12, 12, true, title)
+	doc.cursor += 22
+}
+
+func (e *pdfExporter) writeTableHeader(doc *pdfDocument) {
+	for _, column := range pdfTableColumns {
+		doc.text(column.x, doc.cursor+10, 9, true, e.localizer.T(column.title))
+	}
+	doc.line(pdfMargin, doc.cursor+14, pdfPageWidth-pdfMargin, doc.cursor+14, 0.5, pdfGrey)
+	doc.cursor += 18
+}
+
+func clampPercent(value float64) float64 {
+	return max(0, min(100, value))
+}
@@ -0,0 +1,158 @@
+package exporters
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// A4 portrait in PDF points.
+const (
+	pdfPageWidth  = 595.0
+	pdfPageHeight = 842.0
+	pdfMargin     = 50.0
+)
+
+type pdfColor struct {
+	r, g, b float64
+}
+
+var (
+	pdfBlack = pdfColor{0, 0, 0}
+	pdfGrey  = pdfColor{0.85, 0.85, 0.85}
+	pdfGreen = pdfColor{0.18, 0.62, 0.31}
+	pdfRed   = pdfColor{0.82, 0.2, 0.2}
+)
+
+// pdfDocument is a minimal PDF 1.4 writer limited to what the report needs:
+// text in the standard Helvetica fonts, filled rectangles and lines. The
+// standard fonts need no embedding, which keeps the output small and the
+// writer free of external dependencies. Positions are measured from the top
+// left corner of the page and the cursor tracks where the next line goes.
+type pdfDocument struct {
+	pages  []*bytes.Buffer
+	page   *bytes.Buffer
+	cursor float64
+}
+
+func newPDFDocument() *pdfDocument {
+	d := &pdfDocument{}
+	d.addPage()
+	return d
+}
+
+func (d *pdfDocument) addPage() {
+	d.page = &bytes.Buffer{}
+	d.pages = append(d.pages, d.page)
+	d.cursor = pdfMargin
+}
+
+// reserve starts a new page unless height points still fit above the bottom
+// margin, and reports whether it did.
+func (d *pdfDocument) reserve(height float64) bool {
+	if d.cursor+height <= pdfPageHeight-pdfMargin {
+		return false
+	}
+	d.addPage()
+	return true
+}
+
+func (d *pdfDocument) text(x float64, y float64, size float64, bold bool, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(d.page, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, pdfPageHeight-y, pdfEscape(s))
+}
+
+func (d *pdfDocument) rect(x float64, y float64, width float64, height float64, color pdfColor) {
+	fmt.Fprintf(d.page, "%.3f %.3f %.3f rg %.2f %.2f %.2f %.2f re f\n", color.r, color.g, color.b, x, pdfPageHeight-y-height, width, height)
+}
+
+func (d *pdfDocument) line(x1 float64, y1 float64, x2 float64, y2 float64, width float64, color pdfColor) {
+	fmt.Fprintf(d.page, "%.3f %.3f %.3f RG %.2f w %.2f %.2f m %.2f %.2f l S\n", color.r, color.g, color.b, width, x1, pdfPageHeight-y1, x2, pdfPageHeight-y2)
+}
+
+// bytes serialises the document: catalog, page tree, the two fonts, then a
+// page object followed by its content stream for every page.
+func (d *pdfDocument) bytes() []byte {
+	var objects []string
+	pageIds := make([]string, len(d.pages))
+	for i := range d.pages {
+		pageIds[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+
+	objects = append(objects,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(pageIds, " "), len(d.pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+	)
+	for i, page := range d.pages {
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", pdfPageWidth, pdfPageHeight, 6+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()),
+		)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+// pdfLetterFolder maps the letters that carry no combining mark, and so are
+// not folded by decomposition, to their base letter.
+var pdfLetterFolder = strings.NewReplacer("đ", "d", "Đ", "D")
+
+// pdfEscape escapes the string delimiters and replaces characters outside
+// printable ASCII, which the standard fonts cannot be relied on to render.
+// Accented letters are folded to their base letter first so that translated
+// text stays readable: "Độ sẵn sàng" is written "Do san sang". Keeping the
+// diacritics would mean embedding a Unicode TrueType font.
+func pdfEscape(s string) string {
+	folded, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), pdfLetterFolder.Replace(s))
+	if err == nil {
+		s = folded
+	}
+
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20 || r > 0x7e:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// truncate shortens s to at most n characters, marking the cut with an
+// ellipsis, so that long identifiers stay inside their table column.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-3]) + "..."
+}
@@ -0,0 +1,163 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	LocaleEnglish    = "en"
+	LocaleVietnamese = "vi"
+	DefaultLocale    = LocaleEnglish
+)
+
+var ErrUnsupportedLocale = errors.New("unsupported locale")
+
+//go:embed catalogs/*.json
+var catalogFiles embed.FS
+
+// catalogs holds the translated strings of every supported locale, keyed by
+// locale then message key.
+var catalogs = loadCatalogs()
+
+type localeFormat struct {
+	decimal  string
+	group    string
+	date     string
+	dateTime string
+}
+
+var formats = map[string]localeFormat{
+	LocaleEnglish:    {decimal: ".", group: ",", date: "2006-01-02", dateTime: "2006-01-02 15:04 MST"},
+	LocaleVietnamese: {decimal: ",", group: ".", date: "02/01/2006", dateTime: "02/01/2006 15:04 MST"},
+}
+
+// Localizer translates messages and formats numbers and dates for one locale,
+// rendering times in one timezone.
+type Localizer struct {
+	locale   string
+	location *time.Location
+	messages map[string]string
+	format   localeFormat
+}
+
+// NewLocalizer returns the Localizer for locale and the IANA timezone name.
+// An empty locale selects DefaultLocale and an empty timezone selects UTC.
+func NewLocalizer(locale string, timezone string) (Localizer, error) {
+	if locale == "" {
+		locale = DefaultLocale
+	}
+	messages, ok := catalogs[locale]
+	if !ok {
+		return Localizer{}, fmt.Errorf("%w: %s", ErrUnsupportedLocale, locale)
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return Localizer{}, err
+	}
+
+	return Localizer{
+		locale:   locale,
+		location: location,
+		messages: messages,
+		format:   formats[locale],
+	}, nil
+}
+
+// Locales lists the supported locales.
+func Locales() []string {
+	return []string{LocaleEnglish, LocaleVietnamese}
+}
+
+func (l Localizer) Locale() string {
+	return l.locale
+}
+
+func (l Localizer) Location() *time.Location {
+	return l.location
+}
+
+// T returns the message under key formatted with args. Messages missing from
+// the locale's catalog fall back to English, then to the key itself.
+func (l Localizer) T(key string, args ...any) string {
+	message, ok := l.messages[key]
+	if !ok {
+		message, ok = catalogs[DefaultLocale][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// Number formats value with the given number of decimals and the locale's
+// decimal and thousands separators.
+func (l Localizer) Number(value float64, decimals int) string {
+	formatted := strconv.FormatFloat(value, 'f', decimals, 64)
+	sign := ""
+	if strings.HasPrefix(formatted, "-") {
+		sign, formatted = "-", formatted[1:]
+	}
+
+	integer, fraction, _ := strings.Cut(formatted, ".")
+	var grouped strings.Builder
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			grouped.WriteString(l.format.group)
+		}
+		grouped.WriteRune(digit)
+	}
+
+	if fraction == "" {
+		return sign + grouped.String()
+	}
+	return sign + grouped.String() + l.format.decimal + fraction
+}
+
+func (l Localizer) Integer(value int) string {
+	return l.Number(float64(value), 0)
+}
+
+func (l Localizer) Percent(value float64) string {
+	return l.Number(value, 2) + "%"
+}
+
+// Date formats t as a calendar date in the localizer's timezone.
+func (l Localizer) Date(t time.Time) string {
+	return t.In(l.location).Format(l.format.date)
+}
+
+// DateTime formats t with its time of day and zone in the localizer's
+// timezone, or as "-" when t is unset.
+func (l Localizer) DateTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.In(l.location).Format(l.format.dateTime)
+}
+
+func loadCatalogs() map[string]map[string]string {
+	catalogs := make(map[string]map[string]string)
+	for _, locale := range Locales() {
+		content, err := catalogFiles.ReadFile(path.Join("catalogs", locale+".json"))
+		if err != nil {
+			panic(err)
+		}
+		messages := make(map[string]string)
+		if err := json.Unmarshal(content, &messages); err != nil {
+			panic(fmt.Errorf("invalid %s message catalog: %w", locale, err))
+		}
+		catalogs[locale] = messages
+	}
+	return catalogs
+}
@@ -0,0 +1,78 @@
+package i18n
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type LocalizerSuite struct {
+	suite.Suite
+	english    Localizer
+	vietnamese Localizer
+}
+
+func (s *LocalizerSuite) SetupTest() {
+	var err error
+	s.english, err = NewLocalizer("", "")
+	s.Require().NoError(err)
+	s.vietnamese, err = NewLocalizer(LocaleVietnamese, "Asia/Ho_Chi_Minh")
+	s.Require().NoError(err)
+}
+
+func TestLocalizerSuite(t *testing.T) {
+	suite.Run(t, new(LocalizerSuite))
+}
+
+func (s *LocalizerSuite) TestNewLocalizer() {
+	s.Equal(LocaleEnglish, s.english.Locale())
+	s.Equal(time.UTC, s.english.Location())
+	s.Equal(LocaleVietnamese, s.vietnamese.Locale())
+	s.Equal("Asia/Ho_Chi_Minh", s.vietnamese.Location().String())
+
+	_, err := NewLocalizer("fr", "UTC")
+	s.ErrorIs(err, ErrUnsupportedLocale)
+
+	_, err = NewLocalizer(LocaleEnglish, "Mars/Olympus_Mons")
+	s.Error(err)
+}
+
+func (s *LocalizerSuite) TestCatalogsAreComplete() {
+	for _, locale := range Locales() {
+		for key := range catalogs[DefaultLocale] {
+			s.Contains(catalogs[locale], key, locale)
+		}
+	}
+}
+
+func (s *LocalizerSuite) TestT() {
+	s.Equal("All Clear", s.english.T("allclear.title"))
+	s.Equal("Mọi thứ Ổn định", s.vietnamese.T("allclear.title"))
+	s.Equal("Kỳ báo cáo: a - b", s.vietnamese.T("report.period", "a", "b"))
+	s.Equal("missing.key", s.vietnamese.T("missing.key"))
+
+	s.vietnamese.messages = map[string]string{}
+	s.Equal("All Clear", s.vietnamese.T("allclear.title"))
+}
+
+func (s *LocalizerSuite) TestNumber() {
+	s.Equal("0.00", s.english.Number(0, 2))
+	s.Equal("999.50", s.english.Number(999.5, 2))
+	s.Equal("1,234,567.89", s.english.Number(1234567.891, 2))
+	s.Equal("-1,234", s.english.Number(-1234, 0))
+	s.Equal("1.234.567,89", s.vietnamese.Number(1234567.891, 2))
+	s.Equal("12.345", s.vietnamese.Integer(12345))
+	s.Equal("99.90%", s.english.Percent(99.9))
+	s.Equal("99,90%", s.vietnamese.Percent(99.9))
+}
+
+func (s *LocalizerSuite) TestDates() {
+	t := time.Date(2024, 1, 31, 20, 30, 0, 0, time.UTC)
+
+	s.Equal("2024-01-31", s.english.Date(t))
+	s.Equal("2024-01-31 20:30 UTC", s.english.DateTime(t))
+	s.Equal("01/02/2024", s.vietnamese.Date(t))
+	s.Equal("01/02/2024 03:30 +07", s.vietnamese.DateTime(t))
+	s.Equal("-", s.vietnamese.DateTime(time.Time{}))
+}
@@ -0,0 +1,20 @@
+package notifiers
+
+import (
+	"context"
+
+	"github.com/vnFuhung2903/vcs-report-service/dto"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/services"
+)
+
+type emailNotifier struct {
+	reportService services.IReportService
+}
+
+func NewEmailNotifier(reportService services.IReportService) INotifier {
+	return &emailNotifier{reportService: reportService}
+}
+
+func (n *emailNotifier) Notify(ctx context.Context, target string, report dto.ReportResponse) error {
+	return n.reportService.SendEmail(ctx, target, report)
+}
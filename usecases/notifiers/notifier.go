@@ -0,0 +1,120 @@
+package notifiers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/vnFuhung2903/vcs-report-service/dto"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/logger"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/retry"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/i18n"
+	"go.uber.org/zap"
+)
+
+const (
+	ChannelEmail   = "email"
+	ChannelSlack   = "slack"
+	ChannelTeams   = "teams"
+	ChannelWebhook = "webhook"
+)
+
+type INotifier interface {
+	Notify(ctx context.Context, target string, report dto.ReportResponse) error
+}
+
+type INotificationService interface {
+	Notify(ctx context.Context, channel string, target string, report dto.ReportResponse) error
+}
+
+type notificationService struct {
+	notifiers map[string]INotifier
+	logger    logger.ILogger
+}
+
+func NewNotificationService(notifiers map[string]INotifier, logger logger.ILogger) INotificationService {
+	return &notificationService{
+		notifiers: notifiers,
+		logger:    logger,
+	}
+}
+
+func (s *notificationService) Notify(ctx context.Context, channel string, target string, report dto.ReportResponse) error {
+	notifier, ok := s.notifiers[channel]
+	if !ok {
+		err := fmt.Errorf("unsupported notification channel: %s", channel)
+		s.logger.Error("failed to resolve notifier", zap.Error(err))
+		return err
+	}
+
+	if err := notifier.Notify(ctx, target, report); err != nil {
+		s.logger.Error("failed to send notification", zap.String("channel", channel), zap.Error(err))
+		return err
+	}
+
+	s.logger.Info("notification sent successfully", zap.String("channel", channel))
+	return nil
+}
+
+// postJSON posts payload to url, retrying network errors, 5xx and 429
+// responses according to retryPolicy. Any other non-2xx status fails at once.
+func postJSON(ctx context.Context, httpClient *http.Client, retryPolicy retry.Policy, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return retryPolicy.Do(ctx, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return retry.Permanent(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		res, err := httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+
+		if res.StatusCode < 200 || res.StatusCode >= 300 {
+			err := fmt.Errorf("webhook request failed: %s", res.Status)
+			if res.StatusCode >= 500 || res.StatusCode == http.StatusTooManyRequests {
+				return err
+			}
+			return retry.Permanent(err)
+		}
+		return nil
+	})
+}
+
+// reportTitle matches the subject of the report email, translated and with
+// the window in the timezone of localizer.
+func reportTitle(report dto.ReportResponse, localizer i18n.Localizer) string {
+	return localizer.T("email.subject", localizer.DateTime(report.StartTime), localizer.DateTime(report.EndTime))
+}
+
+// reportFacts lists the headline figures of report, labelled and formatted
+// through localizer.
+func reportFacts(report dto.ReportResponse, localizer i18n.Localizer) [][2]string {
+	return [][2]string{
+		{localizer.T("stat.total"), localizer.Integer(report.ContainerCount)},
+		{localizer.T("stat.active"), localizer.Integer(report.ContainerOnCount)},
+		{localizer.T("stat.inactive"), localizer.Integer(report.ContainerOffCount)},
+		{localizer.T("stat.uptime"), localizer.Number(report.TotalUptime, 2)},
+		{localizer.T("stat.availability"), localizer.Percent(report.Availability)},
+		{localizer.T("stat.breaches"), localizer.T("stat.breaches_target", localizer.Integer(report.SLABreachedCount), localizer.Percent(report.SLATarget))},
+	}
+}
+
+func breachedContainers(report dto.ReportResponse, localizer i18n.Localizer) []string {
+	var lines []string
+	for _, container := range report.Containers {
+		if container.SLABreached {
+			lines = append(lines, fmt.Sprintf("%s: %s (%s)", container.ContainerId, localizer.Percent(container.Availability), container.Status))
+		}
+	}
+	return lines
+}
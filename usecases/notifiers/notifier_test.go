@@ -0,0 +1,243 @@
+package notifiers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/vnFuhung2903/vcs-report-service/dto"
+	"github.com/vnFuhung2903/vcs-report-service/entities"
+	"github.com/vnFuhung2903/vcs-report-service/mocks/logger"
+	"github.com/vnFuhung2903/vcs-report-service/mocks/notifiers"
+	"github.com/vnFuhung2903/vcs-report-service/mocks/services"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/env"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/i18n"
+)
+
+type NotifierSuite struct {
+	suite.Suite
+	ctrl              *gomock.Controller
+	mockLogger        *logger.MockILogger
+	mockReportService *services.MockIReportService
+	server            *httptest.Server
+	statusCode        int
+	statusCodes       []int
+	requests          int
+	retryEnv          env.RetryEnv
+	localizer         i18n.Localizer
+	payload           map[string]interface{}
+	ctx               context.Context
+	report            dto.ReportResponse
+}
+
+func (s *NotifierSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.mockLogger = logger.NewMockILogger(s.ctrl)
+	s.mockReportService = services.NewMockIReportService(s.ctrl)
+	s.ctx = context.Background()
+
+	s.statusCode = http.StatusOK
+	s.statusCodes = nil
+	s.requests = 0
+	s.retryEnv = env.RetryEnv{Attempts: 3, Backoff: time.Millisecond}
+	localizer, err := i18n.NewLocalizer(i18n.LocaleEnglish, "UTC")
+	s.Require().NoError(err)
+	s.localizer = localizer
+	s.payload = nil
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &s.payload)
+		statusCode := s.statusCode
+		if s.requests < len(s.statusCodes) {
+			statusCode = s.statusCodes[s.requests]
+		}
+		s.requests++
+		w.WriteHeader(statusCode)
+	}))
+
+	s.report = dto.ReportResponse{
+		ContainerCount:    2,
+		ContainerOnCount:  1,
+		ContainerOffCount: 1,
+		TotalUptime:       5,
+		Availability:      62.5,
+		SLATarget:         99.9,
+		SLABreachedCount:  1,
+		StartTime:         time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndTime:           time.Date(2024, 1, 1, 4, 0, 0, 0, time.UTC),
+		Containers: []dto.ContainerReport{
+			{ContainerId: "flaky", Status: entities.ContainerOff, UptimeHours: 1, Availability: 25, SLABreached: true},
+			{ContainerId: "healthy", Status: entities.ContainerOn, UptimeHours: 4, Availability: 100},
+		},
+	}
+}
+
+func (s *NotifierSuite) TearDownTest() {
+	s.server.Close()
+	s.ctrl.Finish()
+}
+
+func TestNotifierSuite(t *testing.T) {
+	suite.Run(t, new(NotifierSuite))
+}
+
+func (s *NotifierSuite) TestNotificationServiceDispatch() {
+	slack := notifiers.NewMockINotifier(s.ctrl)
+	service := NewNotificationService(map[string]INotifier{ChannelSlack: slack}, s.mockLogger)
+
+	slack.EXPECT().Notify(s.ctx, "https://hooks.example.com", s.report).Return(nil)
+	s.mockLogger.EXPECT().Info("notification sent successfully", gomock.Any()).Times(1)
+
+	err := service.Notify(s.ctx, ChannelSlack, "https://hooks.example.com", s.report)
+	s.NoError(err)
+}
+
+func (s *NotifierSuite) TestNotificationServiceUnknownChannel() {
+	service := NewNotificationService(map[string]INotifier{}, s.mockLogger)
+
+	s.mockLogger.EXPECT().Error("failed to resolve notifier", gomock.Any()).Times(1)
+
+	err := service.Notify(s.ctx, "sms", "+84000000000", s.report)
+	s.Error(err)
+	s.Contains(err.Error(), "sms")
+}
+
+func (s *NotifierSuite) TestNotificationServiceNotifierError() {
+	teams := notifiers.NewMockINotifier(s.ctrl)
+	service := NewNotificationService(map[string]INotifier{ChannelTeams: teams}, s.mockLogger)
+
+	teams.EXPECT().Notify(s.ctx, "https://hooks.example.com", s.report).Return(errors.New("timeout"))
+	s.mockLogger.EXPECT().Error("failed to send notification", gomock.Any(), gomock.Any()).Times(1)
+
+	err := service.Notify(s.ctx, ChannelTeams, "https://hooks.example.com", s.report)
+	s.EqualError(err, "timeout")
+}
+
+func (s *NotifierSuite) TestEmailNotifier() {
+	s.mockReportService.EXPECT().SendEmail(s.ctx, "test@example.com", s.report).Return(nil)
+
+	err := NewEmailNotifier(s.mockReportService).Notify(s.ctx, "test@example.com", s.report)
+	s.NoError(err)
+}
+
+func (s *NotifierSuite) TestSlackNotifier() {
+	err := NewSlackNotifier(s.server.Client(), s.retryEnv, s.localizer).Notify(s.ctx, s.server.URL, s.report)
+	s.NoError(err)
+
+	s.Equal("Container Management System Report from 2024-01-01 00:00 UTC to 2024-01-01 04:00 UTC", s.payload["text"])
+	blocks := s.payload["blocks"].([]interface{})
+	s.Len(blocks, 3)
+	s.Equal("header", blocks[0].(map[string]interface{})["type"])
+	s.Contains(blocks[1].(map[string]interface{})["text"].(map[string]interface{})["text"], "*Availability:* 62.50%")
+	s.Contains(blocks[2].(map[string]interface{})["text"].(map[string]interface{})["text"], "flaky: 25.00% (OFF)")
+}
+
+func (s *NotifierSuite) TestSlackNotifierLocalized() {
+	localizer, err := i18n.NewLocalizer(i18n.LocaleVietnamese, "Asia/Ho_Chi_Minh")
+	s.Require().NoError(err)
+
+	err = NewSlackNotifier(s.server.Client(), s.retryEnv, localizer).Notify(s.ctx, s.server.URL, s.report)
+	s.NoError(err)
+	s.Equal("Báo cáo Hệ thống Quản lý Container từ 01/01/2024 07:00 +07 đến 01/01/2024 11:00 +07", s.payload["text"])
+	blocks := s.payload["blocks"].([]interface{})
+	s.Contains(blocks[1].(map[string]interface{})["text"].(map[string]interface{})["text"], "*Độ sẵn sàng:* 62,50%")
+	s.Contains(blocks[2].(map[string]interface{})["text"].(map[string]interface{})["text"], "*Vi phạm SLA:*\n• flaky: 25,00% (OFF)")
+}
+
+func (s *NotifierSuite) TestTeamsNotifierLocalized() {
+	localizer, err := i18n.NewLocalizer(i18n.LocaleVietnamese, "Asia/Ho_Chi_Minh")
+	s.Require().NoError(err)
+
+	err = NewTeamsNotifier(s.server.Client(), s.retryEnv, localizer).Notify(s.ctx, s.server.URL, s.report)
+	s.NoError(err)
+
+	attachment := s.payload["attachments"].([]interface{})[0].(map[string]interface{})
+	body := attachment["content"].(map[string]interface{})["body"].([]interface{})
+	facts := body[1].(map[string]interface{})["facts"].([]interface{})
+	s.Equal(map[string]interface{}{"title": "Tổng số Container", "value": "2"}, facts[0])
+	s.Equal(map[string]interface{}{"title": "Vi phạm SLA", "value": "1 (mục tiêu 99,90%)"}, facts[5])
+}
+
+func (s *NotifierSuite) TestTeamsNotifier() {
+	err := NewTeamsNotifier(s.server.Client(), s.retryEnv, s.localizer).Notify(s.ctx, s.server.URL, s.report)
+	s.NoError(err)
+
+	s.Equal("message", s.payload["type"])
+	attachment := s.payload["attachments"].([]interface{})[0].(map[string]interface{})
+	s.Equal("application/vnd.microsoft.card.adaptive", attachment["contentType"])
+
+	body := attachment["content"].(map[string]interface{})["body"].([]interface{})
+	s.Len(body, 3)
+	s.Equal("Container Management System Report from 2024-01-01 00:00 UTC to 2024-01-01 04:00 UTC", body[0].(map[string]interface{})["text"])
+	facts := body[1].(map[string]interface{})["facts"].([]interface{})
+	s.Equal(map[string]interface{}{"title": "Total Containers", "value": "2"}, facts[0])
+	s.Contains(body[2].(map[string]interface{})["text"], "SLA breached:\n\n- flaky: 25.00% (OFF)")
+}
+
+func (s *NotifierSuite) TestWebhookNotifier() {
+	err := NewWebhookNotifier(s.server.Client(), s.retryEnv, s.localizer).Notify(s.ctx, s.server.URL, s.report)
+	s.NoError(err)
+
+	s.Equal("container_report", s.payload["event"])
+	report := s.payload["report"].(map[string]interface{})
+	s.Equal(float64(2), report["container_count"])
+	s.Len(report["containers"], 2)
+}
+
+func (s *NotifierSuite) TestWebhookNotifierErrorStatus() {
+	s.statusCode = http.StatusBadRequest
+
+	err := NewWebhookNotifier(s.server.Client(), s.retryEnv, s.localizer).Notify(s.ctx, s.server.URL, s.report)
+	s.Error(err)
+	s.Contains(err.Error(), "400")
+	s.Equal(1, s.requests)
+}
+
+func (s *NotifierSuite) TestWebhookNotifierRetriesTransientStatus() {
+	s.statusCodes = []int{http.StatusServiceUnavailable, http.StatusTooManyRequests}
+
+	err := NewWebhookNotifier(s.server.Client(), s.retryEnv, s.localizer).Notify(s.ctx, s.server.URL, s.report)
+	s.NoError(err)
+	s.Equal(3, s.requests)
+	s.Equal("container_report", s.payload["event"])
+}
+
+func (s *NotifierSuite) TestWebhookNotifierRetriesExhausted() {
+	s.statusCode = http.StatusBadGateway
+
+	err := NewSlackNotifier(s.server.Client(), s.retryEnv, s.localizer).Notify(s.ctx, s.server.URL, s.report)
+	s.Error(err)
+	s.Contains(err.Error(), "502")
+	s.Equal(3, s.requests)
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func (s *NotifierSuite) TestWebhookNotifierRetriesNetworkError() {
+	attempts := 0
+	httpClient := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		if attempts == 1 {
+			return nil, errors.New("connection reset by peer")
+		}
+		return s.server.Client().Transport.RoundTrip(req)
+	})}
+
+	err := NewTeamsNotifier(httpClient, s.retryEnv, s.localizer).Notify(s.ctx, s.server.URL, s.report)
+	s.NoError(err)
+	s.Equal(2, attempts)
+	s.Equal(1, s.requests)
+	s.Equal("message", s.payload["type"])
+}
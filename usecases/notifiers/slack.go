@@ -0,0 +1,58 @@
+package notifiers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/vnFuhung2903/vcs-report-service/dto"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/env"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/retry"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/i18n"
+)
+
+type slackNotifier struct {
+	httpClient *http.Client
+	retry      retry.Policy
+	localizer  i18n.Localizer
+}
+
+func NewSlackNotifier(httpClient *http.Client, retryEnv env.RetryEnv, localizer i18n.Localizer) INotifier {
+	return &slackNotifier{
+		httpClient: httpClient,
+		retry:      retry.NewPolicy(retryEnv),
+		localizer:  localizer,
+	}
+}
+
+func (n *slackNotifier) Notify(ctx context.Context, target string, report dto.ReportResponse) error {
+	title := reportTitle(report, n.localizer)
+
+	var facts strings.Builder
+	for _, fact := range reportFacts(report, n.localizer) {
+		fmt.Fprintf(&facts, "*%s:* %s\n", fact[0], fact[1])
+	}
+
+	blocks := []interface{}{
+		map[string]interface{}{
+			"type": "header",
+			"text": map[string]string{"type": "plain_text", "text": title},
+		},
+		map[string]interface{}{
+			"type": "section",
+			"text": map[string]string{"type": "mrkdwn", "text": facts.String()},
+		},
+	}
+	if breached := breachedContainers(report, n.localizer); len(breached) > 0 {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "section",
+			"text": map[string]string{"type": "mrkdwn", "text": "*" + n.localizer.T("sla.breached_notice") + ":*\n• " + strings.Join(breached, "\n• ")},
+		})
+	}
+
+	return postJSON(ctx, n.httpClient, n.retry, target, map[string]interface{}{
+		"text":   title,
+		"blocks": blocks,
+	})
+}
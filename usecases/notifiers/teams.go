@@ -0,0 +1,69 @@
+package notifiers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/vnFuhung2903/vcs-report-service/dto"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/env"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/retry"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/i18n"
+)
+
+type teamsNotifier struct {
+	httpClient *http.Client
+	retry      retry.Policy
+	localizer  i18n.Localizer
+}
+
+func NewTeamsNotifier(httpClient *http.Client, retryEnv env.RetryEnv, localizer i18n.Localizer) INotifier {
+	return &teamsNotifier{
+		httpClient: httpClient,
+		retry:      retry.NewPolicy(retryEnv),
+		localizer:  localizer,
+	}
+}
+
+func (n *teamsNotifier) Notify(ctx context.Context, target string, report dto.ReportResponse) error {
+	facts := make([]map[string]string, 0)
+	for _, fact := range reportFacts(report, n.localizer) {
+		facts = append(facts, map[string]string{"title": fact[0], "value": fact[1]})
+	}
+
+	body := []interface{}{
+		map[string]interface{}{
+			"type":   "TextBlock",
+			"text":   reportTitle(report, n.localizer),
+			"weight": "Bolder",
+			"size":   "Medium",
+			"wrap":   true,
+		},
+		map[string]interface{}{
+			"type":  "FactSet",
+			"facts": facts,
+		},
+	}
+	if breached := breachedContainers(report, n.localizer); len(breached) > 0 {
+		body = append(body, map[string]interface{}{
+			"type": "TextBlock",
+			"text": n.localizer.T("sla.breached_notice") + ":\n\n- " + strings.Join(breached, "\n- "),
+			"wrap": true,
+		})
+	}
+
+	return postJSON(ctx, n.httpClient, n.retry, target, map[string]interface{}{
+		"type": "message",
+		"attachments": []interface{}{
+			map[string]interface{}{
+				"contentType": "application/vnd.microsoft.card.adaptive",
+				"content": map[string]interface{}{
+					"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+					"type":    "AdaptiveCard",
+					"version": "1.4",
+					"body":    body,
+				},
+			},
+		},
+	})
+}
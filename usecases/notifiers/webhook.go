@@ -0,0 +1,33 @@
+package notifiers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/vnFuhung2903/vcs-report-service/dto"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/env"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/retry"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/i18n"
+)
+
+type webhookNotifier struct {
+	httpClient *http.Client
+	retry      retry.Policy
+	localizer  i18n.Localizer
+}
+
+func NewWebhookNotifier(httpClient *http.Client, retryEnv env.RetryEnv, localizer i18n.Localizer) INotifier {
+	return &webhookNotifier{
+		httpClient: httpClient,
+		retry:      retry.NewPolicy(retryEnv),
+		localizer:  localizer,
+	}
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, target string, report dto.ReportResponse) error {
+	return postJSON(ctx, n.httpClient, n.retry, target, map[string]interface{}{
+		"event":  "container_report",
+		"title":  reportTitle(report, n.localizer),
+		"report": report,
+	})
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vnFuhung2903/vcs-report-service/entities"
+	"github.com/vnFuhung2903/vcs-report-service/interfaces"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/env"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	deadLetterKey      = "report_dead_letters"
+	deadLetterIndexKey = "report_dead_letters_by_creation"
+	deadLetterPageSize = 100
+)
+
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+type IDeadLetterService interface {
+	Park(ctx context.Context, letter entities.DeadLetter, attempts int) (entities.DeadLetter, error)
+	Get(ctx context.Context, id string) (entities.DeadLetter, error)
+	List(ctx context.Context, cursor uint64, limit int) ([]entities.DeadLetter, uint64, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type deadLetterService struct {
+	redisClient interfaces.IRedisClient
+	logger      logger.ILogger
+	limit       int
+}
+
+func NewDeadLetterService(redisClient interfaces.IRedisClient, logger logger.ILogger, reportEnv env.ReportEnv) IDeadLetterService {
+	return &deadLetterService{
+		redisClient: redisClient,
+		logger:      logger,
+		limit:       reportEnv.DeadLetterLimit,
+	}
+}
+
+// Park stores a delivery whose retries were exhausted after attempts tries.
+// Parking a letter that already has an id, for instance after a failed
+// replay, adds them to the attempts of the existing entry. Past the
+// configured limit, the oldest letters are dropped.
+func (s *deadLetterService) Park(ctx context.Context, letter entities.DeadLetter, attempts int) (entities.DeadLetter, error) {
+	now := time.Now()
+	if letter.Id == "" {
+		letter.Id = uuid.NewString()
+		letter.CreatedAt = now
+	}
+	letter.Attempts += attempts
+	letter.UpdatedAt = now
+
+	val, err := json.Marshal(letter)
+	if err != nil {
+		s.logger.Error("failed to encode dead letter", zap.Error(err))
+		return entities.DeadLetter{}, err
+	}
+
+	if err := s.redisClient.HSet(ctx, deadLetterKey, letter.Id, string(val)); err != nil {
+		s.logger.Error("failed to save dead letter to redis", zap.Error(err))
+		return entities.DeadLetter{}, err
+	}
+	if err := s.redisClient.ZAdd(ctx, deadLetterIndexKey, float64(letter.CreatedAt.UnixMilli()), letter.Id); err != nil {
+		s.logger.Error("failed to index dead letter in redis", zap.Error(err))
+		return entities.DeadLetter{}, err
+	}
+
+	s.logger.Warn("report delivery parked in dead letter queue",
+		zap.String("id", letter.Id),
+		zap.String("channel", letter.Channel),
+		zap.String("target", letter.Target),
+		zap.Int("attempts", letter.Attempts),
+	)
+	s.trim(ctx)
+	return letter, nil
+}
+
+func (s *deadLetterService) Get(ctx context.Context, id string) (entities.DeadLetter, error) {
+	val, err := s.redisClient.HGet(ctx, deadLetterKey, id)
+	if err != nil {
+		s.logger.Error("failed to get dead letter from redis", zap.Error(err))
+		return entities.DeadLetter{}, err
+	}
+	if val == "" {
+		return entities.DeadLetter{}, ErrDeadLetterNotFound
+	}
+
+	var letter entities.DeadLetter
+	if err := json.Unmarshal([]byte(val), &letter); err != nil {
+		s.logger.Error("failed to decode dead letter", zap.Error(err))
+		return entities.DeadLetter{}, err
+	}
+	return letter, nil
+}
+
+// List returns about limit dead letters from cursor on, oldest first within
+// the page, with the cursor of the next page, which is 0 after the last one.
+func (s *deadLetterService) List(ctx context.Context, cursor uint64, limit int) ([]entities.DeadLetter, uint64, error) {
+	if limit <= 0 {
+		limit = deadLetterPageSize
+	}
+
+	values, next, err := s.redisClient.HScan(ctx, deadLetterKey, cursor, int64(limit))
+	if err != nil {
+		s.logger.Error("failed to list dead letters from redis", zap.Error(err))
+		return nil, 0, err
+	}
+
+	letters := make([]entities.DeadLetter, 0, len(values))
+	for id, val := range values {
+		var letter entities.DeadLetter
+		if err := json.Unmarshal([]byte(val), &letter); err != nil {
+			s.logger.Warn("skipping undecodable dead letter", zap.String("id", id), zap.Error(err))
+			continue
+		}
+		letters = append(letters, letter)
+	}
+
+	sort.Slice(letters, func(i, j int) bool {
+		return letters[i].CreatedAt.Before(letters[j].CreatedAt)
+	})
+	return letters, next, nil
+}
+
+func (s *deadLetterService) Delete(ctx context.Context, id string) error {
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.redisClient.HDel(ctx, deadLetterKey, id); err != nil {
+		s.logger.Error("failed to delete dead letter from redis", zap.Error(err))
+		return err
+	}
+	if err := s.redisClient.ZRem(ctx, deadLetterIndexKey, id); err != nil {
+		s.logger.Warn("failed to unindex dead letter", zap.String("id", id), zap.Error(err))
+	}
+	return nil
+}
+
+// trim drops the oldest letters beyond the limit, reading their ids from the
+// creation time index. Failures are only logged since the letter itself was
+// stored.
+func (s *deadLetterService) trim(ctx context.Context) {
+	if s.limit <= 0 {
+		return
+	}
+
+	ids, err := s.redisClient.ZRevRange(ctx, deadLetterIndexKey, int64(s.limit), -1)
+	if err != nil {
+		s.logger.Warn("failed to list dead letters over the limit", zap.Error(err))
+		return
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	for _, id := range ids {
+		if err := s.redisClient.HDel(ctx, deadLetterKey, id); err != nil {
+			s.logger.Warn("failed to drop dead letter", zap.String("id", id), zap.Error(err))
+			return
+		}
+	}
+	if err := s.redisClient.ZRem(ctx, deadLetterIndexKey, ids...); err != nil {
+		s.logger.Warn("failed to unindex dropped dead letters", zap.Error(err))
+		return
+	}
+	s.logger.Warn("dropped dead letters over the limit", zap.Int("count", len(ids)), zap.Int("limit", s.limit))
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/vnFuhung2903/vcs-report-service/entities"
+	"github.com/vnFuhung2903/vcs-report-service/mocks/interfaces"
+	"github.com/vnFuhung2903/vcs-report-service/mocks/logger"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/env"
+)
+
+type DeadLetterServiceSuite struct {
+	suite.Suite
+	ctrl              *gomock.Controller
+	redisClient       *interfaces.MockIRedisClient
+	logger            *logger.MockILogger
+	deadLetterService IDeadLetterService
+	ctx               context.Context
+}
+
+func (s *DeadLetterServiceSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.redisClient = interfaces.NewMockIRedisClient(s.ctrl)
+	s.logger = logger.NewMockILogger(s.ctrl)
+	s.deadLetterService = NewDeadLetterService(s.redisClient, s.logger, env.ReportEnv{DeadLetterLimit: 100})
+	s.ctx = context.Background()
+}
+
+func (s *DeadLetterServiceSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func TestDeadLetterServiceSuite(t *testing.T) {
+	suite.Run(t, new(DeadLetterServiceSuite))
+}
+
+func (s *DeadLetterServiceSuite) storedLetter(letter entities.DeadLetter) string {
+	val, _ := json.Marshal(letter)
+	return string(val)
+}
+
+func (s *DeadLetterServiceSuite) TestPark() {
+	var stored string
+	s.redisClient.EXPECT().
+		HSet(s.ctx, "report_dead_letters", gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, key string, field string, value string) error {
+			stored = value
+			return nil
+		})
+	s.redisClient.EXPECT().ZAdd(s.ctx, "report_dead_letters_by_creation", gomock.Any(), gomock.Any()).Return(nil)
+	s.redisClient.EXPECT().ZRevRange(s.ctx, "report_dead_letters_by_creation", int64(100), int64(-1)).Return(nil, nil)
+	s.logger.EXPECT().Warn("report delivery parked in dead letter queue", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+
+	letter, err := s.deadLetterService.Park(s.ctx, entities.DeadLetter{
+		RunId:   "run-1",
+		Channel: "email",
+		Target:  "ops@example.com",
+		Error:   "connection refused",
+	}, 3)
+	s.NoError(err)
+	s.NotEmpty(letter.Id)
+	s.Equal(3, letter.Attempts)
+	s.False(letter.CreatedAt.IsZero())
+
+	var saved entities.DeadLetter
+	s.Require().NoError(json.Unmarshal([]byte(stored), &saved))
+	s.Equal(letter.Id, saved.Id)
+	s.Equal("ops@example.com", saved.Target)
+}
+
+func (s *DeadLetterServiceSuite) TestParkExisting() {
+	createdAt := time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
+	s.redisClient.EXPECT().HSet(s.ctx, "report_dead_letters", "letter-1", gomock.Any()).Return(nil)
+	s.redisClient.EXPECT().ZAdd(s.ctx, "report_dead_letters_by_creation", float64(createdAt.UnixMilli()), "letter-1").Return(nil)
+	s.redisClient.EXPECT().ZRevRange(s.ctx, "report_dead_letters_by_creation", int64(100), int64(-1)).Return(nil, nil)
+	s.logger.EXPECT().Warn("report delivery parked in dead letter queue", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+
+	letter, err := s.deadLetterService.Park(s.ctx, entities.DeadLetter{Id: "letter-1", Attempts: 3, CreatedAt: createdAt}, 3)
+	s.NoError(err)
+	s.Equal("letter-1", letter.Id)
+	s.Equal(6, letter.Attempts)
+	s.Equal(createdAt, letter.CreatedAt)
+	s.True(letter.UpdatedAt.After(createdAt))
+}
+
+func (s *DeadLetterServiceSuite) TestParkRedisError() {
+	s.redisClient.EXPECT().HSet(s.ctx, "report_dead_letters", gomock.Any(), gomock.Any()).Return(errors.New("redis connection failed"))
+	s.logger.EXPECT().Error("failed to save dead letter to redis", gomock.Any()).Times(1)
+
+	_, err := s.deadLetterService.Park(s.ctx, entities.DeadLetter{Target: "ops@example.com"}, 1)
+	s.Error(err)
+
+	s.redisClient.EXPECT().HSet(s.ctx, "report_dead_letters", gomock.Any(), gomock.Any()).Return(nil)
+	s.redisClient.EXPECT().ZAdd(s.ctx, "report_dead_letters_by_creation", gomock.Any(), gomock.Any()).Return(errors.New("redis connection failed"))
+	s.logger.EXPECT().Error("failed to index dead letter in redis", gomock.Any()).Times(1)
+
+	_, err = s.deadLetterService.Park(s.ctx, entities.DeadLetter{Target: "ops@example.com"}, 1)
+	s.Error(err)
+}
+
+func (s *DeadLetterServiceSuite) TestParkTrimsOverLimit() {
+	deadLetterService := NewDeadLetterService(s.redisClient, s.logger, env.ReportEnv{DeadLetterLimit: 2})
+	s.redisClient.EXPECT().HSet(s.ctx, "report_dead_letters", gomock.Any(), gomock.Any()).Return(nil)
+	s.redisClient.EXPECT().ZAdd(s.ctx, "report_dead_letters_by_creation", gomock.Any(), gomock.Any()).Return(nil)
+	s.redisClient.EXPECT().ZRevRange(s.ctx, "report_dead_letters_by_creation", int64(2), int64(-1)).Return([]string{"letter-2", "letter-1"}, nil)
+	s.redisClient.EXPECT().HDel(s.ctx, "report_dead_letters", "letter-2").Return(nil)
+	s.redisClient.EXPECT().HDel(s.ctx, "report_dead_letters", "letter-1").Return(nil)
+	s.redisClient.EXPECT().ZRem(s.ctx, "report_dead_letters_by_creation", "letter-2", "letter-1").Return(nil)
+	s.logger.EXPECT().Warn("report delivery parked in dead letter queue", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	s.logger.EXPECT().Warn("dropped dead letters over the limit", gomock.Any(), gomock.Any()).Times(1)
+
+	_, err := deadLetterService.Park(s.ctx, entities.DeadLetter{Target: "ops@example.com"}, 1)
+	s.NoError(err)
+}
+
+func (s *DeadLetterServiceSuite) TestParkUnlimited() {
+	deadLetterService := NewDeadLetterService(s.redisClient, s.logger, env.ReportEnv{})
+	s.redisClient.EXPECT().HSet(s.ctx, "report_dead_letters", gomock.Any(), gomock.Any()).Return(nil)
+	s.redisClient.EXPECT().ZAdd(s.ctx, "report_dead_letters_by_creation", gomock.Any(), gomock.Any()).Return(nil)
+	s.logger.EXPECT().Warn("report delivery parked in dead letter queue", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+
+	_, err := deadLetterService.Park(s.ctx, entities.DeadLetter{Target: "ops@example.com"}, 1)
+	s.NoError(err)
+}
+
+func (s *DeadLetterServiceSuite) TestGet() {
+	s.redisClient.EXPECT().HGet(s.ctx, "report_dead_letters", "letter-1").Return(s.storedLetter(entities.DeadLetter{Id: "letter-1", Target: "ops@example.com"}), nil)
+
+	letter, err := s.deadLetterService.Get(s.ctx, "letter-1")
+	s.NoError(err)
+	s.Equal("ops@example.com", letter.Target)
+}
+
+func (s *DeadLetterServiceSuite) TestGetNotFound() {
+	s.redisClient.EXPECT().HGet(s.ctx, "report_dead_letters", "missing").Return("", nil)
+
+	_, err := s.deadLetterService.Get(s.ctx, "missing")
+	s.ErrorIs(err, ErrDeadLetterNotFound)
+}
+
+func (s *DeadLetterServiceSuite) TestGetErrors() {
+	s.redisClient.EXPECT().HGet(s.ctx, "report_dead_letters", "letter-1").Return("", errors.New("redis connection failed"))
+	s.logger.EXPECT().Error("failed to get dead letter from redis", gomock.Any()).Times(1)
+
+	_, err := s.deadLetterService.Get(s.ctx, "letter-1")
+	s.Error(err)
+
+	s.redisClient.EXPECT().HGet(s.ctx, "report_dead_letters", "letter-1").Return("{", nil)
+	s.logger.EXPECT().Error("failed to decode dead letter", gomock.Any()).Times(1)
+
+	_, err = s.deadLetterService.Get(s.ctx, "letter-1")
+	s.Error(err)
+}
+
+func (s *DeadLetterServiceSuite) TestList() {
+	base := time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
+	s.redisClient.EXPECT().HScan(s.ctx, "report_dead_letters", uint64(0), int64(3)).Return(map[string]string{
+		"letter-2": s.storedLetter(entities.DeadLetter{Id: "letter-2", CreatedAt: base.Add(time.Hour)}),
+		"letter-1": s.storedLetter(entities.DeadLetter{Id: "letter-1", CreatedAt: base}),
+		"letter-3": "{",
+	}, uint64(7), nil)
+	s.logger.EXPECT().Warn("skipping undecodable dead letter", gomock.Any(), gomock.Any()).Times(1)
+
+	letters, next, err := s.deadLetterService.List(s.ctx, 0, 3)
+	s.NoError(err)
+	s.Equal(uint64(7), next)
+	s.Len(letters, 2)
+	s.Equal("letter-1", letters[0].Id)
+	s.Equal("letter-2", letters[1].Id)
+
+	s.redisClient.EXPECT().HScan(s.ctx, "report_dead_letters", uint64(7), int64(3)).Return(map[string]string{
+		"letter-4": s.storedLetter(entities.DeadLetter{Id: "letter-4", CreatedAt: base}),
+	}, uint64(0), nil)
+
+	letters, next, err = s.deadLetterService.List(s.ctx, 7, 3)
+	s.NoError(err)
+	s.Zero(next)
+	s.Len(letters, 1)
+}
+
+func (s *DeadLetterServiceSuite) TestListRedisError() {
+	s.redisClient.EXPECT().HScan(s.ctx, "report_dead_letters", uint64(0), int64(100)).Return(nil, uint64(0), errors.New("redis connection failed"))
+	s.logger.EXPECT().Error("failed to list dead letters from redis", gomock.Any()).Times(1)
+
+	letters, _, err := s.deadLetterService.List(s.ctx, 0, 0)
+	s.Error(err)
+	s.Nil(letters)
+}
+
+func (s *DeadLetterServiceSuite) TestDelete() {
+	s.redisClient.EXPECT().HGet(s.ctx, "report_dead_letters", "letter-1").Return(s.storedLetter(entities.DeadLetter{Id: "letter-1"}), nil)
+	s.redisClient.EXPECT().HDel(s.ctx, "report_dead_letters", "letter-1").Return(nil)
+	s.redisClient.EXPECT().ZRem(s.ctx, "report_dead_letters_by_creation", "letter-1").Return(nil)
+
+	s.NoError(s.deadLetterService.Delete(s.ctx, "letter-1"))
+}
+
+func (s *DeadLetterServiceSuite) TestDeleteErrors() {
+	s.redisClient.EXPECT().HGet(s.ctx, "report_dead_letters", "missing").Return("", nil)
+	s.ErrorIs(s.deadLetterService.Delete(s.ctx, "missing"), ErrDeadLetterNotFound)
+
+	s.redisClient.EXPECT().HGet(s.ctx, "report_dead_letters", "letter-1").Return(s.storedLetter(entities.DeadLetter{Id: "letter-1"}), nil)
+	s.redisClient.EXPECT().HDel(s.ctx, "report_dead_letters", "letter-1").Return(errors.New("redis connection failed"))
+	s.logger.EXPECT().Error("failed to delete dead letter from redis", gomock.Any()).Times(1)
+	s.Error(s.deadLetterService.Delete(s.ctx, "letter-1"))
+}
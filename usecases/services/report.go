@@ -1,13 +1,13 @@
 package services
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"html/template"
 	"io"
-	"os"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
@@ -17,83 +17,139 @@ import (
 	"github.com/vnFuhung2903/vcs-report-service/interfaces"
 	"github.com/vnFuhung2903/vcs-report-service/pkg/env"
 	"github.com/vnFuhung2903/vcs-report-service/pkg/logger"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/retry"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/exporters"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/i18n"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/templates"
 	"go.uber.org/zap"
 	"gopkg.in/gomail.v2"
 )
 
+const (
+	esStatusIndex  = "sms_container"
+	esPageSize     = 1000
+	esPitKeepAlive = "1m"
+)
+
+const (
+	StatisticModeMemory      = "memory"
+	StatisticModeAggregation = "aggregation"
+)
+
 type IReportService interface {
-	SendEmail(ctx context.Context, to string, totalCount int, onCount int, offCount int, totalUptime float64, startTime time.Time, endTime time.Time) error
-	CalculateReportStatistic(statusList map[string][]dto.EsStatus, overlapStatusList map[string][]dto.EsStatus, startTime time.Time, endTime time.Time) (int, int, float64)
-	GetEsStatus(ctx context.Context, limit int, startTime time.Time, endTime time.Time, order dto.SortOrder) (map[string][]dto.EsStatus, error)
+	GenerateReport(ctx context.Context, startTime time.Time, endTime time.Time, filter entities.ContainerFilter) (dto.ReportResponse, error)
+	AggregateReportStatistic(ctx context.Context, startTime time.Time, endTime time.Time, filter entities.ContainerFilter) (dto.ReportResponse, error)
+	SendEmail(ctx context.Context, to string, report dto.ReportResponse) error
+	CalculateReportStatistic(statusList map[string][]dto.EsStatus, overlapStatusList map[string][]dto.EsStatus, startTime time.Time, endTime time.Time) dto.ReportResponse
+	GetEsStatus(ctx context.Context, limit int, startTime time.Time, endTime time.Time, order dto.SortOrder, filter entities.ContainerFilter) (map[string][]dto.EsStatus, error)
 }
 
 type reportService struct {
-	mailUsername string
-	mailPassword string
-	esClient     interfaces.IElasticsearchClient
-	redisClient  interfaces.IRedisClient
-	logger       logger.ILogger
+	mailFrom      string
+	mailFromName  string
+	slaTarget     float64
+	statisticMode string
+	esClient      interfaces.IElasticsearchClient
+	redisClient   interfaces.IRedisClient
+	mailDialer    interfaces.IMailDialer
+	logger        logger.ILogger
+	retry         retry.Policy
+	attachments   []string
+	templates     templates.ITemplateRegistry
+	localizer     i18n.Localizer
+	recipients    map[string]i18n.Localizer
 }
 
-func NewReportService(esClient interfaces.IElasticsearchClient, redisClient interfaces.IRedisClient, logger logger.ILogger, env env.GomailEnv) IReportService {
-	return &reportService{
-		mailUsername: env.MailUsername,
-		mailPassword: env.MailPassword,
-		esClient:     esClient,
-		redisClient:  redisClient,
-		logger:       logger,
-	}
-}
-
-func (s *reportService) SendEmail(ctx context.Context, to string, totalCount int, onCount int, offCount int, totalUptime float64, startTime time.Time, endTime time.Time) error {
-	emailTemplate, err := os.ReadFile("html/email.html")
+func NewReportService(esClient interfaces.IElasticsearchClient, redisClient interfaces.IRedisClient, mailDialer interfaces.IMailDialer, logger logger.ILogger, templateRegistry templates.ITemplateRegistry, gomailEnv env.GomailEnv, reportEnv env.ReportEnv, retryEnv env.RetryEnv) IReportService {
+	localizer, err := i18n.NewLocalizer(reportEnv.Locale, reportEnv.Timezone)
 	if err != nil {
-		s.logger.Error("failed to read email template", zap.Error(err))
-		return err
+		logger.Warn("falling back to the default report locale", zap.String("locale", reportEnv.Locale), zap.String("timezone", reportEnv.Timezone), zap.Error(err))
+		localizer, _ = i18n.NewLocalizer(i18n.DefaultLocale, "UTC")
 	}
 
-	funcMap := template.FuncMap{
-		"formatTime": func(t time.Time) string {
-			return t.Format("2006-01-02")
-		},
+	attachments := make([]string, 0, len(reportEnv.EmailAttachments))
+	for _, format := range reportEnv.EmailAttachments {
+		if _, err := exporters.NewExporter(format, localizer); err != nil {
+			logger.Warn("skipping unsupported email attachment", zap.String("format", format), zap.Error(err))
+			continue
+		}
+		attachments = append(attachments, format)
 	}
-	temp, err := template.New("report").Funcs(funcMap).Parse(string(emailTemplate))
-	if err != nil {
-		s.logger.Error("failed to parse template", zap.Error(err))
-		return err
+
+	recipients := make(map[string]i18n.Localizer, len(reportEnv.Recipients))
+	for _, recipient := range reportEnv.Recipients {
+		recipientLocalizer, err := i18n.NewLocalizer(recipient.Locale, recipient.Timezone)
+		if err != nil {
+			logger.Warn("skipping invalid recipient preferences", zap.String("address", recipient.Address), zap.Error(err))
+			continue
+		}
+		recipients[strings.ToLower(recipient.Address)] = recipientLocalizer
 	}
 
-	report := dto.ReportResponse{
-		ContainerCount:    totalCount,
-		ContainerOnCount:  onCount,
-		ContainerOffCount: offCount,
-		TotalUptime:       totalUptime,
-		StartTime:         startTime,
-		EndTime:           endTime,
+	return &reportService{
+		mailFrom:      gomailEnv.MailFrom,
+		mailFromName:  gomailEnv.MailFromName,
+		slaTarget:     reportEnv.SLATarget,
+		statisticMode: reportEnv.StatisticMode,
+		esClient:      esClient,
+		redisClient:   redisClient,
+		mailDialer:    mailDialer,
+		logger:        logger,
+		retry:         retry.NewPolicy(retryEnv),
+		attachments:   attachments,
+		templates:     templateRegistry,
+		localizer:     localizer,
+		recipients:    recipients,
 	}
+}
 
-	var buf bytes.Buffer
-	if err := temp.Execute(&buf, report); err != nil {
-		s.logger.Error("failed to execute template", zap.Error(err))
+func (s *reportService) SendEmail(ctx context.Context, to string, report dto.ReportResponse) error {
+	localizer := s.localizerFor(to)
+	htmlBody, textBody, err := s.templates.Render(report.Template, report, localizer)
+	if err != nil {
+		s.logger.Error("failed to render email template", zap.String("template", report.Template), zap.String("locale", localizer.Locale()), zap.Error(err))
 		return err
 	}
 
-	msg := fmt.Sprintf("Container Management System Report from %s to %s", startTime.Format(time.RFC822), endTime.Format(time.RFC822))
+	msg := localizer.T("email.subject", localizer.DateTime(report.StartTime), localizer.DateTime(report.EndTime))
 
 	message := gomail.NewMessage()
-	message.SetHeader("From", s.mailUsername)
+	message.SetAddressHeader("From", s.mailFrom, s.mailFromName)
 	message.SetHeader("To", to)
 	message.SetHeader("Subject", msg)
-	message.SetBody("text/html", buf.String())
+	message.SetBody("text/plain", textBody)
+	message.AddAlternative("text/html", htmlBody)
 
-	dial := gomail.NewDialer(
-		"smtp.gmail.com",
-		587,
-		s.mailUsername,
-		s.mailPassword,
-	)
+	// Attachments are rendered for the recipient, in the same language and
+	// timezone as the email they come with.
+	for _, format := range s.attachments {
+		exporter, err := exporters.NewExporter(format, localizer)
+		if err != nil {
+			s.logger.Error("failed to export report attachment", zap.String("format", format), zap.Error(err))
+			return err
+		}
+		data, err := exporter.Export(report)
+		if err != nil {
+			s.logger.Error("failed to export report attachment", zap.String("format", format), zap.Error(err))
+			return err
+		}
+		message.Attach(exporters.FileName(exporter, report, localizer),
+			gomail.SetCopyFunc(func(w io.Writer) error {
+				_, err := w.Write(data)
+				return err
+			}),
+			gomail.SetHeader(map[string][]string{"Content-Type": {exporter.ContentType()}}),
+		)
+	}
 
-	if err := dial.DialAndSend(message); err != nil {
+	err = s.retry.Do(ctx, func(ctx context.Context) error {
+		err := s.mailDialer.DialAndSend(message)
+		if errors.Is(err, interfaces.ErrMailRejected) {
+			return retry.Permanent(err)
+		}
+		return err
+	})
+	if err != nil {
 		s.logger.Error("failed to send email", zap.Error(err))
 		return err
 	}
@@ -102,120 +158,334 @@ func (s *reportService) SendEmail(ctx context.Context, to string, totalCount int
 	return nil
 }
 
-func (s *reportService) CalculateReportStatistic(statusList map[string][]dto.EsStatus, overlapStatusList map[string][]dto.EsStatus, startTime time.Time, endTime time.Time) (int, int, float64) {
-	onCount := 0
-	offCount := 0
-	totalUptime := 0.0
-	isOnline := 0
+// localizerFor returns the language and timezone preferred by the recipient
+// at address, or the service-wide ones when none were configured.
+func (s *reportService) localizerFor(address string) i18n.Localizer {
+	if localizer, ok := s.recipients[strings.ToLower(address)]; ok {
+		return localizer
+	}
+	return s.localizer
+}
+
+func (s *reportService) GenerateReport(ctx context.Context, startTime time.Time, endTime time.Time, filter entities.ContainerFilter) (dto.ReportResponse, error) {
+	if s.statisticMode == StatisticModeAggregation {
+		var report dto.ReportResponse
+		err := s.retry.Do(ctx, func(ctx context.Context) error {
+			var err error
+			report, err = s.AggregateReportStatistic(ctx, startTime, endTime, filter)
+			return err
+		})
+		return report, err
+	}
+
+	statusList, err := s.GetEsStatus(ctx, 0, startTime, endTime, dto.Asc, filter)
+	if err != nil {
+		return dto.ReportResponse{}, err
+	}
+
+	overlapStatusList, err := s.GetEsStatus(ctx, 1, endTime, time.Now(), dto.Asc, filter)
+	if err != nil {
+		return dto.ReportResponse{}, err
+	}
+
+	return s.CalculateReportStatistic(statusList, overlapStatusList, startTime, endTime), nil
+}
+
+func (s *reportService) CalculateReportStatistic(statusList map[string][]dto.EsStatus, overlapStatusList map[string][]dto.EsStatus, startTime time.Time, endTime time.Time) dto.ReportResponse {
+	containerReports := make([]dto.ContainerReport, 0, len(statusList))
 
 	for containerId, containerStatus := range statusList {
+		containerReport := dto.ContainerReport{ContainerId: containerId}
 		previousTime := startTime
-		for _, status := range containerStatus {
+		isOnline := false
+
+		for i, status := range containerStatus {
 			if status.Status == entities.ContainerOn {
-				totalUptime += min(status.LastUpdated.Sub(startTime).Hours(), float64(status.Uptime)/3600)
-				isOnline = 1
+				containerReport.UptimeHours += min(status.LastUpdated.Sub(startTime).Hours(), float64(status.Uptime)/3600)
+				isOnline = true
 			} else {
 				previousTime = time.Unix(max(previousTime.Unix(), status.LastUpdated.Unix()), 0)
-				isOnline = 0
+				isOnline = false
+			}
+
+			if i > 0 && status.Status != containerStatus[i-1].Status {
+				containerReport.Transitions++
+			}
+			if containerReport.FirstSeen.IsZero() || status.LastUpdated.Before(containerReport.FirstSeen) {
+				containerReport.FirstSeen = status.LastUpdated
+			}
+			if status.LastUpdated.After(containerReport.LastSeen) {
+				containerReport.LastSeen = status.LastUpdated
 			}
 		}
 
 		if len(overlapStatusList[containerId]) > 0 {
-			if overlapStatusList[containerId][0].Status == entities.ContainerOn {
-				onCount++
-				totalUptime += min(endTime.Sub(previousTime).Hours(), float64(overlapStatusList[containerId][0].Uptime)/3600)
-			} else {
-				offCount++
+			overlapStatus := overlapStatusList[containerId][0]
+			if overlapStatus.Status == entities.ContainerOn {
+				containerReport.UptimeHours += min(endTime.Sub(previousTime).Hours(), float64(overlapStatus.Uptime)/3600)
 			}
-			continue
+			isOnline = overlapStatus.Status == entities.ContainerOn
 		}
 
-		onCount += isOnline
-		offCount += 1 - isOnline
+		containerReport.Status = entities.ContainerOff
+		if isOnline {
+			containerReport.Status = entities.ContainerOn
+		}
+		containerReport.Timeline = statusTimeline(containerStatus, overlapStatusList[containerId], startTime, endTime)
+		containerReports = append(containerReports, containerReport)
 	}
 
-	return onCount, offCount, totalUptime
+	return s.buildReport(containerReports, startTime, endTime)
 }
 
-func (s *reportService) GetEsStatus(ctx context.Context, limit int, startTime time.Time, endTime time.Time, order dto.SortOrder) (map[string][]dto.EsStatus, error) {
-	var body strings.Builder
+// statusTimeline splits the window into the periods a container was on or
+// off, reading the series the way CalculateReportStatistic does: an on record
+// covers the uptime leading up to it, the first record after the window
+// included, and the container is off the rest of the time.
+func statusTimeline(statuses []dto.EsStatus, overlapStatuses []dto.EsStatus, startTime time.Time, endTime time.Time) []dto.StatusSegment {
+	var timeline []dto.StatusSegment
+	cursor := startTime
+	for _, status := range slices.Concat(statuses, overlapStatuses) {
+		if status.Status != entities.ContainerOn {
+			continue
+		}
+
+		onSince := status.LastUpdated.Add(-time.Duration(status.Uptime) * time.Second)
+		if onSince.Before(cursor) {
+			onSince = cursor
+		}
+		onUntil := status.LastUpdated
+		if onUntil.After(endTime) {
+			onUntil = endTime
+		}
+		if !onUntil.After(onSince) {
+			continue
+		}
 
-	containers, err := s.redisClient.Get(ctx, "containers")
-	if err != nil {
-		s.logger.Error("failed to get container ids from redis", zap.Error(err))
-		return nil, err
+		if onSince.After(cursor) {
+			timeline = append(timeline, dto.StatusSegment{Status: entities.ContainerOff, Start: cursor, End: onSince})
+		}
+		if last := len(timeline) - 1; last >= 0 && timeline[last].Status == entities.ContainerOn && timeline[last].End.Equal(onSince) {
+			timeline[last].End = onUntil
+		} else {
+			timeline = append(timeline, dto.StatusSegment{Status: entities.ContainerOn, Start: onSince, End: onUntil})
+		}
+		cursor = onUntil
 	}
 
-	for _, container := range containers {
-		meta := map[string]string{"index": "sms_container"}
-		metaLine, _ := json.Marshal(meta)
-		body.Write(metaLine)
-		body.WriteByte('\n')
-
-		query := map[string]interface{}{
-			"query": map[string]interface{}{
-				"bool": map[string]interface{}{
-					"must": []interface{}{
-						map[string]interface{}{"term": map[string]string{"container_id.keyword": container.ContainerId}},
-						map[string]interface{}{
-							"range": map[string]interface{}{
-								"last_updated": map[string]string{
-									"gte": startTime.Format(time.RFC3339),
-									"lt":  endTime.Format(time.RFC3339),
-								},
-							},
-						},
-					},
-				},
-			},
-			"size": limit,
-			"sort": []interface{}{
-				map[string]interface{}{"counter": map[string]string{"order": string(order)}},
-			},
+	if endTime.After(cursor) {
+		timeline = append(timeline, dto.StatusSegment{Status: entities.ContainerOff, Start: cursor, End: endTime})
+	}
+	return timeline
+}
+
+func (s *reportService) buildReport(containerReports []dto.ContainerReport, startTime time.Time, endTime time.Time) dto.ReportResponse {
+	report := dto.ReportResponse{
+		SLATarget:  s.slaTarget,
+		StartTime:  startTime,
+		EndTime:    endTime,
+		Containers: containerReports,
+	}
+	windowHours := endTime.Sub(startTime).Hours()
+
+	for i := range report.Containers {
+		containerReport := &report.Containers[i]
+		if containerReport.Status == entities.ContainerOn {
+			report.ContainerOnCount++
+		} else {
+			report.ContainerOffCount++
+		}
+
+		containerReport.DowntimeHours = max(windowHours-containerReport.UptimeHours, 0)
+		if windowHours > 0 {
+			containerReport.Availability = min(containerReport.UptimeHours/windowHours*100, 100)
+		}
+		if containerReport.Availability < s.slaTarget {
+			containerReport.SLABreached = true
+			report.SLABreachedCount++
 		}
-		queryLine, _ := json.Marshal(query)
-		body.Write(queryLine)
-		body.WriteByte('\n')
+
+		report.TotalUptime += containerReport.UptimeHours
 	}
 
-	req := esapi.MsearchRequest{
-		Body: strings.NewReader(body.String()),
+	report.ContainerCount = report.ContainerOnCount + report.ContainerOffCount
+	if report.ContainerCount > 0 && windowHours > 0 {
+		report.Availability = min(report.TotalUptime/(float64(report.ContainerCount)*windowHours)*100, 100)
 	}
-	res, err := s.esClient.Do(ctx, req)
+	sort.Slice(report.Containers, func(i, j int) bool {
+		return report.Containers[i].ContainerId < report.Containers[j].ContainerId
+	})
+	return report
+}
+
+// GetEsStatus retries transient Redis and Elasticsearch failures according to
+// the configured retry policy.
+func (s *reportService) GetEsStatus(ctx context.Context, limit int, startTime time.Time, endTime time.Time, order dto.SortOrder, filter entities.ContainerFilter) (map[string][]dto.EsStatus, error) {
+	var results map[string][]dto.EsStatus
+	err := s.retry.Do(ctx, func(ctx context.Context) error {
+		var err error
+		results, err = s.getEsStatus(ctx, limit, startTime, endTime, order, filter)
+		return err
+	})
+	return results, err
+}
+
+func (s *reportService) getEsStatus(ctx context.Context, limit int, startTime time.Time, endTime time.Time, order dto.SortOrder, filter entities.ContainerFilter) (map[string][]dto.EsStatus, error) {
+	pending, err := s.containerIds(ctx, filter)
 	if err != nil {
-		s.logger.Error("failed to msearch elasticsearch status", zap.Error(err))
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	bodyBytes, err := io.ReadAll(res.Body)
+	results := make(map[string][]dto.EsStatus)
+	if len(pending) == 0 {
+		s.logger.Info("elasticsearch status retrieved successfully", zap.Int("containers_count", 0))
+		return results, nil
+	}
+
+	pitId, err := s.esClient.OpenPointInTime(ctx, esStatusIndex, esPitKeepAlive)
 	if err != nil {
-		s.logger.Error("failed to read response body", zap.Error(err))
+		s.logger.Error("failed to open elasticsearch point in time", zap.Error(err))
 		return nil, err
 	}
+	defer func() {
+		if err := s.esClient.ClosePointInTime(context.WithoutCancel(ctx), pitId); err != nil {
+			s.logger.Warn("failed to close elasticsearch point in time", zap.Error(err))
+		}
+	}()
+
+	searchAfter := make(map[string][]json.RawMessage)
+	for len(pending) > 0 {
+		var body strings.Builder
+		pageSizes := make([]int, len(pending))
+
+		for i, containerId := range pending {
+			pageSizes[i] = esPageSize
+			if limit > 0 {
+				pageSizes[i] = min(esPageSize, limit-len(results[containerId]))
+			}
+
+			body.WriteString("{}\n")
+
+			query := map[string]interface{}{
+				"query": map[string]interface{}{
+					"bool": map[string]interface{}{
+						"must": []interface{}{
+							map[string]interface{}{"term": map[string]string{"container_id.keyword": containerId}},
+							map[string]interface{}{
+								"range": map[string]interface{}{
+									"last_updated": map[string]string{
+										"gte": startTime.Format(time.RFC3339Nano),
+										"lt":  endTime.Format(time.RFC3339Nano),
+									},
+								},
+							},
+						},
+					},
+				},
+				"size": pageSizes[i],
+				"sort": []interface{}{
+					map[string]interface{}{"counter": map[string]string{"order": string(order)}},
+					map[string]interface{}{"_shard_doc": map[string]string{"order": string(order)}},
+				},
+				"pit": map[string]string{
+					"id":         pitId,
+					"keep_alive": esPitKeepAlive,
+				},
+			}
+			if after, ok := searchAfter[containerId]; ok {
+				query["search_after"] = after
+			}
+			queryLine, _ := json.Marshal(query)
+			body.Write(queryLine)
+			body.WriteByte('\n')
+		}
+
+		req := esapi.MsearchRequest{
+			Body: strings.NewReader(body.String()),
+		}
+		res, err := s.esClient.Do(ctx, req)
+		if err != nil {
+			s.logger.Error("failed to msearch elasticsearch status", zap.Error(err))
+			return nil, err
+		}
+
+		bodyBytes, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			s.logger.Error("failed to read response body", zap.Error(err))
+			return nil, err
+		}
+		if res.IsError() {
+			err := fmt.Errorf("elasticsearch msearch failed: %s", res.Status())
+			s.logger.Error("failed to msearch elasticsearch status", zap.Error(err))
+			return nil, err
+		}
 
-	var parsed struct {
-		Responses []struct {
-			Hits struct {
-				Hits []struct {
-					ID     string       `json:"_id"`
-					Source dto.EsStatus `json:"_source"`
+		var parsed struct {
+			Responses []struct {
+				PitId string          `json:"pit_id"`
+				Error json.RawMessage `json:"error"`
+				Hits  struct {
+					Hits []struct {
+						ID     string            `json:"_id"`
+						Source dto.EsStatus      `json:"_source"`
+						Sort   []json.RawMessage `json:"sort"`
+					} `json:"hits"`
 				} `json:"hits"`
-			} `json:"hits"`
-		} `json:"responses"`
+			} `json:"responses"`
+		}
+		if err := json.Unmarshal(bodyBytes, &parsed); err != nil {
+			s.logger.Error("failed to decode response body", zap.Error(err))
+			return nil, err
+		}
+
+		next := make([]string, 0, len(pending))
+		for i, response := range parsed.Responses {
+			if i >= len(pending) {
+				break
+			}
+			containerId := pending[i]
+			if len(response.Error) > 0 {
+				err := fmt.Errorf("elasticsearch search failed for container %s: %s", containerId, response.Error)
+				s.logger.Error("failed to search elasticsearch status", zap.Error(err))
+				return nil, err
+			}
+			if response.PitId != "" {
+				pitId = response.PitId
+			}
+
+			hits := response.Hits.Hits
+			for _, hit := range hits {
+				results[containerId] = append(results[containerId], hit.Source)
+			}
+
+			if len(hits) == 0 || len(hits) < pageSizes[i] || (limit > 0 && len(results[containerId]) >= limit) {
+				continue
+			}
+			searchAfter[containerId] = hits[len(hits)-1].Sort
+			next = append(next, containerId)
+		}
+		pending = next
 	}
-	if err := json.Unmarshal(bodyBytes, &parsed); err != nil {
-		s.logger.Error("failed to decode response body", zap.Error(err))
+
+	s.logger.Info("elasticsearch status retrieved successfully", zap.Int("containers_count", len(results)))
+	return results, nil
+}
+
+// containerIds returns the distinct IDs of the containers listed in Redis that
+// match filter. The Elasticsearch queries are restricted to these IDs.
+func (s *reportService) containerIds(ctx context.Context, filter entities.ContainerFilter) ([]string, error) {
+	containers, err := s.redisClient.Get(ctx, "containers")
+	if err != nil {
+		s.logger.Error("failed to get container ids from redis", zap.Error(err))
 		return nil, err
 	}
 
-	results := make(map[string][]dto.EsStatus)
-	for i, response := range parsed.Responses {
-		containerId := containers[i].ContainerId
-		for _, hit := range response.Hits.Hits {
-			results[containerId] = append(results[containerId], hit.Source)
+	containerIds := make([]string, 0, len(containers))
+	for _, container := range containers {
+		if filter.Matches(container) && !slices.Contains(containerIds, container.ContainerId) {
+			containerIds = append(containerIds, container.ContainerId)
 		}
 	}
-	s.logger.Info("elasticsearch status retrieved successfully", zap.Int("containers_count", len(results)))
-	return results, nil
+	return containerIds, nil
 }
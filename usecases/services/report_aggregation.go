@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/esapi"
+	"github.com/vnFuhung2903/vcs-report-service/dto"
+	"github.com/vnFuhung2903/vcs-report-service/entities"
+	"go.uber.org/zap"
+)
+
+const onUptimeScript = "Math.min(doc['last_updated'].value.toInstant().toEpochMilli() - params.start, doc['uptime'].value * 1000L) / 3600000.0"
+
+type esAggregatedStatus struct {
+	Hits struct {
+		Hits []struct {
+			Source dto.EsStatus `json:"_source"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+type esContainerBucket struct {
+	Key       string `json:"key"`
+	FirstSeen struct {
+		Value *float64 `json:"value"`
+	} `json:"first_seen"`
+	LastSeen struct {
+		Value *float64 `json:"value"`
+	} `json:"last_seen"`
+	Latest esAggregatedStatus `json:"latest"`
+	First  esAggregatedStatus `json:"first"`
+	Off    struct {
+		LastOff struct {
+			Value *float64 `json:"value"`
+		} `json:"last_off"`
+	} `json:"off"`
+	On struct {
+		Uptime struct {
+			Value float64 `json:"value"`
+		} `json:"uptime"`
+	} `json:"on"`
+}
+
+// AggregateReportStatistic computes the report from per-container
+// aggregations so that no raw status document leaves Elasticsearch. Counting
+// transitions would need every status change of a container in order on the
+// coordinating node, so they are not reported in this mode and the report is
+// flagged as aggregated for templates to leave them out.
+func (s *reportService) AggregateReportStatistic(ctx context.Context, startTime time.Time, endTime time.Time, filter entities.ContainerFilter) (dto.ReportResponse, error) {
+	containerIds, err := s.containerIds(ctx, filter)
+	if err != nil {
+		return dto.ReportResponse{}, err
+	}
+	if len(containerIds) == 0 {
+		report := s.buildReport([]dto.ContainerReport{}, startTime, endTime)
+		report.Aggregated = true
+		return report, nil
+	}
+
+	windowQuery := map[string]interface{}{
+		"size":  0,
+		"query": aggregationFilter(containerIds, startTime, endTime),
+		"aggs": map[string]interface{}{
+			"containers": map[string]interface{}{
+				"terms": map[string]interface{}{"field": "container_id.keyword", "size": len(containerIds)},
+				"aggs": map[string]interface{}{
+					"first_seen": map[string]interface{}{"min": map[string]string{"field": "last_updated"}},
+					"last_seen":  map[string]interface{}{"max": map[string]string{"field": "last_updated"}},
+					"latest":     latestStatusAggregation(dto.Dsc),
+					"off": map[string]interface{}{
+						"filter": map[string]interface{}{"term": map[string]string{"status.keyword": string(entities.ContainerOff)}},
+						"aggs": map[string]interface{}{
+							"last_off": map[string]interface{}{"max": map[string]string{"field": "last_updated"}},
+						},
+					},
+					"on": map[string]interface{}{
+						"filter": map[string]interface{}{"term": map[string]string{"status.keyword": string(entities.ContainerOn)}},
+						"aggs": map[string]interface{}{
+							"uptime": map[string]interface{}{
+								"sum": map[string]interface{}{
+									"script": map[string]interface{}{
+										"source": onUptimeScript,
+										"params": map[string]int64{"start": startTime.UnixMilli()},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	overlapQuery := map[string]interface{}{
+		"size":  0,
+		"query": aggregationFilter(containerIds, endTime, time.Now()),
+		"aggs": map[string]interface{}{
+			"containers": map[string]interface{}{
+				"terms": map[string]interface{}{"field": "container_id.keyword", "size": len(containerIds)},
+				"aggs": map[string]interface{}{
+					"first": latestStatusAggregation(dto.Asc),
+				},
+			},
+		},
+	}
+
+	var body strings.Builder
+	for _, query := range []map[string]interface{}{windowQuery, overlapQuery} {
+		metaLine, _ := json.Marshal(map[string]string{"index": esStatusIndex})
+		body.Write(metaLine)
+		body.WriteByte('\n')
+		queryLine, _ := json.Marshal(query)
+		body.Write(queryLine)
+		body.WriteByte('\n')
+	}
+
+	req := esapi.MsearchRequest{
+		Body: strings.NewReader(body.String()),
+	}
+	res, err := s.esClient.Do(ctx, req)
+	if err != nil {
+		s.logger.Error("failed to aggregate elasticsearch status", zap.Error(err))
+		return dto.ReportResponse{}, err
+	}
+	defer res.Body.Close()
+
+	bodyBytes, err := io.ReadAll(res.Body)
+	if err != nil {
+		s.logger.Error("failed to read response body", zap.Error(err))
+		return dto.ReportResponse{}, err
+	}
+	if res.IsError() {
+		err := fmt.Errorf("elasticsearch aggregation failed: %s", res.Status())
+		s.logger.Error("failed to aggregate elasticsearch status", zap.Error(err))
+		return dto.ReportResponse{}, err
+	}
+
+	var parsed struct {
+		Responses []struct {
+			Error        json.RawMessage `json:"error"`
+			Aggregations struct {
+				Containers struct {
+					Buckets []esContainerBucket `json:"buckets"`
+				} `json:"containers"`
+			} `json:"aggregations"`
+		} `json:"responses"`
+	}
+	if err := json.Unmarshal(bodyBytes, &parsed); err != nil {
+		s.logger.Error("failed to decode response body", zap.Error(err))
+		return dto.ReportResponse{}, err
+	}
+	if len(parsed.Responses) != 2 {
+		err := fmt.Errorf("elasticsearch aggregation returned %d responses", len(parsed.Responses))
+		s.logger.Error("failed to aggregate elasticsearch status", zap.Error(err))
+		return dto.ReportResponse{}, err
+	}
+	for _, response := range parsed.Responses {
+		if len(response.Error) > 0 {
+			err := fmt.Errorf("elasticsearch aggregation failed: %s", response.Error)
+			s.logger.Error("failed to aggregate elasticsearch status", zap.Error(err))
+			return dto.ReportResponse{}, err
+		}
+	}
+
+	overlapStatus := make(map[string]dto.EsStatus)
+	for _, bucket := range parsed.Responses[1].Aggregations.Containers.Buckets {
+		if len(bucket.First.Hits.Hits) > 0 {
+			overlapStatus[bucket.Key] = bucket.First.Hits.Hits[0].Source
+		}
+	}
+
+	containerReports := make([]dto.ContainerReport, 0, len(parsed.Responses[0].Aggregations.Containers.Buckets))
+	for _, bucket := range parsed.Responses[0].Aggregations.Containers.Buckets {
+		containerReport := dto.ContainerReport{
+			ContainerId: bucket.Key,
+			UptimeHours: bucket.On.Uptime.Value,
+			FirstSeen:   millisToTime(bucket.FirstSeen.Value),
+			LastSeen:    millisToTime(bucket.LastSeen.Value),
+		}
+
+		previousTime := startTime
+		if bucket.Off.LastOff.Value != nil {
+			previousTime = time.Unix(max(previousTime.Unix(), int64(*bucket.Off.LastOff.Value)/1000), 0)
+		}
+
+		isOnline := len(bucket.Latest.Hits.Hits) > 0 && bucket.Latest.Hits.Hits[0].Source.Status == entities.ContainerOn
+		if status, ok := overlapStatus[bucket.Key]; ok {
+			if status.Status == entities.ContainerOn {
+				containerReport.UptimeHours += min(endTime.Sub(previousTime).Hours(), float64(status.Uptime)/3600)
+			}
+			isOnline = status.Status == entities.ContainerOn
+		}
+
+		containerReport.Status = entities.ContainerOff
+		if isOnline {
+			containerReport.Status = entities.ContainerOn
+		}
+		containerReports = append(containerReports, containerReport)
+	}
+
+	s.logger.Info("elasticsearch status aggregated successfully", zap.Int("containers_count", len(containerReports)))
+	report := s.buildReport(containerReports, startTime, endTime)
+	report.Aggregated = true
+	return report, nil
+}
+
+func aggregationFilter(containerIds []string, startTime time.Time, endTime time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"bool": map[string]interface{}{
+			"filter": []interface{}{
+				map[string]interface{}{"terms": map[string]interface{}{"container_id.keyword": containerIds}},
+				map[string]interface{}{
+					"range": map[string]interface{}{
+						"last_updated": map[string]string{
+							"gte": startTime.Format(time.RFC3339Nano),
+							"lt":  endTime.Format(time.RFC3339Nano),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func latestStatusAggregation(order dto.SortOrder) map[string]interface{} {
+	return map[string]interface{}{
+		"top_hits": map[string]interface{}{
+			"size": 1,
+			"sort": []interface{}{
+				map[string]interface{}{"counter": map[string]string{"order": string(order)}},
+			},
+			"_source": map[string]interface{}{
+				"includes": []string{"container_id", "status", "uptime", "last_updated", "counter"},
+			},
+		},
+	}
+}
+
+func millisToTime(value *float64) time.Time {
+	if value == nil {
+		return time.Time{}
+	}
+	return time.UnixMilli(int64(*value)).UTC()
+}
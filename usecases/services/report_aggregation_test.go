@@ -0,0 +1,366 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/esapi"
+	"github.com/golang/mock/gomock"
+
+	"github.com/vnFuhung2903/vcs-report-service/dto"
+	"github.com/vnFuhung2903/vcs-report-service/entities"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/env"
+)
+
+// aggregateFakeStatus answers the aggregations of AggregateReportStatistic
+// from the matched documents the way Elasticsearch would.
+func aggregateFakeStatus(query fakeQuery, matched map[string][]dto.EsStatus) interface{} {
+	var containersAgg struct {
+		Aggs struct {
+			On struct {
+				Aggs struct {
+					Uptime struct {
+						Sum struct {
+							Script struct {
+								Params struct {
+									Start int64 `json:"start"`
+								} `json:"params"`
+							} `json:"script"`
+						} `json:"sum"`
+					} `json:"uptime"`
+				} `json:"aggs"`
+			} `json:"on"`
+			First json.RawMessage `json:"first"`
+		} `json:"aggs"`
+	}
+	_ = json.Unmarshal(query.Aggs["containers"], &containersAgg)
+
+	topHit := func(doc dto.EsStatus) map[string]interface{} {
+		return map[string]interface{}{
+			"hits": map[string]interface{}{
+				"hits": []interface{}{map[string]interface{}{"_source": doc}},
+			},
+		}
+	}
+	millis := func(t time.Time) *float64 {
+		value := float64(t.UnixMilli())
+		return &value
+	}
+
+	buckets := []interface{}{}
+	for containerId, docs := range matched {
+		if len(docs) == 0 {
+			continue
+		}
+
+		bucket := map[string]interface{}{"key": containerId, "doc_count": len(docs)}
+		if containersAgg.Aggs.First != nil {
+			bucket["first"] = topHit(docs[0])
+			buckets = append(buckets, bucket)
+			continue
+		}
+
+		firstSeen, lastSeen := docs[0].LastUpdated, docs[0].LastUpdated
+		var lastOff *float64
+		uptime := 0.0
+		for _, doc := range docs {
+			if doc.LastUpdated.Before(firstSeen) {
+				firstSeen = doc.LastUpdated
+			}
+			if doc.LastUpdated.After(lastSeen) {
+				lastSeen = doc.LastUpdated
+			}
+			if doc.Status == entities.ContainerOff {
+				if lastOff == nil || float64(doc.LastUpdated.UnixMilli()) > *lastOff {
+					lastOff = millis(doc.LastUpdated)
+				}
+			} else {
+				start := containersAgg.Aggs.On.Aggs.Uptime.Sum.Script.Params.Start
+				uptime += float64(min(doc.LastUpdated.UnixMilli()-start, doc.Uptime*1000)) / 3600000.0
+			}
+		}
+
+		bucket["first_seen"] = map[string]interface{}{"value": millis(firstSeen)}
+		bucket["last_seen"] = map[string]interface{}{"value": millis(lastSeen)}
+		bucket["latest"] = topHit(docs[len(docs)-1])
+		bucket["off"] = map[string]interface{}{"last_off": map[string]interface{}{"value": lastOff}}
+		bucket["on"] = map[string]interface{}{"uptime": map[string]interface{}{"value": uptime}}
+		buckets = append(buckets, bucket)
+	}
+
+	return map[string]interface{}{
+		"containers": map[string]interface{}{"buckets": buckets},
+	}
+}
+
+func (s *ReportServiceSuite) TestAggregateReportStatisticMatchesInMemoryCalculation() {
+	ctx := context.Background()
+	startTime := time.Now().UTC().Truncate(time.Hour).Add(-48 * time.Hour)
+	endTime := startTime.Add(24 * time.Hour)
+
+	at := func(offset time.Duration) time.Time { return startTime.Add(offset) }
+	server := newFakeElasticsearchServer(map[string][]dto.EsStatus{
+		"alpha": {
+			{ContainerId: "alpha", Status: entities.ContainerOn, Uptime: 7200, LastUpdated: at(1 * time.Hour), Counter: 1},
+			{ContainerId: "alpha", Status: entities.ContainerOff, Uptime: 0, LastUpdated: at(5 * time.Hour), Counter: 2},
+			{ContainerId: "alpha", Status: entities.ContainerOn, Uptime: 3600, LastUpdated: at(10 * time.Hour), Counter: 3},
+			{ContainerId: "alpha", Status: entities.ContainerOn, Uptime: 36000, LastUpdated: at(20 * time.Hour), Counter: 4},
+			{ContainerId: "alpha", Status: entities.ContainerOn, Uptime: 18000, LastUpdated: at(25 * time.Hour), Counter: 5},
+		},
+		"beta": {
+			{ContainerId: "beta", Status: entities.ContainerOff, Uptime: 0, LastUpdated: at(2 * time.Hour), Counter: 1},
+			{ContainerId: "beta", Status: entities.ContainerOff, Uptime: 0, LastUpdated: at(12 * time.Hour), Counter: 2},
+		},
+		"gamma": {
+			{ContainerId: "gamma", Status: entities.ContainerOn, Uptime: 1800, LastUpdated: at(3 * time.Hour), Counter: 1},
+			{ContainerId: "gamma", Status: entities.ContainerOff, Uptime: 0, LastUpdated: at(24*time.Hour + 30*time.Minute), Counter: 2},
+		},
+		"delta": {
+			{ContainerId: "delta", Status: entities.ContainerOn, Uptime: 600, LastUpdated: at(26 * time.Hour), Counter: 1},
+		},
+	})
+	defer server.Close()
+
+	s.redisClient.EXPECT().
+		Get(ctx, "containers").
+		Return([]entities.ContainerWithStatus{
+			{ContainerId: "alpha", Status: entities.ContainerOn},
+			{ContainerId: "beta", Status: entities.ContainerOff},
+			{ContainerId: "gamma", Status: entities.ContainerOff},
+			{ContainerId: "delta", Status: entities.ContainerOn},
+		}, nil).
+		AnyTimes()
+
+	s.logger.EXPECT().Info("elasticsearch status retrieved successfully", gomock.Any()).Times(2)
+	s.logger.EXPECT().Info("elasticsearch status aggregated successfully", gomock.Any()).Times(1)
+
+	memoryService := NewReportService(server.client(), s.redisClient, s.mailDialer, s.logger, s.templates, env.GomailEnv{}, env.ReportEnv{SLATarget: 99.9, StatisticMode: StatisticModeMemory}, env.RetryEnv{})
+	expected, err := memoryService.GenerateReport(ctx, startTime, endTime, entities.ContainerFilter{})
+	s.Require().NoError(err)
+	s.False(expected.Aggregated)
+
+	aggregationService := NewReportService(server.client(), s.redisClient, s.mailDialer, s.logger, s.templates, env.GomailEnv{}, env.ReportEnv{SLATarget: 99.9, StatisticMode: StatisticModeAggregation}, env.RetryEnv{})
+	actual, err := aggregationService.GenerateReport(ctx, startTime, endTime, entities.ContainerFilter{})
+	s.Require().NoError(err)
+	s.True(actual.Aggregated)
+
+	s.Equal(3, expected.ContainerCount)
+	s.Equal(expected.ContainerCount, actual.ContainerCount)
+	s.Equal(expected.ContainerOnCount, actual.ContainerOnCount)
+	s.Equal(expected.ContainerOffCount, actual.ContainerOffCount)
+	s.Equal(expected.SLABreachedCount, actual.SLABreachedCount)
+	s.InDelta(expected.TotalUptime, actual.TotalUptime, 1e-9)
+	s.InDelta(expected.Availability, actual.Availability, 1e-9)
+
+	s.Require().Len(actual.Containers, len(expected.Containers))
+	for i, container := range expected.Containers {
+		s.Equal(container.ContainerId, actual.Containers[i].ContainerId)
+		s.Equal(container.Status, actual.Containers[i].Status)
+		s.Equal(container.SLABreached, actual.Containers[i].SLABreached)
+		s.InDelta(container.UptimeHours, actual.Containers[i].UptimeHours, 1e-9)
+		s.InDelta(container.DowntimeHours, actual.Containers[i].DowntimeHours, 1e-9)
+		s.InDelta(container.Availability, actual.Containers[i].Availability, 1e-9)
+		s.True(container.FirstSeen.Equal(actual.Containers[i].FirstSeen))
+		s.True(container.LastSeen.Equal(actual.Containers[i].LastSeen))
+	}
+}
+
+func (s *ReportServiceSuite) TestAggregateReportStatisticQuery() {
+	ctx := context.Background()
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	endTime := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	at := func(offset time.Duration) int64 { return startTime.Add(offset).UnixMilli() }
+
+	s.redisClient.EXPECT().
+		Get(ctx, "containers").
+		Return([]entities.ContainerWithStatus{
+			{ContainerId: "alpha", Status: entities.ContainerOn},
+			{ContainerId: "beta", Status: entities.ContainerOff},
+		}, nil)
+
+	esResponse := fmt.Sprintf(`{"responses": [
+		{"aggregations": {"containers": {"buckets": [
+			{
+				"key": "alpha",
+				"first_seen": {"value": %d},
+				"last_seen": {"value": %d},
+				"latest": {"hits": {"hits": [{"_source": {"container_id": "alpha", "status": "ON", "uptime": 3600, "last_updated": "2024-01-01T22:00:00Z", "counter": 4}}]}},
+				"off": {"last_off": {"value": %d}},
+				"on": {"uptime": {"value": 3.5}}
+			},
+			{
+				"key": "beta",
+				"first_seen": {"value": %d},
+				"last_seen": {"value": %d},
+				"latest": {"hits": {"hits": [{"_source": {"container_id": "beta", "status": "OFF", "uptime": 0, "last_updated": "2024-01-01T12:00:00Z", "counter": 2}}]}},
+				"off": {"last_off": {"value": %d}},
+				"on": {"uptime": {"value": 0}}
+			}
+		]}}},
+		{"aggregations": {"containers": {"buckets": [
+			{"key": "alpha", "first": {"hits": {"hits": [{"_source": {"container_id": "alpha", "status": "ON", "uptime": 7200, "last_updated": "2024-01-02T01:00:00Z", "counter": 5}}]}}}
+		]}}}
+	]}`, at(time.Hour), at(22*time.Hour), at(20*time.Hour), at(2*time.Hour), at(12*time.Hour), at(12*time.Hour))
+
+	s.esClient.EXPECT().
+		Do(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, req esapi.Request) (*esapi.Response, error) {
+			body, err := io.ReadAll(req.(esapi.MsearchRequest).Body)
+			s.Require().NoError(err)
+			lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+			s.Require().Len(lines, 4)
+
+			var window, overlap map[string]interface{}
+			s.Require().NoError(json.Unmarshal([]byte(lines[1]), &window))
+			s.Require().NoError(json.Unmarshal([]byte(lines[3]), &overlap))
+
+			query, err := json.Marshal(window["query"])
+			s.Require().NoError(err)
+			s.JSONEq(`{"bool": {"filter": [
+				{"terms": {"container_id.keyword": ["alpha", "beta"]}},
+				{"range": {"last_updated": {"gte": "2024-01-01T00:00:00Z", "lt": "2024-01-02T00:00:00Z"}}}
+			]}}`, string(query))
+
+			containers := window["aggs"].(map[string]interface{})["containers"].(map[string]interface{})
+			s.Equal(map[string]interface{}{"field": "container_id.keyword", "size": float64(2)}, containers["terms"])
+			aggs := containers["aggs"].(map[string]interface{})
+			s.ElementsMatch([]string{"first_seen", "last_seen", "latest", "off", "on"}, keys(aggs))
+			s.NotContains(string(body), "scripted_metric")
+
+			script := aggs["on"].(map[string]interface{})["aggs"].(map[string]interface{})["uptime"].(map[string]interface{})["sum"].(map[string]interface{})["script"].(map[string]interface{})
+			s.Equal(onUptimeScript, script["source"])
+			s.Equal(map[string]interface{}{"start": float64(startTime.UnixMilli())}, script["params"])
+
+			overlapAggs := overlap["aggs"].(map[string]interface{})["containers"].(map[string]interface{})["aggs"].(map[string]interface{})
+			s.ElementsMatch([]string{"first"}, keys(overlapAggs))
+			return NewMockElasticsearchResponse(esResponse, 200), nil
+		})
+
+	s.logger.EXPECT().Info("elasticsearch status aggregated successfully", gomock.Any()).Times(1)
+
+	report, err := s.reportService.AggregateReportStatistic(ctx, startTime, endTime, entities.ContainerFilter{})
+	s.Require().NoError(err)
+
+	s.Equal(2, report.ContainerCount)
+	s.Equal(1, report.ContainerOnCount)
+	s.Equal(1, report.ContainerOffCount)
+	s.True(report.Aggregated)
+	s.Require().Len(report.Containers, 2)
+
+	alpha := report.Containers[0]
+	s.Equal("alpha", alpha.ContainerId)
+	s.Equal(entities.ContainerOn, alpha.Status)
+	s.InDelta(5.5, alpha.UptimeHours, 1e-9)
+	s.Zero(alpha.Transitions)
+	s.True(startTime.Add(time.Hour).Equal(alpha.FirstSeen))
+	s.True(startTime.Add(22 * time.Hour).Equal(alpha.LastSeen))
+
+	beta := report.Containers[1]
+	s.Equal("beta", beta.ContainerId)
+	s.Equal(entities.ContainerOff, beta.Status)
+	s.Zero(beta.UptimeHours)
+}
+
+func keys(m map[string]interface{}) []string {
+	result := make([]string, 0, len(m))
+	for key := range m {
+		result = append(result, key)
+	}
+	return result
+}
+
+func (s *ReportServiceSuite) TestAggregationFilterKeepsSubSecondBounds() {
+	startTime := time.Date(2024, 1, 1, 12, 0, 0, 500*int(time.Millisecond), time.UTC)
+	endTime := time.Date(2024, 1, 1, 12, 0, 1, 250*int(time.Millisecond), time.UTC)
+
+	query, err := json.Marshal(aggregationFilter([]string{"alpha"}, startTime, endTime))
+	s.Require().NoError(err)
+	s.JSONEq(`{"bool": {"filter": [
+		{"terms": {"container_id.keyword": ["alpha"]}},
+		{"range": {"last_updated": {"gte": "2024-01-01T12:00:00.5Z", "lt": "2024-01-01T12:00:01.25Z"}}}
+	]}}`, string(query))
+}
+
+func (s *ReportServiceSuite) TestAggregateReportStatisticNoContainers() {
+	ctx := context.Background()
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	endTime := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	s.redisClient.EXPECT().
+		Get(ctx, "containers").
+		Return([]entities.ContainerWithStatus{}, nil)
+
+	report, err := s.reportService.AggregateReportStatistic(ctx, startTime, endTime, entities.ContainerFilter{})
+
+	s.NoError(err)
+	s.Equal(0, report.ContainerCount)
+	s.Empty(report.Containers)
+}
+
+func (s *ReportServiceSuite) TestAggregateReportStatisticRedisError() {
+	ctx := context.Background()
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	endTime := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	s.redisClient.EXPECT().
+		Get(ctx, "containers").
+		Return(nil, errors.New("redis connection failed"))
+
+	s.logger.EXPECT().
+		Error("failed to get container ids from redis", gomock.Any()).
+		Times(1)
+
+	_, err := s.reportService.AggregateReportStatistic(ctx, startTime, endTime, entities.ContainerFilter{})
+
+	s.Error(err)
+}
+
+func (s *ReportServiceSuite) TestAggregateReportStatisticElasticsearchError() {
+	ctx := context.Background()
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	endTime := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	s.redisClient.EXPECT().
+		Get(ctx, "containers").
+		Return([]entities.ContainerWithStatus{{ContainerId: "container1", Status: entities.ContainerOn}}, nil)
+
+	s.esClient.EXPECT().
+		Do(ctx, gomock.Any()).
+		Return(nil, errors.New("elasticsearch connection failed"))
+
+	s.logger.EXPECT().
+		Error("failed to aggregate elasticsearch status", gomock.Any()).
+		Times(1)
+
+	_, err := s.reportService.AggregateReportStatistic(ctx, startTime, endTime, entities.ContainerFilter{})
+
+	s.Error(err)
+}
+
+func (s *ReportServiceSuite) TestAggregateReportStatisticResponseError() {
+	ctx := context.Background()
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	endTime := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	s.redisClient.EXPECT().
+		Get(ctx, "containers").
+		Return([]entities.ContainerWithStatus{{ContainerId: "container1", Status: entities.ContainerOn}}, nil)
+
+	s.esClient.EXPECT().
+		Do(ctx, gomock.Any()).
+		Return(NewMockElasticsearchResponse(`{"responses":[{"error":{"type":"script_exception"},"status":400},{"aggregations":{}}]}`, 200), nil)
+
+	s.logger.EXPECT().
+		Error("failed to aggregate elasticsearch status", gomock.Any()).
+		Times(1)
+
+	_, err := s.reportService.AggregateReportStatistic(ctx, startTime, endTime, entities.ContainerFilter{})
+
+	s.Error(err)
+	s.Contains(err.Error(), "script_exception")
+}
@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vnFuhung2903/vcs-report-service/entities"
+	"github.com/vnFuhung2903/vcs-report-service/interfaces"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/env"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	jobKey           = "report_jobs"
+	jobQueueKey      = "report_job_queue"
+	jobProcessingKey = "report_job_processing"
+	jobWorkerKey     = "report_job_workers"
+	jobFinishedKey   = "report_jobs_by_finish"
+)
+
+var ErrJobNotFound = errors.New("report job not found")
+
+type IReportJobService interface {
+	Enqueue(ctx context.Context, job entities.ReportJob) (entities.ReportJob, error)
+	Get(ctx context.Context, owner string, id string) (entities.ReportJob, error)
+	Dequeue(ctx context.Context, worker string, timeout time.Duration) (*entities.ReportJob, error)
+	Ack(ctx context.Context, worker string, id string) error
+	Save(ctx context.Context, job entities.ReportJob) error
+	Register(ctx context.Context, worker string) error
+	Workers(ctx context.Context) ([]string, error)
+	Requeue(ctx context.Context, worker string) (int, error)
+}
+
+type reportJobService struct {
+	redisClient interfaces.IRedisClient
+	logger      logger.ILogger
+	retention   time.Duration
+}
+
+func NewReportJobService(redisClient interfaces.IRedisClient, logger logger.ILogger, reportEnv env.ReportEnv) IReportJobService {
+	return &reportJobService{
+		redisClient: redisClient,
+		logger:      logger,
+		retention:   reportEnv.JobRetention,
+	}
+}
+
+// Enqueue stores job as queued and pushes it to the queue shared by every
+// job worker.
+func (s *reportJobService) Enqueue(ctx context.Context, job entities.ReportJob) (entities.ReportJob, error) {
+	job.Id = uuid.NewString()
+	job.Status = entities.JobQueued
+	job.CreatedAt = time.Now()
+
+	if err := s.Save(ctx, job); err != nil {
+		return entities.ReportJob{}, err
+	}
+
+	if err := s.redisClient.LPush(ctx, jobQueueKey, job.Id); err != nil {
+		s.logger.Error("failed to push report job to redis", zap.Error(err))
+		if err := s.redisClient.HDel(ctx, jobKey, job.Id); err != nil {
+			s.logger.Warn("failed to remove unqueued report job", zap.String("id", job.Id), zap.Error(err))
+		}
+		return entities.ReportJob{}, err
+	}
+
+	s.logger.Info("report job queued", zap.String("id", job.Id), zap.String("owner", job.Owner))
+	return job, nil
+}
+
+func (s *reportJobService) Get(ctx context.Context, owner string, id string) (entities.ReportJob, error) {
+	job, err := s.get(ctx, id)
+	if err != nil {
+		return entities.ReportJob{}, err
+	}
+	if job.Owner != owner {
+		return entities.ReportJob{}, ErrJobNotFound
+	}
+	return job, nil
+}
+
+// Dequeue waits up to timeout for the next queued job and moves it to the
+// processing list of worker, where it stays until Ack. It returns a nil job
+// when the queue stayed empty or the job expired before being picked up.
+func (s *reportJobService) Dequeue(ctx context.Context, worker string, timeout time.Duration) (*entities.ReportJob, error) {
+	id, err := s.redisClient.BLMove(ctx, timeout, jobQueueKey, processingKey(worker))
+	if err != nil {
+		s.logger.Error("failed to pop report job from redis", zap.Error(err))
+		return nil, err
+	}
+	if id == "" {
+		return nil, nil
+	}
+
+	job, err := s.get(ctx, id)
+	if errors.Is(err, ErrJobNotFound) {
+		s.logger.Warn("skipping missing report job", zap.String("id", id))
+		return nil, s.Ack(ctx, worker, id)
+	} else if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Ack drops the job id from the processing list of worker once it finished.
+func (s *reportJobService) Ack(ctx context.Context, worker string, id string) error {
+	if err := s.redisClient.LRem(ctx, processingKey(worker), id); err != nil {
+		s.logger.Error("failed to acknowledge report job in redis", zap.String("id", id), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Save stores job. Finished jobs are indexed by their finish time, and jobs
+// that finished longer than the retention ago are pruned.
+func (s *reportJobService) Save(ctx context.Context, job entities.ReportJob) error {
+	val, err := json.Marshal(job)
+	if err != nil {
+		s.logger.Error("failed to encode report job", zap.Error(err))
+		return err
+	}
+
+	if err := s.redisClient.HSet(ctx, jobKey, job.Id, string(val)); err != nil {
+		s.logger.Error("failed to save report job to redis", zap.Error(err))
+		return err
+	}
+
+	if job.Status == entities.JobSucceeded || job.Status == entities.JobFailed {
+		if err := s.redisClient.ZAdd(ctx, jobFinishedKey, float64(job.FinishedAt.UnixMilli()), job.Id); err != nil {
+			s.logger.Error("failed to index report job in redis", zap.Error(err))
+			return err
+		}
+		s.prune(ctx)
+	}
+	return nil
+}
+
+// Register records worker so that its processing list can be found and
+// requeued by another worker if it dies.
+func (s *reportJobService) Register(ctx context.Context, worker string) error {
+	if err := s.redisClient.HSet(ctx, jobWorkerKey, worker, time.Now().UTC().Format(time.RFC3339)); err != nil {
+		s.logger.Error("failed to register report job worker in redis", zap.String("worker", worker), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (s *reportJobService) Workers(ctx context.Context) ([]string, error) {
+	values, err := s.redisClient.HGetAll(ctx, jobWorkerKey)
+	if err != nil {
+		s.logger.Error("failed to list report job workers from redis", zap.Error(err))
+		return nil, err
+	}
+
+	workers := make([]string, 0, len(values))
+	for worker := range values {
+		workers = append(workers, worker)
+	}
+	sort.Strings(workers)
+	return workers, nil
+}
+
+// Requeue moves every job left in the processing list of worker back to the
+// front of the queue, then forgets worker. It returns how many jobs moved.
+func (s *reportJobService) Requeue(ctx context.Context, worker string) (int, error) {
+	requeued := 0
+	for {
+		id, err := s.redisClient.LMove(ctx, processingKey(worker), jobQueueKey)
+		if err != nil {
+			s.logger.Error("failed to requeue report job in redis", zap.String("worker", worker), zap.Error(err))
+			return requeued, err
+		}
+		if id == "" {
+			break
+		}
+		requeued++
+	}
+
+	if err := s.redisClient.HDel(ctx, jobWorkerKey, worker); err != nil {
+		s.logger.Error("failed to unregister report job worker in redis", zap.String("worker", worker), zap.Error(err))
+		return requeued, err
+	}
+	return requeued, nil
+}
+
+func (s *reportJobService) get(ctx context.Context, id string) (entities.ReportJob, error) {
+	val, err := s.redisClient.HGet(ctx, jobKey, id)
+	if err != nil {
+		s.logger.Error("failed to get report job from redis", zap.Error(err))
+		return entities.ReportJob{}, err
+	}
+	if val == "" {
+		return entities.ReportJob{}, ErrJobNotFound
+	}
+
+	var job entities.ReportJob
+	if err := json.Unmarshal([]byte(val), &job); err != nil {
+		s.logger.Error("failed to decode report job", zap.Error(err))
+		return entities.ReportJob{}, err
+	}
+	return job, nil
+}
+
+// prune drops the jobs that finished before the retention period, reading
+// their ids from the finish time index rather than every stored job.
+func (s *reportJobService) prune(ctx context.Context) {
+	if s.retention <= 0 {
+		return
+	}
+
+	cutoff := time.Now().Add(-s.retention).UnixMilli()
+	ids, err := s.redisClient.ZRangeByScore(ctx, jobFinishedKey, "-inf", fmt.Sprintf("(%d", cutoff))
+	if err != nil {
+		s.logger.Warn("failed to list expired report jobs", zap.Error(err))
+		return
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	for _, id := range ids {
+		if err := s.redisClient.HDel(ctx, jobKey, id); err != nil {
+			s.logger.Warn("failed to prune report job", zap.String("id", id), zap.Error(err))
+			return
+		}
+	}
+	if err := s.redisClient.ZRem(ctx, jobFinishedKey, ids...); err != nil {
+		s.logger.Warn("failed to prune report job index", zap.Error(err))
+	}
+}
+
+func processingKey(worker string) string {
+	return jobProcessingKey + ":" + worker
+}
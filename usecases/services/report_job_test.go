@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/vnFuhung2903/vcs-report-service/entities"
+	"github.com/vnFuhung2903/vcs-report-service/mocks/interfaces"
+	"github.com/vnFuhung2903/vcs-report-service/mocks/logger"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/env"
+)
+
+type ReportJobServiceSuite struct {
+	suite.Suite
+	ctrl        *gomock.Controller
+	redisClient *interfaces.MockIRedisClient
+	logger      *logger.MockILogger
+	jobService  IReportJobService
+	ctx         context.Context
+}
+
+func (s *ReportJobServiceSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.redisClient = interfaces.NewMockIRedisClient(s.ctrl)
+	s.logger = logger.NewMockILogger(s.ctrl)
+	s.jobService = NewReportJobService(s.redisClient, s.logger, env.ReportEnv{JobRetention: 24 * time.Hour})
+	s.ctx = context.Background()
+}
+
+func (s *ReportJobServiceSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func TestReportJobServiceSuite(t *testing.T) {
+	suite.Run(t, new(ReportJobServiceSuite))
+}
+
+func (s *ReportJobServiceSuite) storedJob(job entities.ReportJob) string {
+	val, _ := json.Marshal(job)
+	return string(val)
+}
+
+func (s *ReportJobServiceSuite) TestEnqueue() {
+	var stored string
+	s.redisClient.EXPECT().
+		HSet(s.ctx, "report_jobs", gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, key string, field string, value string) error {
+			stored = value
+			return nil
+		})
+	s.redisClient.EXPECT().LPush(s.ctx, "report_job_queue", gomock.Any()).Return(nil)
+	s.logger.EXPECT().Info("report job queued", gomock.Any(), gomock.Any()).Times(1)
+
+	job, err := s.jobService.Enqueue(s.ctx, entities.ReportJob{Owner: "user-1", Channel: "email", Target: "ops@example.com"})
+	s.NoError(err)
+	s.NotEmpty(job.Id)
+	s.Equal(entities.JobQueued, job.Status)
+	s.False(job.CreatedAt.IsZero())
+
+	var saved entities.ReportJob
+	s.Require().NoError(json.Unmarshal([]byte(stored), &saved))
+	s.Equal(job.Id, saved.Id)
+	s.Equal(entities.JobQueued, saved.Status)
+}
+
+func (s *ReportJobServiceSuite) TestEnqueueSaveError() {
+	s.redisClient.EXPECT().HSet(s.ctx, "report_jobs", gomock.Any(), gomock.Any()).Return(errors.New("redis connection failed"))
+	s.logger.EXPECT().Error("failed to save report job to redis", gomock.Any()).Times(1)
+
+	_, err := s.jobService.Enqueue(s.ctx, entities.ReportJob{Owner: "user-1"})
+	s.Error(err)
+}
+
+func (s *ReportJobServiceSuite) TestEnqueuePushError() {
+	s.redisClient.EXPECT().HSet(s.ctx, "report_jobs", gomock.Any(), gomock.Any()).Return(nil)
+	s.redisClient.EXPECT().LPush(s.ctx, "report_job_queue", gomock.Any()).Return(errors.New("redis connection failed"))
+	s.redisClient.EXPECT().HDel(s.ctx, "report_jobs", gomock.Any()).Return(nil)
+	s.logger.EXPECT().Error("failed to push report job to redis", gomock.Any()).Times(1)
+
+	_, err := s.jobService.Enqueue(s.ctx, entities.ReportJob{Owner: "user-1"})
+	s.Error(err)
+}
+
+func (s *ReportJobServiceSuite) TestGet() {
+	s.redisClient.EXPECT().HGet(s.ctx, "report_jobs", "job-1").Return(s.storedJob(entities.ReportJob{Id: "job-1", Owner: "user-1", Status: entities.JobRunning}), nil)
+
+	job, err := s.jobService.Get(s.ctx, "user-1", "job-1")
+	s.NoError(err)
+	s.Equal(entities.JobRunning, job.Status)
+}
+
+func (s *ReportJobServiceSuite) TestGetOtherOwner() {
+	s.redisClient.EXPECT().HGet(s.ctx, "report_jobs", "job-1").Return(s.storedJob(entities.ReportJob{Id: "job-1", Owner: "user-2"}), nil)
+
+	_, err := s.jobService.Get(s.ctx, "user-1", "job-1")
+	s.ErrorIs(err, ErrJobNotFound)
+}
+
+func (s *ReportJobServiceSuite) TestGetErrors() {
+	s.redisClient.EXPECT().HGet(s.ctx, "report_jobs", "missing").Return("", nil)
+	_, err := s.jobService.Get(s.ctx, "user-1", "missing")
+	s.ErrorIs(err, ErrJobNotFound)
+
+	s.redisClient.EXPECT().HGet(s.ctx, "report_jobs", "job-1").Return("", errors.New("redis connection failed"))
+	s.logger.EXPECT().Error("failed to get report job from redis", gomock.Any()).Times(1)
+	_, err = s.jobService.Get(s.ctx, "user-1", "job-1")
+	s.Error(err)
+
+	s.redisClient.EXPECT().HGet(s.ctx, "report_jobs", "job-1").Return("{", nil)
+	s.logger.EXPECT().Error("failed to decode report job", gomock.Any()).Times(1)
+	_, err = s.jobService.Get(s.ctx, "user-1", "job-1")
+	s.Error(err)
+}
+
+func (s *ReportJobServiceSuite) TestDequeue() {
+	s.redisClient.EXPECT().BLMove(s.ctx, time.Second, "report_job_queue", "report_job_processing:worker-1").Return("job-1", nil)
+	s.redisClient.EXPECT().HGet(s.ctx, "report_jobs", "job-1").Return(s.storedJob(entities.ReportJob{Id: "job-1", Status: entities.JobQueued}), nil)
+
+	job, err := s.jobService.Dequeue(s.ctx, "worker-1", time.Second)
+	s.NoError(err)
+	s.Require().NotNil(job)
+	s.Equal("job-1", job.Id)
+}
+
+func (s *ReportJobServiceSuite) TestDequeueEmpty() {
+	s.redisClient.EXPECT().BLMove(s.ctx, time.Second, "report_job_queue", "report_job_processing:worker-1").Return("", nil)
+
+	job, err := s.jobService.Dequeue(s.ctx, "worker-1", time.Second)
+	s.NoError(err)
+	s.Nil(job)
+}
+
+func (s *ReportJobServiceSuite) TestDequeueMissingJob() {
+	s.redisClient.EXPECT().BLMove(s.ctx, time.Second, "report_job_queue", "report_job_processing:worker-1").Return("job-1", nil)
+	s.redisClient.EXPECT().HGet(s.ctx, "report_jobs", "job-1").Return("", nil)
+	s.logger.EXPECT().Warn("skipping missing report job", gomock.Any()).Times(1)
+	s.redisClient.EXPECT().LRem(s.ctx, "report_job_processing:worker-1", "job-1").Return(nil)
+
+	job, err := s.jobService.Dequeue(s.ctx, "worker-1", time.Second)
+	s.NoError(err)
+	s.Nil(job)
+}
+
+func (s *ReportJobServiceSuite) TestDequeueRedisError() {
+	s.redisClient.EXPECT().BLMove(s.ctx, time.Second, "report_job_queue", "report_job_processing:worker-1").Return("", errors.New("redis connection failed"))
+	s.logger.EXPECT().Error("failed to pop report job from redis", gomock.Any()).Times(1)
+
+	job, err := s.jobService.Dequeue(s.ctx, "worker-1", time.Second)
+	s.Error(err)
+	s.Nil(job)
+}
+
+func (s *ReportJobServiceSuite) TestAck() {
+	s.redisClient.EXPECT().LRem(s.ctx, "report_job_processing:worker-1", "job-1").Return(nil)
+	s.NoError(s.jobService.Ack(s.ctx, "worker-1", "job-1"))
+
+	s.redisClient.EXPECT().LRem(s.ctx, "report_job_processing:worker-1", "job-1").Return(errors.New("redis connection failed"))
+	s.logger.EXPECT().Error("failed to acknowledge report job in redis", gomock.Any(), gomock.Any()).Times(1)
+	s.Error(s.jobService.Ack(s.ctx, "worker-1", "job-1"))
+}
+
+func (s *ReportJobServiceSuite) TestRegister() {
+	s.redisClient.EXPECT().HSet(s.ctx, "report_job_workers", "worker-1", gomock.Any()).Return(nil)
+	s.NoError(s.jobService.Register(s.ctx, "worker-1"))
+
+	s.redisClient.EXPECT().HSet(s.ctx, "report_job_workers", "worker-1", gomock.Any()).Return(errors.New("redis connection failed"))
+	s.logger.EXPECT().Error("failed to register report job worker in redis", gomock.Any(), gomock.Any()).Times(1)
+	s.Error(s.jobService.Register(s.ctx, "worker-1"))
+}
+
+func (s *ReportJobServiceSuite) TestWorkers() {
+	s.redisClient.EXPECT().HGetAll(s.ctx, "report_job_workers").Return(map[string]string{
+		"worker-2": "2024-01-08T00:00:00Z",
+		"worker-1": "2024-01-08T00:00:00Z",
+	}, nil)
+
+	workers, err := s.jobService.Workers(s.ctx)
+	s.NoError(err)
+	s.Equal([]string{"worker-1", "worker-2"}, workers)
+
+	s.redisClient.EXPECT().HGetAll(s.ctx, "report_job_workers").Return(nil, errors.New("redis connection failed"))
+	s.logger.EXPECT().Error("failed to list report job workers from redis", gomock.Any()).Times(1)
+
+	_, err = s.jobService.Workers(s.ctx)
+	s.Error(err)
+}
+
+func (s *ReportJobServiceSuite) TestRequeue() {
+	gomock.InOrder(
+		s.redisClient.EXPECT().LMove(s.ctx, "report_job_processing:worker-1", "report_job_queue").Return("job-1", nil),
+		s.redisClient.EXPECT().LMove(s.ctx, "report_job_processing:worker-1", "report_job_queue").Return("job-2", nil),
+		s.redisClient.EXPECT().LMove(s.ctx, "report_job_processing:worker-1", "report_job_queue").Return("", nil),
+		s.redisClient.EXPECT().HDel(s.ctx, "report_job_workers", "worker-1").Return(nil),
+	)
+
+	requeued, err := s.jobService.Requeue(s.ctx, "worker-1")
+	s.NoError(err)
+	s.Equal(2, requeued)
+}
+
+func (s *ReportJobServiceSuite) TestRequeueRedisError() {
+	s.redisClient.EXPECT().LMove(s.ctx, "report_job_processing:worker-1", "report_job_queue").Return("job-1", nil)
+	s.redisClient.EXPECT().LMove(s.ctx, "report_job_processing:worker-1", "report_job_queue").Return("", errors.New("redis connection failed"))
+	s.logger.EXPECT().Error("failed to requeue report job in redis", gomock.Any(), gomock.Any()).Times(1)
+
+	requeued, err := s.jobService.Requeue(s.ctx, "worker-1")
+	s.Error(err)
+	s.Equal(1, requeued)
+
+	s.redisClient.EXPECT().LMove(s.ctx, "report_job_processing:worker-1", "report_job_queue").Return("", nil)
+	s.redisClient.EXPECT().HDel(s.ctx, "report_job_workers", "worker-1").Return(errors.New("redis connection failed"))
+	s.logger.EXPECT().Error("failed to unregister report job worker in redis", gomock.Any(), gomock.Any()).Times(1)
+
+	_, err = s.jobService.Requeue(s.ctx, "worker-1")
+	s.Error(err)
+}
+
+func (s *ReportJobServiceSuite) TestSaveFinishedPrunes() {
+	now := time.Now()
+	s.redisClient.EXPECT().HSet(s.ctx, "report_jobs", "job-1", gomock.Any()).Return(nil)
+	s.redisClient.EXPECT().ZAdd(s.ctx, "report_jobs_by_finish", float64(now.UnixMilli()), "job-1").Return(nil)
+	s.redisClient.EXPECT().
+		ZRangeByScore(s.ctx, "report_jobs_by_finish", "-inf", gomock.Any()).
+		DoAndReturn(func(ctx context.Context, key string, min string, max string) ([]string, error) {
+			cutoff, err := strconv.ParseInt(strings.TrimPrefix(max, "("), 10, 64)
+			s.Require().NoError(err)
+			s.True(strings.HasPrefix(max, "("))
+			s.InDelta(now.Add(-24*time.Hour).UnixMilli(), cutoff, float64(time.Minute.Milliseconds()))
+			return []string{"job-old"}, nil
+		})
+	s.redisClient.EXPECT().HDel(s.ctx, "report_jobs", "job-old").Return(nil)
+	s.redisClient.EXPECT().ZRem(s.ctx, "report_jobs_by_finish", "job-old").Return(nil)
+
+	s.NoError(s.jobService.Save(s.ctx, entities.ReportJob{Id: "job-1", Status: entities.JobSucceeded, FinishedAt: now}))
+}
+
+func (s *ReportJobServiceSuite) TestSaveFinishedIndexError() {
+	s.redisClient.EXPECT().HSet(s.ctx, "report_jobs", "job-1", gomock.Any()).Return(nil)
+	s.redisClient.EXPECT().ZAdd(s.ctx, "report_jobs_by_finish", gomock.Any(), "job-1").Return(errors.New("redis connection failed"))
+	s.logger.EXPECT().Error("failed to index report job in redis", gomock.Any()).Times(1)
+
+	s.Error(s.jobService.Save(s.ctx, entities.ReportJob{Id: "job-1", Status: entities.JobFailed, FinishedAt: time.Now()}))
+}
+
+func (s *ReportJobServiceSuite) TestSavePruneError() {
+	s.redisClient.EXPECT().HSet(s.ctx, "report_jobs", "job-1", gomock.Any()).Return(nil)
+	s.redisClient.EXPECT().ZAdd(s.ctx, "report_jobs_by_finish", gomock.Any(), "job-1").Return(nil)
+	s.redisClient.EXPECT().ZRangeByScore(s.ctx, "report_jobs_by_finish", "-inf", gomock.Any()).Return([]string{"job-old"}, nil)
+	s.redisClient.EXPECT().HDel(s.ctx, "report_jobs", "job-old").Return(errors.New("redis connection failed"))
+	s.logger.EXPECT().Warn("failed to prune report job", gomock.Any(), gomock.Any()).Times(1)
+
+	s.NoError(s.jobService.Save(s.ctx, entities.ReportJob{Id: "job-1", Status: entities.JobSucceeded, FinishedAt: time.Now()}))
+}
+
+func (s *ReportJobServiceSuite) TestSaveRunningSkipsPrune() {
+	s.redisClient.EXPECT().HSet(s.ctx, "report_jobs", "job-1", gomock.Any()).Return(nil)
+
+	s.NoError(s.jobService.Save(s.ctx, entities.ReportJob{Id: "job-1", Status: entities.JobRunning}))
+}
@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vnFuhung2903/vcs-report-service/dto"
+	"github.com/vnFuhung2903/vcs-report-service/entities"
+	"github.com/vnFuhung2903/vcs-report-service/interfaces"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/env"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	runKey          = "report_runs"
+	runIndexKey     = "report_runs_by_start"
+	runMarkerKey    = "report_schedule_markers"
+	defaultRunLimit = 50
+	runPageSize     = 100
+)
+
+var ErrRunNotFound = errors.New("report run not found")
+
+type IReportRunService interface {
+	Start(ctx context.Context, run entities.ReportRun, lease *interfaces.Lease) (entities.ReportRun, error)
+	Finish(ctx context.Context, run entities.ReportRun, report *dto.ReportResponse, lease *interfaces.Lease) (entities.ReportRun, error)
+	Get(ctx context.Context, owners []string, id string) (entities.ReportRun, error)
+	List(ctx context.Context, owners []string, req dto.RunListRequest) ([]entities.ReportRun, error)
+	LastReported(ctx context.Context, schedule string) (time.Time, error)
+	MarkReported(ctx context.Context, schedule string, windowEnd time.Time, lease *interfaces.Lease) error
+}
+
+type reportRunService struct {
+	redisClient interfaces.IRedisClient
+	logger      logger.ILogger
+	retention   time.Duration
+}
+
+func NewReportRunService(redisClient interfaces.IRedisClient, logger logger.ILogger, reportEnv env.ReportEnv) IReportRunService {
+	return &reportRunService{
+		redisClient: redisClient,
+		logger:      logger,
+		retention:   reportEnv.RunRetention,
+	}
+}
+
+// Start records run as running. The returned run always carries its id, even
+// when persisting it fails, so that Finish can still store the outcome. Runs
+// guarded by a lease pass it so that a holder that lost it stops writing;
+// others pass nil.
+func (s *reportRunService) Start(ctx context.Context, run entities.ReportRun, lease *interfaces.Lease) (entities.ReportRun, error) {
+	run.Id = uuid.NewString()
+	run.Status = entities.RunRunning
+	run.StartedAt = time.Now()
+
+	if err := s.save(ctx, run, lease); err != nil {
+		return run, err
+	}
+	return run, nil
+}
+
+// Finish stores the outcome of run. A nil report means the report could not be
+// generated; otherwise the run fails only when no recipient was reached.
+func (s *reportRunService) Finish(ctx context.Context, run entities.ReportRun, report *dto.ReportResponse, lease *interfaces.Lease) (entities.ReportRun, error) {
+	run.FinishedAt = time.Now()
+	run.DurationMs = run.FinishedAt.Sub(run.StartedAt).Milliseconds()
+
+	if report != nil {
+		run.Stats = &entities.ReportRunStats{
+			ContainerCount:    report.ContainerCount,
+			ContainerOnCount:  report.ContainerOnCount,
+			ContainerOffCount: report.ContainerOffCount,
+			TotalUptime:       report.TotalUptime,
+			Availability:      report.Availability,
+			SLABreachedCount:  report.SLABreachedCount,
+		}
+	}
+
+	switch {
+	case report == nil, len(run.FailedRecipients) > 0 && len(run.FailedRecipients) >= len(run.Recipients):
+		run.Status = entities.RunFailed
+	case len(run.FailedRecipients) > 0:
+		run.Status = entities.RunPartial
+	default:
+		run.Status = entities.RunSucceeded
+	}
+
+	if err := s.save(ctx, run, lease); err != nil {
+		return run, err
+	}
+
+	s.prune(ctx)
+	return run, nil
+}
+
+// Get returns the run stored under id when it belongs to one of owners. Runs
+// carry their recipients, webhook URLs included, so other callers get
+// ErrRunNotFound.
+func (s *reportRunService) Get(ctx context.Context, owners []string, id string) (entities.ReportRun, error) {
+	val, err := s.redisClient.HGet(ctx, runKey, id)
+	if err != nil {
+		s.logger.Error("failed to get report run from redis", zap.Error(err))
+		return entities.ReportRun{}, err
+	}
+	if val == "" {
+		return entities.ReportRun{}, ErrRunNotFound
+	}
+
+	var run entities.ReportRun
+	if err := json.Unmarshal([]byte(val), &run); err != nil {
+		s.logger.Error("failed to decode report run", zap.Error(err))
+		return entities.ReportRun{}, err
+	}
+	if !slices.Contains(owners, run.Owner) {
+		return entities.ReportRun{}, ErrRunNotFound
+	}
+	return run, nil
+}
+
+// List returns the most recent runs of owners first. It walks the start time
+// index page by page and stops as soon as limit runs matched.
+func (s *reportRunService) List(ctx context.Context, owners []string, req dto.RunListRequest) ([]entities.ReportRun, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultRunLimit
+	}
+
+	filtered := make([]entities.ReportRun, 0, limit)
+	for start := int64(0); ; start += runPageSize {
+		ids, err := s.redisClient.ZRevRange(ctx, runIndexKey, start, start+runPageSize-1)
+		if err != nil {
+			s.logger.Error("failed to list report runs from redis", zap.Error(err))
+			return nil, err
+		}
+		if len(ids) == 0 {
+			return filtered, nil
+		}
+
+		values, err := s.redisClient.HMGet(ctx, runKey, ids...)
+		if err != nil {
+			s.logger.Error("failed to list report runs from redis", zap.Error(err))
+			return nil, err
+		}
+
+		for i, val := range values {
+			if val == "" {
+				continue
+			}
+			var run entities.ReportRun
+			if err := json.Unmarshal([]byte(val), &run); err != nil {
+				s.logger.Warn("skipping undecodable report run", zap.String("id", ids[i]), zap.Error(err))
+				continue
+			}
+			if !slices.Contains(owners, run.Owner) {
+				continue
+			}
+			if req.Status != "" && string(run.Status) != req.Status {
+				continue
+			}
+			if req.Trigger != "" && string(run.Trigger) != req.Trigger {
+				continue
+			}
+			if req.Schedule != "" && run.Schedule != req.Schedule {
+				continue
+			}
+			filtered = append(filtered, run)
+			if len(filtered) == limit {
+				return filtered, nil
+			}
+		}
+		if len(ids) < runPageSize {
+			return filtered, nil
+		}
+	}
+}
+
+// LastReported returns the end of the latest window successfully reported for
+// schedule, or the zero time when the schedule has never been reported.
+func (s *reportRunService) LastReported(ctx context.Context, schedule string) (time.Time, error) {
+	val, err := s.redisClient.HGet(ctx, runMarkerKey, schedule)
+	if err != nil {
+		s.logger.Error("failed to get report marker from redis", zap.Error(err))
+		return time.Time{}, err
+	}
+	if val == "" {
+		return time.Time{}, nil
+	}
+
+	windowEnd, err := time.Parse(time.RFC3339, val)
+	if err != nil {
+		s.logger.Error("failed to decode report marker", zap.String("schedule", schedule), zap.Error(err))
+		return time.Time{}, err
+	}
+	return windowEnd, nil
+}
+
+// MarkReported advances the marker of schedule to windowEnd. The comparison
+// and the write happen in one step in Redis, so older windows, for instance
+// ones backfilled after a newer run or sent under the lock of another window,
+// never move it backwards. The marker is only written while lease, when given,
+// is still current.
+func (s *reportRunService) MarkReported(ctx context.Context, schedule string, windowEnd time.Time, lease *interfaces.Lease) error {
+	if err := s.redisClient.HSetMax(ctx, lease, runMarkerKey, schedule, windowEnd.UTC().Format(time.RFC3339)); err != nil {
+		s.logger.Error("failed to save report marker to redis", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// prune drops the runs that started before the retention period, reading
+// their ids from the start time index rather than every stored run.
+func (s *reportRunService) prune(ctx context.Context) {
+	if s.retention <= 0 {
+		return
+	}
+
+	cutoff := time.Now().Add(-s.retention).UnixMilli()
+	ids, err := s.redisClient.ZRangeByScore(ctx, runIndexKey, "-inf", fmt.Sprintf("(%d", cutoff))
+	if err != nil {
+		s.logger.Warn("failed to list expired report runs", zap.Error(err))
+		return
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	for _, id := range ids {
+		if err := s.redisClient.HDel(ctx, runKey, id); err != nil {
+			s.logger.Warn("failed to prune report run", zap.String("id", id), zap.Error(err))
+			return
+		}
+	}
+	if err := s.redisClient.ZRem(ctx, runIndexKey, ids...); err != nil {
+		s.logger.Warn("failed to prune report run index", zap.Error(err))
+	}
+}
+
+func (s *reportRunService) save(ctx context.Context, run entities.ReportRun, lease *interfaces.Lease) error {
+	val, err := json.Marshal(run)
+	if err != nil {
+		s.logger.Error("failed to encode report run", zap.Error(err))
+		return err
+	}
+
+	if err := s.redisClient.HSetFenced(ctx, lease, runKey, run.Id, string(val)); err != nil {
+		s.logger.Error("failed to save report run to redis", zap.Error(err))
+		return err
+	}
+	if err := s.redisClient.ZAdd(ctx, runIndexKey, float64(run.StartedAt.UnixMilli()), run.Id); err != nil {
+		s.logger.Error("failed to index report run in redis", zap.Error(err))
+		return err
+	}
+	return nil
+}
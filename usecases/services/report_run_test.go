@@ -0,0 +1,403 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/vnFuhung2903/vcs-report-service/dto"
+	"github.com/vnFuhung2903/vcs-report-service/entities"
+	clients "github.com/vnFuhung2903/vcs-report-service/interfaces"
+	"github.com/vnFuhung2903/vcs-report-service/mocks/interfaces"
+	"github.com/vnFuhung2903/vcs-report-service/mocks/logger"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/env"
+)
+
+type ReportRunServiceSuite struct {
+	suite.Suite
+	ctrl        *gomock.Controller
+	redisClient *interfaces.MockIRedisClient
+	logger      *logger.MockILogger
+	runService  IReportRunService
+	ctx         context.Context
+}
+
+func (s *ReportRunServiceSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.redisClient = interfaces.NewMockIRedisClient(s.ctrl)
+	s.logger = logger.NewMockILogger(s.ctrl)
+	s.runService = NewReportRunService(s.redisClient, s.logger, env.ReportEnv{RunRetention: 24 * time.Hour})
+	s.ctx = context.Background()
+}
+
+func (s *ReportRunServiceSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func TestReportRunServiceSuite(t *testing.T) {
+	suite.Run(t, new(ReportRunServiceSuite))
+}
+
+func (s *ReportRunServiceSuite) storedRun(run entities.ReportRun) string {
+	val, _ := json.Marshal(run)
+	return string(val)
+}
+
+func (s *ReportRunServiceSuite) TestStart() {
+	lease := &clients.Lease{Key: "report_lock:daily:1704672000", Token: 7}
+	var stored string
+	s.redisClient.EXPECT().
+		HSetFenced(s.ctx, lease, "report_runs", gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, lease *clients.Lease, key string, field string, value string) error {
+			stored = value
+			return nil
+		})
+	s.redisClient.EXPECT().ZAdd(s.ctx, "report_runs_by_start", gomock.Any(), gomock.Any()).Return(nil)
+
+	run, err := s.runService.Start(s.ctx, entities.ReportRun{
+		Trigger:    entities.RunTriggerSchedule,
+		Schedule:   "daily",
+		Channel:    "email",
+		Recipients: []string{"ops@example.com"},
+	}, lease)
+	s.NoError(err)
+	s.NotEmpty(run.Id)
+	s.Equal(entities.RunRunning, run.Status)
+	s.False(run.StartedAt.IsZero())
+
+	var saved entities.ReportRun
+	s.Require().NoError(json.Unmarshal([]byte(stored), &saved))
+	s.Equal(run.Id, saved.Id)
+	s.Equal("daily", saved.Schedule)
+}
+
+func (s *ReportRunServiceSuite) TestStartRedisError() {
+	s.redisClient.EXPECT().HSetFenced(s.ctx, nil, "report_runs", gomock.Any(), gomock.Any()).Return(errors.New("redis connection failed"))
+	s.logger.EXPECT().Error("failed to save report run to redis", gomock.Any()).Times(1)
+
+	run, err := s.runService.Start(s.ctx, entities.ReportRun{Trigger: entities.RunTriggerAPI}, nil)
+	s.Error(err)
+	s.NotEmpty(run.Id)
+}
+
+func (s *ReportRunServiceSuite) TestFinish() {
+	report := &dto.ReportResponse{ContainerCount: 2, ContainerOnCount: 1, ContainerOffCount: 1, TotalUptime: 12.5, Availability: 50, SLABreachedCount: 1}
+
+	tests := []struct {
+		name   string
+		failed []string
+		report *dto.ReportResponse
+		status entities.RunStatus
+	}{
+		{name: "succeeded", report: report, status: entities.RunSucceeded},
+		{name: "partial", failed: []string{"ops@example.com"}, report: report, status: entities.RunPartial},
+		{name: "all recipients failed", failed: []string{"ops@example.com", "dev@example.com"}, report: report, status: entities.RunFailed},
+		{name: "generation failed", status: entities.RunFailed},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.redisClient.EXPECT().HSetFenced(s.ctx, nil, "report_runs", "run-1", gomock.Any()).Return(nil)
+			s.redisClient.EXPECT().ZAdd(s.ctx, "report_runs_by_start", gomock.Any(), "run-1").Return(nil)
+			s.redisClient.EXPECT().ZRangeByScore(s.ctx, "report_runs_by_start", "-inf", gomock.Any()).Return(nil, nil)
+
+			run, err := s.runService.Finish(s.ctx, entities.ReportRun{
+				Id:               "run-1",
+				Recipients:       []string{"ops@example.com", "dev@example.com"},
+				FailedRecipients: tt.failed,
+				StartedAt:        time.Now().Add(-time.Second),
+			}, tt.report, nil)
+			s.NoError(err)
+			s.Equal(tt.status, run.Status)
+			s.GreaterOrEqual(run.DurationMs, int64(1000))
+			if tt.report != nil {
+				s.Equal(&entities.ReportRunStats{ContainerCount: 2, ContainerOnCount: 1, ContainerOffCount: 1, TotalUptime: 12.5, Availability: 50, SLABreachedCount: 1}, run.Stats)
+			} else {
+				s.Nil(run.Stats)
+			}
+		})
+	}
+}
+
+func (s *ReportRunServiceSuite) TestFinishRedisError() {
+	s.redisClient.EXPECT().HSetFenced(s.ctx, nil, "report_runs", "run-1", gomock.Any()).Return(errors.New("redis connection failed"))
+	s.logger.EXPECT().Error("failed to save report run to redis", gomock.Any()).Times(1)
+
+	_, err := s.runService.Finish(s.ctx, entities.ReportRun{Id: "run-1"}, nil, nil)
+	s.Error(err)
+}
+
+func (s *ReportRunServiceSuite) TestFinishIndexError() {
+	s.redisClient.EXPECT().HSetFenced(s.ctx, nil, "report_runs", "run-1", gomock.Any()).Return(nil)
+	s.redisClient.EXPECT().ZAdd(s.ctx, "report_runs_by_start", gomock.Any(), "run-1").Return(errors.New("redis connection failed"))
+	s.logger.EXPECT().Error("failed to index report run in redis", gomock.Any()).Times(1)
+
+	_, err := s.runService.Finish(s.ctx, entities.ReportRun{Id: "run-1"}, nil, nil)
+	s.Error(err)
+}
+
+func (s *ReportRunServiceSuite) TestFinishPrunesExpiredRuns() {
+	startedAt := time.Now().Add(-time.Hour)
+	s.redisClient.EXPECT().HSetFenced(s.ctx, nil, "report_runs", "run-1", gomock.Any()).Return(nil)
+	s.redisClient.EXPECT().ZAdd(s.ctx, "report_runs_by_start", float64(startedAt.UnixMilli()), "run-1").Return(nil)
+	s.redisClient.EXPECT().
+		ZRangeByScore(s.ctx, "report_runs_by_start", "-inf", gomock.Any()).
+		DoAndReturn(func(ctx context.Context, key string, min string, max string) ([]string, error) {
+			cutoff, err := strconv.ParseInt(strings.TrimPrefix(max, "("), 10, 64)
+			s.Require().NoError(err)
+			s.True(strings.HasPrefix(max, "("))
+			s.InDelta(time.Now().Add(-24*time.Hour).UnixMilli(), cutoff, float64(time.Minute.Milliseconds()))
+			return []string{"run-old", "run-older"}, nil
+		})
+	s.redisClient.EXPECT().HDel(s.ctx, "report_runs", "run-old").Return(nil)
+	s.redisClient.EXPECT().HDel(s.ctx, "report_runs", "run-older").Return(nil)
+	s.redisClient.EXPECT().ZRem(s.ctx, "report_runs_by_start", "run-old", "run-older").Return(nil)
+
+	_, err := s.runService.Finish(s.ctx, entities.ReportRun{Id: "run-1", StartedAt: startedAt}, &dto.ReportResponse{}, nil)
+	s.NoError(err)
+}
+
+func (s *ReportRunServiceSuite) TestFinishPruneError() {
+	s.redisClient.EXPECT().HSetFenced(s.ctx, nil, "report_runs", "run-1", gomock.Any()).Return(nil)
+	s.redisClient.EXPECT().ZAdd(s.ctx, "report_runs_by_start", gomock.Any(), "run-1").Return(nil)
+	s.redisClient.EXPECT().ZRangeByScore(s.ctx, "report_runs_by_start", "-inf", gomock.Any()).Return([]string{"run-old"}, nil)
+	s.redisClient.EXPECT().HDel(s.ctx, "report_runs", "run-old").Return(errors.New("redis connection failed"))
+	s.logger.EXPECT().Warn("failed to prune report run", gomock.Any(), gomock.Any()).Times(1)
+
+	_, err := s.runService.Finish(s.ctx, entities.ReportRun{Id: "run-1"}, &dto.ReportResponse{}, nil)
+	s.NoError(err)
+}
+
+func (s *ReportRunServiceSuite) TestFinishWithoutRetention() {
+	runService := NewReportRunService(s.redisClient, s.logger, env.ReportEnv{})
+	s.redisClient.EXPECT().HSetFenced(s.ctx, nil, "report_runs", "run-1", gomock.Any()).Return(nil)
+	s.redisClient.EXPECT().ZAdd(s.ctx, "report_runs_by_start", gomock.Any(), "run-1").Return(nil)
+
+	_, err := runService.Finish(s.ctx, entities.ReportRun{Id: "run-1"}, &dto.ReportResponse{}, nil)
+	s.NoError(err)
+}
+
+func (s *ReportRunServiceSuite) TestGet() {
+	s.redisClient.EXPECT().HGet(s.ctx, "report_runs", "run-1").Return(s.storedRun(entities.ReportRun{Id: "run-1", Owner: "user-1", Status: entities.RunSucceeded}), nil)
+
+	run, err := s.runService.Get(s.ctx, []string{"user-1"}, "run-1")
+	s.NoError(err)
+	s.Equal("run-1", run.Id)
+	s.Equal(entities.RunSucceeded, run.Status)
+}
+
+func (s *ReportRunServiceSuite) TestGetOtherOwner() {
+	s.redisClient.EXPECT().HGet(s.ctx, "report_runs", "run-1").Return(s.storedRun(entities.ReportRun{Id: "run-1", Owner: "user-2"}), nil).Times(2)
+
+	_, err := s.runService.Get(s.ctx, []string{"user-1"}, "run-1")
+	s.ErrorIs(err, ErrRunNotFound)
+
+	_, err = s.runService.Get(s.ctx, []string{entities.RunOwnerSystem}, "run-1")
+	s.ErrorIs(err, ErrRunNotFound)
+}
+
+func (s *ReportRunServiceSuite) TestGetSystemOwner() {
+	s.redisClient.EXPECT().HGet(s.ctx, "report_runs", "run-1").Return(s.storedRun(entities.ReportRun{Id: "run-1", Owner: entities.RunOwnerSystem, Schedule: "daily"}), nil).Times(2)
+
+	_, err := s.runService.Get(s.ctx, []string{"user-1"}, "run-1")
+	s.ErrorIs(err, ErrRunNotFound)
+
+	run, err := s.runService.Get(s.ctx, []string{"user-1", entities.RunOwnerSystem}, "run-1")
+	s.NoError(err)
+	s.Equal("daily", run.Schedule)
+}
+
+func (s *ReportRunServiceSuite) TestListSystemOwner() {
+	ids := []string{"run-2", "run-1"}
+	s.redisClient.EXPECT().ZRevRange(s.ctx, "report_runs_by_start", int64(0), int64(99)).Return(ids, nil)
+	s.redisClient.EXPECT().HMGet(s.ctx, "report_runs", ids).Return([]string{
+		s.storedRun(entities.ReportRun{Id: "run-2", Owner: entities.RunOwnerSystem, Schedule: "daily"}),
+		s.storedRun(entities.ReportRun{Id: "run-1", Owner: "user-2"}),
+	}, nil)
+
+	runs, err := s.runService.List(s.ctx, []string{"user-1", entities.RunOwnerSystem}, dto.RunListRequest{})
+	s.NoError(err)
+	s.Len(runs, 1)
+	s.Equal("run-2", runs[0].Id)
+}
+
+func (s *ReportRunServiceSuite) TestGetNotFound() {
+	s.redisClient.EXPECT().HGet(s.ctx, "report_runs", "missing").Return("", nil)
+
+	_, err := s.runService.Get(s.ctx, []string{"user-1"}, "missing")
+	s.ErrorIs(err, ErrRunNotFound)
+}
+
+func (s *ReportRunServiceSuite) TestGetRedisError() {
+	s.redisClient.EXPECT().HGet(s.ctx, "report_runs", "run-1").Return("", errors.New("redis connection failed"))
+	s.logger.EXPECT().Error("failed to get report run from redis", gomock.Any()).Times(1)
+
+	_, err := s.runService.Get(s.ctx, []string{"user-1"}, "run-1")
+	s.Error(err)
+}
+
+func (s *ReportRunServiceSuite) TestGetDecodeError() {
+	s.redisClient.EXPECT().HGet(s.ctx, "report_runs", "run-1").Return("{", nil)
+	s.logger.EXPECT().Error("failed to decode report run", gomock.Any()).Times(1)
+
+	_, err := s.runService.Get(s.ctx, []string{"user-1"}, "run-1")
+	s.Error(err)
+}
+
+func (s *ReportRunServiceSuite) TestList() {
+	base := time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
+	ids := []string{"run-5", "run-4", "run-3", "run-pruned", "run-2", "run-1"}
+	s.redisClient.EXPECT().ZRevRange(s.ctx, "report_runs_by_start", int64(0), int64(99)).Return(ids, nil).Times(4)
+	s.redisClient.EXPECT().HMGet(s.ctx, "report_runs", ids).Return([]string{
+		s.storedRun(entities.ReportRun{Id: "run-5", Owner: "user-2", Status: entities.RunFailed, StartedAt: base.Add(4 * time.Hour)}),
+		"{",
+		s.storedRun(entities.ReportRun{Id: "run-3", Owner: "user-1", Trigger: entities.RunTriggerSchedule, Schedule: "daily", Status: entities.RunFailed, StartedAt: base.Add(2 * time.Hour)}),
+		"",
+		s.storedRun(entities.ReportRun{Id: "run-2", Owner: "user-1", Trigger: entities.RunTriggerAPI, Status: entities.RunFailed, StartedAt: base.Add(time.Hour)}),
+		s.storedRun(entities.ReportRun{Id: "run-1", Owner: "user-1", Trigger: entities.RunTriggerSchedule, Schedule: "daily", Status: entities.RunSucceeded, StartedAt: base}),
+	}, nil).Times(4)
+	s.logger.EXPECT().Warn("skipping undecodable report run", gomock.Any(), gomock.Any()).Times(4)
+
+	runs, err := s.runService.List(s.ctx, []string{"user-1"}, dto.RunListRequest{})
+	s.NoError(err)
+	s.Len(runs, 3)
+	s.Equal("run-3", runs[0].Id)
+	s.Equal("run-1", runs[2].Id)
+
+	runs, err = s.runService.List(s.ctx, []string{"user-1"}, dto.RunListRequest{Status: "failed", Trigger: "schedule"})
+	s.NoError(err)
+	s.Len(runs, 1)
+	s.Equal("run-3", runs[0].Id)
+
+	runs, err = s.runService.List(s.ctx, []string{"user-1"}, dto.RunListRequest{Schedule: "daily", Limit: 1})
+	s.NoError(err)
+	s.Len(runs, 1)
+	s.Equal("run-3", runs[0].Id)
+
+	runs, err = s.runService.List(s.ctx, []string{"user-1"}, dto.RunListRequest{Schedule: "weekly"})
+	s.NoError(err)
+	s.Empty(runs)
+}
+
+func (s *ReportRunServiceSuite) TestListPages() {
+	firstPage := make([]string, 100)
+	firstValues := make([]string, 100)
+	for i := range firstPage {
+		firstPage[i] = fmt.Sprintf("other-%d", i)
+		firstValues[i] = s.storedRun(entities.ReportRun{Id: firstPage[i], Owner: "user-2"})
+	}
+	gomock.InOrder(
+		s.redisClient.EXPECT().ZRevRange(s.ctx, "report_runs_by_start", int64(0), int64(99)).Return(firstPage, nil),
+		s.redisClient.EXPECT().HMGet(s.ctx, "report_runs", firstPage).Return(firstValues, nil),
+		s.redisClient.EXPECT().ZRevRange(s.ctx, "report_runs_by_start", int64(100), int64(199)).Return([]string{"run-1"}, nil),
+		s.redisClient.EXPECT().HMGet(s.ctx, "report_runs", []string{"run-1"}).Return([]string{s.storedRun(entities.ReportRun{Id: "run-1", Owner: "user-1"})}, nil),
+	)
+
+	runs, err := s.runService.List(s.ctx, []string{"user-1"}, dto.RunListRequest{})
+	s.NoError(err)
+	s.Len(runs, 1)
+	s.Equal("run-1", runs[0].Id)
+}
+
+func (s *ReportRunServiceSuite) TestListRedisError() {
+	s.redisClient.EXPECT().ZRevRange(s.ctx, "report_runs_by_start", int64(0), int64(99)).Return(nil, errors.New("redis connection failed"))
+	s.logger.EXPECT().Error("failed to list report runs from redis", gomock.Any()).Times(1)
+
+	runs, err := s.runService.List(s.ctx, []string{"user-1"}, dto.RunListRequest{})
+	s.Error(err)
+	s.Nil(runs)
+
+	s.redisClient.EXPECT().ZRevRange(s.ctx, "report_runs_by_start", int64(0), int64(99)).Return([]string{"run-1"}, nil)
+	s.redisClient.EXPECT().HMGet(s.ctx, "report_runs", []string{"run-1"}).Return(nil, errors.New("redis connection failed"))
+	s.logger.EXPECT().Error("failed to list report runs from redis", gomock.Any()).Times(1)
+
+	runs, err = s.runService.List(s.ctx, []string{"user-1"}, dto.RunListRequest{})
+	s.Error(err)
+	s.Nil(runs)
+}
+
+func (s *ReportRunServiceSuite) TestLastReported() {
+	s.redisClient.EXPECT().HGet(s.ctx, "report_schedule_markers", "daily").Return("2024-01-08T00:00:00Z", nil)
+
+	windowEnd, err := s.runService.LastReported(s.ctx, "daily")
+	s.NoError(err)
+	s.Equal(time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), windowEnd)
+}
+
+func (s *ReportRunServiceSuite) TestLastReportedNever() {
+	s.redisClient.EXPECT().HGet(s.ctx, "report_schedule_markers", "daily").Return("", nil)
+
+	windowEnd, err := s.runService.LastReported(s.ctx, "daily")
+	s.NoError(err)
+	s.True(windowEnd.IsZero())
+}
+
+func (s *ReportRunServiceSuite) TestLastReportedErrors() {
+	s.redisClient.EXPECT().HGet(s.ctx, "report_schedule_markers", "daily").Return("", errors.New("redis connection failed"))
+	s.logger.EXPECT().Error("failed to get report marker from redis", gomock.Any()).Times(1)
+
+	_, err := s.runService.LastReported(s.ctx, "daily")
+	s.Error(err)
+
+	s.redisClient.EXPECT().HGet(s.ctx, "report_schedule_markers", "daily").Return("yesterday", nil)
+	s.logger.EXPECT().Error("failed to decode report marker", gomock.Any(), gomock.Any()).Times(1)
+
+	_, err = s.runService.LastReported(s.ctx, "daily")
+	s.Error(err)
+}
+
+func (s *ReportRunServiceSuite) TestMarkReported() {
+	location := time.FixedZone("ICT", 7*3600)
+	s.redisClient.EXPECT().HSetMax(s.ctx, nil, "report_schedule_markers", "daily", "2024-01-07T17:00:00Z").Return(nil)
+
+	s.NoError(s.runService.MarkReported(s.ctx, "daily", time.Date(2024, 1, 8, 0, 0, 0, 0, location), nil))
+}
+
+func (s *ReportRunServiceSuite) TestMarkReportedKeepsNewerMarker() {
+	miniRedis, err := miniredis.Run()
+	s.Require().NoError(err)
+	defer miniRedis.Close()
+	client := redis.NewClient(&redis.Options{Addr: miniRedis.Addr()})
+	defer client.Close()
+
+	// Each window is sent under its own lock, so the fence alone cannot order
+	// the marker writes of two windows.
+	lock := clients.NewRedisLock(client)
+	older, err := lock.Acquire(s.ctx, "report_lock:daily:1704585600", time.Minute)
+	s.Require().NoError(err)
+	newer, err := lock.Acquire(s.ctx, "report_lock:daily:1704672000", time.Minute)
+	s.Require().NoError(err)
+
+	runService := NewReportRunService(clients.NewRedisClient(client), s.logger, env.ReportEnv{})
+	s.NoError(runService.MarkReported(s.ctx, "daily", time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), newer))
+	s.NoError(runService.MarkReported(s.ctx, "daily", time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC), older))
+
+	last, err := runService.LastReported(s.ctx, "daily")
+	s.NoError(err)
+	s.Equal(time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), last.UTC())
+}
+
+func (s *ReportRunServiceSuite) TestMarkReportedLeaseLost() {
+	lease := &clients.Lease{Key: "report_lock:daily:1704672000", Token: 7}
+	s.redisClient.EXPECT().HSetMax(s.ctx, lease, "report_schedule_markers", "daily", "2024-01-08T00:00:00Z").Return(clients.ErrLeaseLost)
+	s.logger.EXPECT().Error("failed to save report marker to redis", gomock.Any()).Times(1)
+
+	s.ErrorIs(s.runService.MarkReported(s.ctx, "daily", time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), lease), clients.ErrLeaseLost)
+}
+
+func (s *ReportRunServiceSuite) TestMarkReportedRedisError() {
+	s.redisClient.EXPECT().HSetMax(s.ctx, nil, "report_schedule_markers", "daily", "2024-01-08T00:00:00Z").Return(errors.New("redis connection failed"))
+	s.logger.EXPECT().Error("failed to save report marker to redis", gomock.Any()).Times(1)
+
+	s.Error(s.runService.MarkReported(s.ctx, "daily", time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), nil))
+}
@@ -1,23 +1,42 @@
 package services
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/elastic/go-elasticsearch/esapi"
+	"github.com/elastic/go-elasticsearch/v8"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/vnFuhung2903/vcs-report-service/dto"
 	"github.com/vnFuhung2903/vcs-report-service/entities"
+	clients "github.com/vnFuhung2903/vcs-report-service/interfaces"
 	"github.com/vnFuhung2903/vcs-report-service/mocks/interfaces"
 	"github.com/vnFuhung2903/vcs-report-service/mocks/logger"
 	"github.com/vnFuhung2903/vcs-report-service/pkg/env"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/retry"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/templates"
+	"gopkg.in/gomail.v2"
 )
 
 type ReportServiceSuite struct {
@@ -25,10 +44,13 @@ type ReportServiceSuite struct {
 	ctrl          *gomock.Controller
 	esClient      *interfaces.MockIElasticsearchClient
 	redisClient   *interfaces.MockIRedisClient
+	mailDialer    *interfaces.MockIMailDialer
 	reportService IReportService
 	logger        *logger.MockILogger
 	ctx           context.Context
 	sampleReport  *dto.ReportResponse
+	templateDir   string
+	templates     templates.ITemplateRegistry
 }
 
 type MockElasticsearchResponse struct {
@@ -43,16 +65,188 @@ func NewMockElasticsearchResponse(body string, statusCode int) *esapi.Response {
 	}
 }
 
+type fakeElasticsearchServer struct {
+	*httptest.Server
+	docs          map[string][]dto.EsStatus
+	msearchCalls  atomic.Int32
+	openPitCalls  atomic.Int32
+	closePitCalls atomic.Int32
+}
+
+func newFakeElasticsearchServer(docs map[string][]dto.EsStatus) *fakeElasticsearchServer {
+	f := &fakeElasticsearchServer{docs: docs}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.handle))
+	return f
+}
+
+func (f *fakeElasticsearchServer) handle(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Elastic-Product", "Elasticsearch")
+	w.Header().Set("Content-Type", "application/json")
+
+	switch {
+	case r.URL.Path == "/sms_container/_pit":
+		f.openPitCalls.Add(1)
+		fmt.Fprint(w, `{"id":"fake-pit"}`)
+	case r.URL.Path == "/_pit" && r.Method == http.MethodDelete:
+		f.closePitCalls.Add(1)
+		fmt.Fprint(w, `{"succeeded":true,"num_freed":1}`)
+	case r.URL.Path == "/_msearch":
+		f.msearchCalls.Add(1)
+		f.msearch(w, r)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+type fakeClause struct {
+	Term  map[string]string   `json:"term"`
+	Terms map[string][]string `json:"terms"`
+	Range map[string]struct {
+		Gte time.Time `json:"gte"`
+		Lt  time.Time `json:"lt"`
+	} `json:"range"`
+}
+
+type fakeQuery struct {
+	Query struct {
+		Bool struct {
+			Must   []fakeClause `json:"must"`
+			Filter []fakeClause `json:"filter"`
+		} `json:"bool"`
+	} `json:"query"`
+	Size        int                        `json:"size"`
+	SearchAfter []int64                    `json:"search_after"`
+	Aggs        map[string]json.RawMessage `json:"aggs"`
+}
+
+func (f *fakeElasticsearchServer) match(query fakeQuery) map[string][]dto.EsStatus {
+	var containerIds []string
+	var gte, lt time.Time
+	for _, clause := range append(query.Query.Bool.Must, query.Query.Bool.Filter...) {
+		if id, ok := clause.Term["container_id.keyword"]; ok {
+			containerIds = append(containerIds, id)
+		}
+		containerIds = append(containerIds, clause.Terms["container_id.keyword"]...)
+		if r, ok := clause.Range["last_updated"]; ok {
+			gte, lt = r.Gte, r.Lt
+		}
+	}
+
+	matched := make(map[string][]dto.EsStatus)
+	for _, containerId := range containerIds {
+		for _, doc := range f.docs[containerId] {
+			if doc.LastUpdated.Before(gte) || !doc.LastUpdated.Before(lt) {
+				continue
+			}
+			matched[containerId] = append(matched[containerId], doc)
+		}
+		sort.Slice(matched[containerId], func(i, j int) bool {
+			return matched[containerId][i].Counter < matched[containerId][j].Counter
+		})
+	}
+	return matched
+}
+
+func (f *fakeElasticsearchServer) msearch(w http.ResponseWriter, r *http.Request) {
+	type hit struct {
+		ID     string       `json:"_id"`
+		Source dto.EsStatus `json:"_source"`
+		Sort   []int64      `json:"sort"`
+	}
+	type response struct {
+		PitId string `json:"pit_id,omitempty"`
+		Hits  struct {
+			Hits []hit `json:"hits"`
+		} `json:"hits"`
+		Aggregations interface{} `json:"aggregations,omitempty"`
+	}
+
+	var responses []response
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	for scanner.Scan() {
+		if !scanner.Scan() {
+			break
+		}
+
+		var query fakeQuery
+		if err := json.Unmarshal(scanner.Bytes(), &query); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		matched := f.match(query)
+
+		var res response
+		res.Hits.Hits = []hit{}
+		if len(query.Aggs) > 0 {
+			res.Aggregations = aggregateFakeStatus(query, matched)
+			responses = append(responses, res)
+			continue
+		}
+
+		res.PitId = "fake-pit"
+		for containerId, docs := range matched {
+			for _, doc := range docs {
+				if len(query.SearchAfter) > 0 && doc.Counter <= query.SearchAfter[0] {
+					continue
+				}
+				if len(res.Hits.Hits) >= query.Size {
+					break
+				}
+				res.Hits.Hits = append(res.Hits.Hits, hit{
+					ID:     fmt.Sprintf("%s-%d", containerId, doc.Counter),
+					Source: doc,
+					Sort:   []int64{doc.Counter, doc.Counter},
+				})
+			}
+		}
+		responses = append(responses, res)
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"responses": responses})
+}
+
+func (f *fakeElasticsearchServer) client() clients.IElasticsearchClient {
+	es, err := elasticsearch.NewClient(elasticsearch.Config{Addresses: []string{f.URL}})
+	if err != nil {
+		panic(err)
+	}
+	return clients.NewElasticsearchClient(es)
+}
+
+func generateEsStatus(containerId string, count int, from time.Time) []dto.EsStatus {
+	statuses := make([]dto.EsStatus, 0, count)
+	for i := 0; i < count; i++ {
+		status := entities.ContainerOn
+		if i%2 == 1 {
+			status = entities.ContainerOff
+		}
+		statuses = append(statuses, dto.EsStatus{
+			ContainerId: containerId,
+			Status:      status,
+			Uptime:      60,
+			LastUpdated: from.Add(time.Duration(i) * time.Minute),
+			Counter:     int64(i + 1),
+		})
+	}
+	return statuses
+}
+
 func (s *ReportServiceSuite) SetupTest() {
 	s.ctrl = gomock.NewController(s.T())
 	s.esClient = interfaces.NewMockIElasticsearchClient(s.ctrl)
 	s.redisClient = interfaces.NewMockIRedisClient(s.ctrl)
+	s.mailDialer = interfaces.NewMockIMailDialer(s.ctrl)
 	s.logger = logger.NewMockILogger(s.ctrl)
 
-	s.reportService = NewReportService(s.esClient, s.redisClient, s.logger, env.GomailEnv{
+	s.reportService = NewReportService(s.esClient, s.redisClient, s.mailDialer, s.logger, nil, env.GomailEnv{
 		MailUsername: "test@gmail.com",
 		MailPassword: "testpass",
-	})
+		MailFrom:     "reports@example.com",
+		MailFromName: "VCS Reports",
+	}, env.ReportEnv{
+		SLATarget: 99.9,
+	}, env.RetryEnv{})
 	s.ctx = context.Background()
 
 	s.sampleReport = &dto.ReportResponse{
@@ -64,12 +258,8 @@ func (s *ReportServiceSuite) SetupTest() {
 		EndTime:           time.Now(),
 	}
 
-	err := os.MkdirAll("html", 0755)
-	if err != nil {
-		s.T().Fatal("Failed to create html directory:", err)
-	}
-
-	htmlContent := `<!DOCTYPE html>
+	s.templateDir = s.T().TempDir()
+	s.useTemplate(templates.DefaultTemplate, `<!DOCTYPE html>
 <html>
 <head><title>Test Report</title></head>
 <body>
@@ -80,16 +270,25 @@ func (s *ReportServiceSuite) SetupTest() {
     <p>Offline Containers: {{ .ContainerOffCount }}</p>
     <p>Total Uptime: {{ .TotalUptime }}h</p>
 </body>
-</html>`
+</html>`, `Daily Container Report
+{{ .StartTime | formatTime }} - {{ .EndTime | formatTime }}
+Total Container: {{ .ContainerCount }}
+Total Uptime: {{ .TotalUptime }}h`)
+}
 
-	err = os.WriteFile("html/email.html", []byte(htmlContent), 0644)
-	if err != nil {
-		s.T().Fatal("Failed to create html file:", err)
-	}
+// useTemplate writes a template called name to the template directory and
+// reloads the registry of the service under test.
+func (s *ReportServiceSuite) useTemplate(name string, html string, text string) {
+	s.Require().NoError(os.WriteFile(filepath.Join(s.templateDir, name+".html"), []byte(html), 0644))
+	s.Require().NoError(os.WriteFile(filepath.Join(s.templateDir, name+".txt"), []byte(text), 0644))
+
+	var err error
+	s.templates, err = templates.NewTemplateRegistry(s.templateDir)
+	s.Require().NoError(err)
+	s.reportService.(*reportService).templates = s.templates
 }
 
 func (s *ReportServiceSuite) TearDownTest() {
-	os.RemoveAll("html")
 	s.ctrl.Finish()
 }
 
@@ -97,36 +296,332 @@ func TestReportServiceSuite(t *testing.T) {
 	suite.Run(t, new(ReportServiceSuite))
 }
 
+func (s *ReportServiceSuite) TestSendEmail() {
+	var sent *gomail.Message
+	s.mailDialer.EXPECT().
+		DialAndSend(gomock.Any()).
+		DoAndReturn(func(messages ...*gomail.Message) error {
+			sent = messages[0]
+			return nil
+		})
+	s.logger.EXPECT().Info("report sent successfully", gomock.Any()).Times(1)
+
+	err := s.reportService.SendEmail(s.ctx, "recipient@example.com", *s.sampleReport)
+	s.NoError(err)
+
+	s.Require().NotNil(sent)
+	s.Equal([]string{`"VCS Reports" <reports@example.com>`}, sent.GetHeader("From"))
+	s.Equal([]string{"recipient@example.com"}, sent.GetHeader("To"))
+
+	var body bytes.Buffer
+	_, err = sent.WriteTo(&body)
+	s.NoError(err)
+	s.Contains(body.String(), "Total Container: 10")
+}
+
+func (s *ReportServiceSuite) TestSendEmailAttachesExports() {
+	reportService := NewReportService(s.esClient, s.redisClient, s.mailDialer, s.logger, s.templates, env.GomailEnv{MailFrom: "reports@example.com"}, env.ReportEnv{SLATarget: 99.9, EmailAttachments: []string{"csv", "pdf"}}, env.RetryEnv{})
+	report := *s.sampleReport
+	report.StartTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	report.EndTime = time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC)
+	report.Containers = []dto.ContainerReport{{ContainerId: "container-1", Status: entities.ContainerOn, UptimeHours: 24, Availability: 100}}
+
+	var sent *gomail.Message
+	s.mailDialer.EXPECT().
+		DialAndSend(gomock.Any()).
+		DoAndReturn(func(messages ...*gomail.Message) error {
+			sent = messages[0]
+			return nil
+		})
+	s.logger.EXPECT().Info("report sent successfully", gomock.Any()).Times(1)
+
+	err := reportService.SendEmail(s.ctx, "recipient@example.com", report)
+	s.NoError(err)
+
+	var body bytes.Buffer
+	_, err = sent.WriteTo(&body)
+	s.NoError(err)
+	s.Contains(body.String(), `Content-Disposition: attachment; filename="report_2024-01-01_2024-01-31.csv"`)
+	s.Contains(body.String(), "Content-Type: text/csv; charset=utf-8")
+	s.Contains(body.String(), `Content-Disposition: attachment; filename="report_2024-01-01_2024-01-31.pdf"`)
+	s.Contains(body.String(), "Content-Type: application/pdf")
+	s.Contains(body.String(), "Content-Type: multipart/mixed")
+	s.Contains(body.String(), "Content-Type: multipart/alternative")
+	s.Contains(body.String(), "Content-Type: text/html")
+}
+
+func (s *ReportServiceSuite) TestSendEmailAttachmentsLocalizedForRecipient() {
+	reportService := NewReportService(s.esClient, s.redisClient, s.mailDialer, s.logger, s.templates, env.GomailEnv{MailFrom: "reports@example.com"}, env.ReportEnv{
+		SLATarget:        99.9,
+		EmailAttachments: []string{"pdf"},
+		Recipients: []env.RecipientPreferenceEnv{
+			{Address: "ops@example.vn", Locale: "vi", Timezone: "Asia/Ho_Chi_Minh"},
+		},
+	}, env.RetryEnv{})
+	report := *s.sampleReport
+	report.StartTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	report.EndTime = time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC)
+
+	var sent *gomail.Message
+	s.mailDialer.EXPECT().
+		DialAndSend(gomock.Any()).
+		DoAndReturn(func(messages ...*gomail.Message) error {
+			sent = messages[0]
+			return nil
+		})
+	s.logger.EXPECT().Info("report sent successfully", gomock.Any()).Times(1)
+
+	s.NoError(reportService.SendEmail(s.ctx, "ops@example.vn", report))
+
+	var raw bytes.Buffer
+	_, err := sent.WriteTo(&raw)
+	s.NoError(err)
+	message, err := mail.ReadMessage(&raw)
+	s.Require().NoError(err)
+	_, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	s.Require().NoError(err)
+
+	parts := multipart.NewReader(message.Body, params["boundary"])
+	var attachment string
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		s.Require().NoError(err)
+		if part.FileName() == "" {
+			continue
+		}
+		s.Equal("bao-cao_2024-01-01_2024-02-01.pdf", part.FileName())
+		data, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, part))
+		s.Require().NoError(err)
+		attachment = string(data)
+	}
+	s.Contains(attachment, "(Ky bao cao: 01/01/2024 07:00 +07 - 01/02/2024 06:59 +07) Tj")
+}
+
+func (s *ReportServiceSuite) TestSendEmailMultipartAlternative() {
+	var sent *gomail.Message
+	s.mailDialer.EXPECT().
+		DialAndSend(gomock.Any()).
+		DoAndReturn(func(messages ...*gomail.Message) error {
+			sent = messages[0]
+			return nil
+		})
+	s.logger.EXPECT().Info("report sent successfully", gomock.Any()).Times(1)
+
+	s.NoError(s.reportService.SendEmail(s.ctx, "recipient@example.com", *s.sampleReport))
+
+	var raw bytes.Buffer
+	_, err := sent.WriteTo(&raw)
+	s.NoError(err)
+	message, err := mail.ReadMessage(&raw)
+	s.Require().NoError(err)
+
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	s.Require().NoError(err)
+	s.Equal("multipart/alternative", mediaType)
+
+	parts := multipart.NewReader(message.Body, params["boundary"])
+	var contentTypes, bodies []string
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		s.Require().NoError(err)
+		body, err := io.ReadAll(part)
+		s.Require().NoError(err)
+		contentTypes = append(contentTypes, part.Header.Get("Content-Type"))
+		bodies = append(bodies, string(body))
+	}
+
+	s.Equal([]string{"text/plain; charset=UTF-8", "text/html; charset=UTF-8"}, contentTypes)
+	s.Contains(bodies[0], "Total Container: 10")
+	s.NotContains(bodies[0], "<")
+	s.Contains(bodies[1], "<p>Total Container: 10</p>")
+}
+
+func (s *ReportServiceSuite) TestSendEmailRendersCharts() {
+	s.useTemplate("charts", `<html><body>{{ fleetChart . }}{{ availabilityHistogram . }}{{ timelineChart . }}</body></html>`, "")
+
+	report := *s.sampleReport
+	report.Template = "charts"
+	report.Availability = 99
+	report.Containers = []dto.ContainerReport{{
+		ContainerId:  "container-1",
+		Availability: 99,
+		Timeline:     []dto.StatusSegment{{Status: entities.ContainerOn, Start: report.StartTime, End: report.EndTime}},
+	}}
+
+	var sent *gomail.Message
+	s.mailDialer.EXPECT().
+		DialAndSend(gomock.Any()).
+		DoAndReturn(func(messages ...*gomail.Message) error {
+			sent = messages[0]
+			return nil
+		})
+	s.logger.EXPECT().Info("report sent successfully", gomock.Any()).Times(1)
+
+	s.NoError(s.reportService.SendEmail(s.ctx, "recipient@example.com", report))
+
+	var message bytes.Buffer
+	_, err := sent.WriteTo(&message)
+	s.NoError(err)
+	body, err := io.ReadAll(quotedprintable.NewReader(&message))
+	s.NoError(err)
+	s.Contains(string(body), `aria-label="Fleet Uptime"`)
+	s.Contains(string(body), `aria-label="Availability Distribution"`)
+	s.Contains(string(body), `aria-label="Container Timeline"`)
+}
+
+func (s *ReportServiceSuite) TestNewReportServiceSkipsUnsupportedAttachment() {
+	s.logger.EXPECT().Warn("skipping unsupported email attachment", gomock.Any(), gomock.Any()).Times(1)
+
+	service := NewReportService(s.esClient, s.redisClient, s.mailDialer, s.logger, s.templates, env.GomailEnv{}, env.ReportEnv{EmailAttachments: []string{"xlsx"}}, env.RetryEnv{})
+	s.Empty(service.(*reportService).attachments)
+}
+
+func (s *ReportServiceSuite) TestNewReportServiceSkipsInvalidRecipientPreferences() {
+	s.logger.EXPECT().Warn("skipping invalid recipient preferences", gomock.Any(), gomock.Any()).Times(1)
+
+	service := NewReportService(s.esClient, s.redisClient, s.mailDialer, s.logger, s.templates, env.GomailEnv{}, env.ReportEnv{
+		Recipients: []env.RecipientPreferenceEnv{
+			{Address: "ops@example.vn", Locale: "vi", Timezone: "Asia/Ho_Chi_Minh"},
+			{Address: "ops@example.fr", Locale: "fr", Timezone: "Europe/Paris"},
+		},
+	}, env.RetryEnv{})
+	s.Len(service.(*reportService).recipients, 1)
+}
+
+func (s *ReportServiceSuite) TestSendEmailLocalizedForRecipient() {
+	service := NewReportService(s.esClient, s.redisClient, s.mailDialer, s.logger, s.templates, env.GomailEnv{MailFrom: "reports@example.com"}, env.ReportEnv{
+		SLATarget: 99.9,
+		Locale:    "en",
+		Timezone:  "UTC",
+		Recipients: []env.RecipientPreferenceEnv{
+			{Address: "Ops@Example.vn", Locale: "vi", Timezone: "Asia/Ho_Chi_Minh"},
+		},
+	}, env.RetryEnv{})
+	s.useTemplate("localized", `<p>{{ t "stat.uptime" }}: {{ formatNumber .TotalUptime }}</p>`, `{{ t "stat.uptime" }}: {{ formatNumber .TotalUptime }}`)
+	service.(*reportService).templates = s.templates
+
+	report := *s.sampleReport
+	report.Template = "localized"
+	report.TotalUptime = 1234.5
+	report.StartTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	report.EndTime = time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	var sent []*gomail.Message
+	s.mailDialer.EXPECT().
+		DialAndSend(gomock.Any()).
+		DoAndReturn(func(messages ...*gomail.Message) error {
+			sent = append(sent, messages[0])
+			return nil
+		}).
+		Times(2)
+	s.logger.EXPECT().Info("report sent successfully", gomock.Any()).Times(2)
+
+	s.NoError(service.SendEmail(s.ctx, "ops@example.vn", report))
+	s.NoError(service.SendEmail(s.ctx, "ops@example.com", report))
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(sent[0].GetHeader("Subject")[0])
+	s.NoError(err)
+	s.Equal("Báo cáo Hệ thống Quản lý Container từ 01/01/2024 07:00 +07 đến 02/01/2024 07:00 +07", subject)
+	s.Equal([]string{"Container Management System Report from 2024-01-01 00:00 UTC to 2024-01-02 00:00 UTC"}, sent[1].GetHeader("Subject"))
+
+	var body bytes.Buffer
+	_, err = sent[0].WriteTo(&body)
+	s.NoError(err)
+	decoded, err := io.ReadAll(quotedprintable.NewReader(&body))
+	s.NoError(err)
+	s.Contains(string(decoded), "<p>Số giờ Hoạt động: 1.234,50</p>")
+
+	body.Reset()
+	_, err = sent[1].WriteTo(&body)
+	s.NoError(err)
+	s.Contains(body.String(), "<p>Uptime Hours: 1,234.50</p>")
+}
+
 func (s *ReportServiceSuite) TestSendEmailError() {
+	s.mailDialer.EXPECT().
+		DialAndSend(gomock.Any()).
+		Return(errors.New("connection refused"))
 	s.logger.EXPECT().Error("failed to send email", gomock.Any()).Times(1)
-	err := s.reportService.SendEmail(s.ctx, "recipient@example.com", 10, 7, 3, 24.5, s.sampleReport.StartTime, s.sampleReport.EndTime)
+	err := s.reportService.SendEmail(s.ctx, "recipient@example.com", *s.sampleReport)
 	s.Error(err)
 }
 
-func (s *ReportServiceSuite) TestSendEmailTemplateNotFound() {
-	os.Remove("html/email.html")
-	s.logger.EXPECT().Error("failed to read email template", gomock.Any()).Times(1)
-	err := s.reportService.SendEmail(s.ctx, "recipient@example.com", 10, 7, 3, 24.5, s.sampleReport.StartTime, s.sampleReport.EndTime)
-	s.Error(err)
+func (s *ReportServiceSuite) TestSendEmailRetriesTransientError() {
+	reportService := NewReportService(s.esClient, s.redisClient, s.mailDialer, s.logger, s.templates, env.GomailEnv{MailFrom: "reports@example.com"}, env.ReportEnv{SLATarget: 99.9}, env.RetryEnv{Attempts: 3, Backoff: time.Millisecond})
+	gomock.InOrder(
+		s.mailDialer.EXPECT().DialAndSend(gomock.Any()).Return(errors.New("421 service not available")),
+		s.mailDialer.EXPECT().DialAndSend(gomock.Any()).Return(nil),
+	)
+	s.logger.EXPECT().Info("report sent successfully", gomock.Any()).Times(1)
+
+	err := reportService.SendEmail(s.ctx, "recipient@example.com", *s.sampleReport)
+	s.NoError(err)
 }
 
-func (s *ReportServiceSuite) TestSendEmailInvalidTemplate() {
-	invalidTemplate := `{{invalid template syntax`
-	err := os.WriteFile("html/email.html", []byte(invalidTemplate), 0644)
+func (s *ReportServiceSuite) TestSendEmailRetriesExhausted() {
+	reportService := NewReportService(s.esClient, s.redisClient, s.mailDialer, s.logger, s.templates, env.GomailEnv{MailFrom: "reports@example.com"}, env.ReportEnv{SLATarget: 99.9}, env.RetryEnv{Attempts: 3, Backoff: time.Millisecond})
+	s.mailDialer.EXPECT().DialAndSend(gomock.Any()).Return(errors.New("connection refused")).Times(3)
+	s.logger.EXPECT().Error("failed to send email", gomock.Any()).Times(1)
+
+	err := reportService.SendEmail(s.ctx, "recipient@example.com", *s.sampleReport)
+	s.EqualError(err, "connection refused")
+	s.Equal(3, retry.Attempts(err))
+}
+
+func (s *ReportServiceSuite) TestSendEmailRejectedIsNotRetried() {
+	reportService := NewReportService(s.esClient, s.redisClient, s.mailDialer, s.logger, s.templates, env.GomailEnv{MailFrom: "reports@example.com"}, env.ReportEnv{SLATarget: 99.9}, env.RetryEnv{Attempts: 3, Backoff: time.Millisecond})
+	rejected := fmt.Errorf("%w: 550 mailbox unavailable", clients.ErrMailRejected)
+	s.mailDialer.EXPECT().DialAndSend(gomock.Any()).Return(rejected).Times(1)
+	s.logger.EXPECT().Error("failed to send email", gomock.Any()).Times(1)
+
+	err := reportService.SendEmail(s.ctx, "recipient@example.com", *s.sampleReport)
+	s.ErrorIs(err, clients.ErrMailRejected)
+	s.Equal(1, retry.Attempts(err))
+}
+
+func (s *ReportServiceSuite) TestSendEmailSelectsTemplate() {
+	s.useTemplate(templates.TemplateOnCall, "<p>On call: {{ .ContainerOffCount }}</p>", "On call: {{ .ContainerOffCount }}")
+
+	var sent *gomail.Message
+	s.mailDialer.EXPECT().
+		DialAndSend(gomock.Any()).
+		DoAndReturn(func(messages ...*gomail.Message) error {
+			sent = messages[0]
+			return nil
+		})
+	s.logger.EXPECT().Info("report sent successfully", gomock.Any()).Times(1)
+
+	report := *s.sampleReport
+	report.Template = templates.TemplateOnCall
+	s.NoError(s.reportService.SendEmail(s.ctx, "recipient@example.com", report))
+
+	var message bytes.Buffer
+	_, err := sent.WriteTo(&message)
 	s.NoError(err)
+	s.Contains(message.String(), "<p>On call: 3</p>")
+	s.NotContains(message.String(), "Daily Container Report")
+}
 
-	s.logger.EXPECT().Error("failed to parse template", gomock.Any()).Times(1)
-	err = s.reportService.SendEmail(s.ctx, "recipient@example.com", 10, 7, 3, 24.5, s.sampleReport.StartTime, s.sampleReport.EndTime)
-	s.Error(err)
+func (s *ReportServiceSuite) TestSendEmailTemplateNotFound() {
+	s.logger.EXPECT().Error("failed to render email template", gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+
+	report := *s.sampleReport
+	report.Template = "missing"
+	err := s.reportService.SendEmail(s.ctx, "recipient@example.com", report)
+	s.ErrorIs(err, templates.ErrTemplateNotFound)
 }
 
 func (s *ReportServiceSuite) TestSendEmailTemplateExecutionError() {
-	invalidTemplate := `<html><body>{{.NonExistentField}}</body></html>`
-	err := os.WriteFile("html/email.html", []byte(invalidTemplate), 0644)
-	s.NoError(err)
+	s.useTemplate(templates.DefaultTemplate, `<html><body>{{.NonExistentField}}</body></html>`, "")
 
-	s.logger.EXPECT().Error("failed to execute template", gomock.Any()).Times(1)
-	err = s.reportService.SendEmail(s.ctx, "recipient@example.com", 10, 7, 3, 24.5, s.sampleReport.StartTime, s.sampleReport.EndTime)
+	s.logger.EXPECT().Error("failed to render email template", gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	err := s.reportService.SendEmail(s.ctx, "recipient@example.com", *s.sampleReport)
 	s.Error(err)
 }
 
@@ -156,11 +651,115 @@ func (s *ReportServiceSuite) TestCalculateReportStatistic() {
 		},
 	}
 
-	onCount, offCount, totalUptime := s.reportService.CalculateReportStatistic(statusList, overlapStatusList, startTime, endTime)
+	report := s.reportService.CalculateReportStatistic(statusList, overlapStatusList, startTime, endTime)
+
+	s.Equal(3, report.ContainerCount)
+	s.Equal(1, report.ContainerOnCount)
+	s.Equal(2, report.ContainerOffCount)
+	s.Equal(float64(2), report.TotalUptime)
+	s.InDelta(100*2.0/12.0, report.Availability, 1e-9)
+	s.Equal(99.9, report.SLATarget)
+	s.Equal(3, report.SLABreachedCount)
+	s.Equal(startTime, report.StartTime)
+	s.Equal(endTime, report.EndTime)
+
+	s.Len(report.Containers, 3)
+	s.Equal("container1", report.Containers[0].ContainerId)
+	s.Equal(entities.ContainerOff, report.Containers[0].Status)
+	s.Equal(1.5, report.Containers[0].UptimeHours)
+	s.Equal(2.5, report.Containers[0].DowntimeHours)
+	s.Equal(2, report.Containers[0].Transitions)
+	s.Equal(37.5, report.Containers[0].Availability)
+	s.True(report.Containers[0].SLABreached)
+	s.Equal(baseTime.Add(-210*time.Minute), report.Containers[0].FirstSeen)
+	s.Equal(baseTime.Add(-2*time.Hour), report.Containers[0].LastSeen)
+	s.Equal([]dto.StatusSegment{
+		{Status: entities.ContainerOn, Start: startTime, End: baseTime.Add(-210 * time.Minute)},
+		{Status: entities.ContainerOff, Start: baseTime.Add(-210 * time.Minute), End: baseTime.Add(-3 * time.Hour)},
+		{Status: entities.ContainerOn, Start: baseTime.Add(-3 * time.Hour), End: baseTime.Add(-2 * time.Hour)},
+		{Status: entities.ContainerOff, Start: baseTime.Add(-2 * time.Hour), End: endTime},
+	}, report.Containers[0].Timeline)
+
+	s.Equal("container2", report.Containers[1].ContainerId)
+	s.Equal(entities.ContainerOff, report.Containers[1].Status)
+	s.Equal(float64(0), report.Containers[1].UptimeHours)
+	s.Equal(float64(4), report.Containers[1].DowntimeHours)
+	s.Equal(0, report.Containers[1].Transitions)
+	s.Equal([]dto.StatusSegment{{Status: entities.ContainerOff, Start: startTime, End: endTime}}, report.Containers[1].Timeline)
+
+	s.Equal("container3", report.Containers[2].ContainerId)
+	s.Equal(entities.ContainerOn, report.Containers[2].Status)
+	s.Equal(0.5, report.Containers[2].UptimeHours)
+	s.Equal(12.5, report.Containers[2].Availability)
+	s.True(report.Containers[2].FirstSeen.IsZero())
+	s.Equal([]dto.StatusSegment{
+		{Status: entities.ContainerOff, Start: startTime, End: baseTime.Add(-30 * time.Minute)},
+		{Status: entities.ContainerOn, Start: baseTime.Add(-30 * time.Minute), End: endTime},
+	}, report.Containers[2].Timeline)
+}
+
+func (s *ReportServiceSuite) TestStatusTimelineMergesConsecutiveUptime() {
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	endTime := startTime.Add(4 * time.Hour)
+	statuses := []dto.EsStatus{
+		{Status: entities.ContainerOn, Uptime: 3600, LastUpdated: startTime.Add(time.Hour)},
+		{Status: entities.ContainerOn, Uptime: 7200, LastUpdated: startTime.Add(2 * time.Hour)},
+	}
+	overlapStatuses := []dto.EsStatus{
+		{Status: entities.ContainerOn, Uptime: 3600, LastUpdated: endTime.Add(30 * time.Minute)},
+	}
+
+	s.Equal([]dto.StatusSegment{
+		{Status: entities.ContainerOn, Start: startTime, End: startTime.Add(2 * time.Hour)},
+		{Status: entities.ContainerOff, Start: startTime.Add(2 * time.Hour), End: endTime.Add(-30 * time.Minute)},
+		{Status: entities.ContainerOn, Start: endTime.Add(-30 * time.Minute), End: endTime},
+	}, statusTimeline(statuses, overlapStatuses, startTime, endTime))
+}
+
+func (s *ReportServiceSuite) TestCalculateReportStatisticSLACompliance() {
+	endTime := time.Now()
+	startTime := endTime.Add(-4 * time.Hour)
+	statusList := map[string][]dto.EsStatus{
+		"healthy": {},
+		"flaky": {
+			{ContainerId: "flaky", Status: entities.ContainerOn, Uptime: int64(3600), LastUpdated: endTime.Add(-1 * time.Minute)},
+		},
+	}
+
+	overlapStatusList := map[string][]dto.EsStatus{
+		"healthy": {
+			{ContainerId: "healthy", Status: entities.ContainerOn, Uptime: int64(5 * 3600), LastUpdated: endTime.Add(1 * time.Minute)},
+		},
+	}
+
+	report := s.reportService.CalculateReportStatistic(statusList, overlapStatusList, startTime, endTime)
+
+	s.Equal(1, report.SLABreachedCount)
+	s.Equal("flaky", report.Containers[0].ContainerId)
+	s.True(report.Containers[0].SLABreached)
+	s.Equal("healthy", report.Containers[1].ContainerId)
+	s.False(report.Containers[1].SLABreached)
+	s.Equal(25.0, report.Containers[0].Availability)
+	s.Equal(100.0, report.Containers[1].Availability)
+	s.Equal(62.5, report.Availability)
+}
+
+func (s *ReportServiceSuite) TestGenerateReportGetEsStatusError() {
+	ctx := context.Background()
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	endTime := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	s.redisClient.EXPECT().
+		Get(ctx, "containers").
+		Return(nil, errors.New("redis connection failed"))
+
+	s.logger.EXPECT().
+		Error("failed to get container ids from redis", gomock.Any()).
+		Times(1)
 
-	s.Equal(1, onCount)
-	s.Equal(2, offCount)
-	s.Equal(float64(2), totalUptime)
+	_, err := s.reportService.GenerateReport(ctx, startTime, endTime, entities.ContainerFilter{})
+
+	s.Error(err)
 }
 
 func (s *ReportServiceSuite) TestGetEsStatus() {
@@ -217,6 +816,14 @@ func (s *ReportServiceSuite) TestGetEsStatus() {
 
 	mockResponse := NewMockElasticsearchResponse(esResponse, 200)
 
+	s.esClient.EXPECT().
+		OpenPointInTime(ctx, "sms_container", "1m").
+		Return("pit-id", nil)
+
+	s.esClient.EXPECT().
+		ClosePointInTime(gomock.Any(), "pit-id").
+		Return(nil)
+
 	s.esClient.EXPECT().
 		Do(ctx, gomock.Any()).
 		Return(mockResponse, nil)
@@ -225,7 +832,7 @@ func (s *ReportServiceSuite) TestGetEsStatus() {
 		Info("elasticsearch status retrieved successfully", gomock.Any()).
 		Times(1)
 
-	result, err := s.reportService.GetEsStatus(ctx, limit, startTime, endTime, dto.Asc)
+	result, err := s.reportService.GetEsStatus(ctx, limit, startTime, endTime, dto.Asc, entities.ContainerFilter{})
 
 	s.NoError(err)
 	s.Len(result, 2)
@@ -239,6 +846,51 @@ func (s *ReportServiceSuite) TestGetEsStatus() {
 	s.Equal(entities.ContainerOff, result["container2"][0].Status)
 }
 
+func (s *ReportServiceSuite) TestGetEsStatusFiltered() {
+	ctx := context.Background()
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	endTime := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	s.redisClient.EXPECT().
+		Get(ctx, "containers").
+		Return([]entities.ContainerWithStatus{
+			{ContainerId: "api-1", Status: entities.ContainerOn, Labels: map[string]string{"team": "payments"}},
+			{ContainerId: "api-2", Status: entities.ContainerOn, Labels: map[string]string{"team": "search"}},
+			{ContainerId: "worker-1", Status: entities.ContainerOn, Labels: map[string]string{"team": "payments"}},
+			{ContainerId: "api-3", Status: entities.ContainerOff},
+		}, nil)
+
+	s.esClient.EXPECT().
+		OpenPointInTime(ctx, "sms_container", "1m").
+		Return("pit-id", nil)
+
+	s.esClient.EXPECT().
+		ClosePointInTime(gomock.Any(), "pit-id").
+		Return(nil)
+
+	s.esClient.EXPECT().
+		Do(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, req esapi.Request) (*esapi.Response, error) {
+			body, err := io.ReadAll(req.(esapi.MsearchRequest).Body)
+			s.Require().NoError(err)
+			s.Contains(string(body), `"container_id.keyword":"api-1"`)
+			s.NotContains(string(body), `"container_id.keyword":"api-2"`)
+			s.NotContains(string(body), `"container_id.keyword":"worker-1"`)
+			s.NotContains(string(body), `"container_id.keyword":"api-3"`)
+			return NewMockElasticsearchResponse(`{"responses": [{"hits": {"hits": []}}]}`, 200), nil
+		})
+
+	s.logger.EXPECT().
+		Info("elasticsearch status retrieved successfully", gomock.Any()).
+		Times(1)
+
+	filter := entities.ContainerFilter{Pattern: "api-", Labels: map[string]string{"team": "payments"}}
+	result, err := s.reportService.GetEsStatus(ctx, 0, startTime, endTime, dto.Asc, filter)
+
+	s.NoError(err)
+	s.Empty(result)
+}
+
 func (s *ReportServiceSuite) TestGetEsStatusRedisError() {
 	ctx := context.Background()
 	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
@@ -255,11 +907,11 @@ func (s *ReportServiceSuite) TestGetEsStatusRedisError() {
 		Error("failed to get container ids from redis", gomock.Any()).
 		Times(1)
 
-	result, err := s.reportService.GetEsStatus(ctx, limit, startTime, endTime, dto.Asc)
+	result, err := s.reportService.GetEsStatus(ctx, limit, startTime, endTime, dto.Asc, entities.ContainerFilter{})
 
 	s.Error(err)
 	s.Nil(result)
-	s.Equal(expectedError, err)
+	s.ErrorIs(err, expectedError)
 }
 
 func (s *ReportServiceSuite) TestGetEsStatusElasticsearchError() {
@@ -278,6 +930,14 @@ func (s *ReportServiceSuite) TestGetEsStatusElasticsearchError() {
 		Get(ctx, "containers").
 		Return(containers, nil)
 
+	s.esClient.EXPECT().
+		OpenPointInTime(ctx, "sms_container", "1m").
+		Return("pit-id", nil)
+
+	s.esClient.EXPECT().
+		ClosePointInTime(gomock.Any(), "pit-id").
+		Return(nil)
+
 	s.esClient.EXPECT().
 		Do(ctx, gomock.Any()).
 		Return(nil, expectedError)
@@ -286,11 +946,11 @@ func (s *ReportServiceSuite) TestGetEsStatusElasticsearchError() {
 		Error("failed to msearch elasticsearch status", gomock.Any()).
 		Times(1)
 
-	result, err := s.reportService.GetEsStatus(ctx, limit, startTime, endTime, dto.Asc)
+	result, err := s.reportService.GetEsStatus(ctx, limit, startTime, endTime, dto.Asc, entities.ContainerFilter{})
 
 	s.Error(err)
 	s.Nil(result)
-	s.Equal(expectedError, err)
+	s.ErrorIs(err, expectedError)
 }
 
 func (s *ReportServiceSuite) TestGetEsStatusInvalidJSONResponse() {
@@ -310,6 +970,14 @@ func (s *ReportServiceSuite) TestGetEsStatusInvalidJSONResponse() {
 	invalidJSON := `{"invalid": json}`
 	mockResponse := NewMockElasticsearchResponse(invalidJSON, 200)
 
+	s.esClient.EXPECT().
+		OpenPointInTime(ctx, "sms_container", "1m").
+		Return("pit-id", nil)
+
+	s.esClient.EXPECT().
+		ClosePointInTime(gomock.Any(), "pit-id").
+		Return(nil)
+
 	s.esClient.EXPECT().
 		Do(ctx, gomock.Any()).
 		Return(mockResponse, nil)
@@ -318,8 +986,202 @@ func (s *ReportServiceSuite) TestGetEsStatusInvalidJSONResponse() {
 		Error("failed to decode response body", gomock.Any()).
 		Times(1)
 
-	result, err := s.reportService.GetEsStatus(ctx, limit, startTime, endTime, dto.Asc)
+	result, err := s.reportService.GetEsStatus(ctx, limit, startTime, endTime, dto.Asc, entities.ContainerFilter{})
 
 	s.Error(err)
 	s.Nil(result)
 }
+
+func (s *ReportServiceSuite) TestGetEsStatusNoContainers() {
+	ctx := context.Background()
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	endTime := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	s.redisClient.EXPECT().
+		Get(ctx, "containers").
+		Return([]entities.ContainerWithStatus{}, nil)
+
+	s.logger.EXPECT().
+		Info("elasticsearch status retrieved successfully", gomock.Any()).
+		Times(1)
+
+	result, err := s.reportService.GetEsStatus(ctx, 0, startTime, endTime, dto.Asc, entities.ContainerFilter{})
+
+	s.NoError(err)
+	s.Empty(result)
+}
+
+func (s *ReportServiceSuite) TestGetEsStatusRetriesTransientError() {
+	reportService := NewReportService(s.esClient, s.redisClient, s.mailDialer, s.logger, s.templates, env.GomailEnv{}, env.ReportEnv{SLATarget: 99.9}, env.RetryEnv{Attempts: 2, Backoff: time.Millisecond})
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	endTime := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	gomock.InOrder(
+		s.redisClient.EXPECT().Get(s.ctx, "containers").Return(nil, errors.New("redis connection failed")),
+		s.redisClient.EXPECT().Get(s.ctx, "containers").Return([]entities.ContainerWithStatus{}, nil),
+	)
+	s.logger.EXPECT().Error("failed to get container ids from redis", gomock.Any()).Times(1)
+	s.logger.EXPECT().Info("elasticsearch status retrieved successfully", gomock.Any()).Times(1)
+
+	result, err := reportService.GetEsStatus(s.ctx, 0, startTime, endTime, dto.Asc, entities.ContainerFilter{})
+	s.NoError(err)
+	s.Empty(result)
+}
+
+func (s *ReportServiceSuite) TestGetEsStatusOpenPointInTimeError() {
+	ctx := context.Background()
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	endTime := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	s.redisClient.EXPECT().
+		Get(ctx, "containers").
+		Return([]entities.ContainerWithStatus{{ContainerId: "container1", Status: entities.ContainerOn}}, nil)
+
+	s.esClient.EXPECT().
+		OpenPointInTime(ctx, "sms_container", "1m").
+		Return("", errors.New("index not found"))
+
+	s.logger.EXPECT().
+		Error("failed to open elasticsearch point in time", gomock.Any()).
+		Times(1)
+
+	result, err := s.reportService.GetEsStatus(ctx, 0, startTime, endTime, dto.Asc, entities.ContainerFilter{})
+
+	s.Error(err)
+	s.Nil(result)
+}
+
+func (s *ReportServiceSuite) TestGetEsStatusSearchResponseError() {
+	ctx := context.Background()
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	endTime := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	s.redisClient.EXPECT().
+		Get(ctx, "containers").
+		Return([]entities.ContainerWithStatus{{ContainerId: "container1", Status: entities.ContainerOn}}, nil)
+
+	s.esClient.EXPECT().
+		OpenPointInTime(ctx, "sms_container", "1m").
+		Return("pit-id", nil)
+
+	s.esClient.EXPECT().
+		ClosePointInTime(gomock.Any(), "pit-id").
+		Return(errors.New("pit already closed"))
+
+	s.esClient.EXPECT().
+		Do(ctx, gomock.Any()).
+		Return(NewMockElasticsearchResponse(`{"responses":[{"error":{"type":"search_context_missing_exception"},"status":404}]}`, 200), nil)
+
+	s.logger.EXPECT().
+		Error("failed to search elasticsearch status", gomock.Any()).
+		Times(1)
+	s.logger.EXPECT().
+		Warn("failed to close elasticsearch point in time", gomock.Any()).
+		Times(1)
+
+	result, err := s.reportService.GetEsStatus(ctx, 0, startTime, endTime, dto.Asc, entities.ContainerFilter{})
+
+	s.Error(err)
+	s.Nil(result)
+	s.Contains(err.Error(), "search_context_missing_exception")
+}
+
+func (s *ReportServiceSuite) TestGetEsStatusPaginatesBeyondPageSize() {
+	ctx := context.Background()
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	endTime := time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
+
+	server := newFakeElasticsearchServer(map[string][]dto.EsStatus{
+		"container1": generateEsStatus("container1", 2500, startTime),
+		"container2": generateEsStatus("container2", 3, startTime),
+	})
+	defer server.Close()
+
+	s.redisClient.EXPECT().
+		Get(ctx, "containers").
+		Return([]entities.ContainerWithStatus{
+			{ContainerId: "container1", Status: entities.ContainerOn},
+			{ContainerId: "container2", Status: entities.ContainerOn},
+			{ContainerId: "container3", Status: entities.ContainerOff},
+		}, nil)
+
+	s.logger.EXPECT().
+		Info("elasticsearch status retrieved successfully", gomock.Any()).
+		Times(1)
+
+	reportService := NewReportService(server.client(), s.redisClient, s.mailDialer, s.logger, s.templates, env.GomailEnv{}, env.ReportEnv{SLATarget: 99.9}, env.RetryEnv{})
+	result, err := reportService.GetEsStatus(ctx, 0, startTime, endTime, dto.Asc, entities.ContainerFilter{})
+
+	s.NoError(err)
+	s.Len(result, 2)
+	s.Len(result["container1"], 2500)
+	s.Len(result["container2"], 3)
+	for i, status := range result["container1"] {
+		s.Equal(int64(i+1), status.Counter)
+	}
+	s.Equal(int32(3), server.msearchCalls.Load())
+	s.Equal(int32(1), server.openPitCalls.Load())
+	s.Equal(int32(1), server.closePitCalls.Load())
+}
+
+func (s *ReportServiceSuite) TestGetEsStatusSubSecondBounds() {
+	ctx := context.Background()
+	startTime := time.Date(2024, 1, 1, 12, 0, 0, 500*int(time.Millisecond), time.UTC)
+	endTime := time.Date(2024, 1, 1, 12, 0, 1, 300*int(time.Millisecond), time.UTC)
+	status := func(offset time.Duration, counter int64) dto.EsStatus {
+		return dto.EsStatus{ContainerId: "container1", Status: entities.ContainerOn, LastUpdated: startTime.Add(offset), Counter: counter}
+	}
+
+	server := newFakeElasticsearchServer(map[string][]dto.EsStatus{
+		"container1": {
+			status(-300*time.Millisecond, 1),
+			status(200*time.Millisecond, 2),
+			status(600*time.Millisecond, 3),
+			status(900*time.Millisecond, 4),
+		},
+	})
+	defer server.Close()
+
+	s.redisClient.EXPECT().
+		Get(ctx, "containers").
+		Return([]entities.ContainerWithStatus{{ContainerId: "container1", Status: entities.ContainerOn}}, nil)
+	s.logger.EXPECT().
+		Info("elasticsearch status retrieved successfully", gomock.Any()).
+		Times(1)
+
+	reportService := NewReportService(server.client(), s.redisClient, s.mailDialer, s.logger, s.templates, env.GomailEnv{}, env.ReportEnv{SLATarget: 99.9}, env.RetryEnv{})
+	result, err := reportService.GetEsStatus(ctx, 0, startTime, endTime, dto.Asc, entities.ContainerFilter{})
+
+	s.NoError(err)
+	s.Require().Len(result["container1"], 2)
+	s.Equal(int64(2), result["container1"][0].Counter)
+	s.Equal(int64(3), result["container1"][1].Counter)
+}
+
+func (s *ReportServiceSuite) TestGetEsStatusPaginationRespectsLimit() {
+	ctx := context.Background()
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	endTime := time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
+
+	server := newFakeElasticsearchServer(map[string][]dto.EsStatus{
+		"container1": generateEsStatus("container1", 2500, startTime),
+	})
+	defer server.Close()
+
+	s.redisClient.EXPECT().
+		Get(ctx, "containers").
+		Return([]entities.ContainerWithStatus{{ContainerId: "container1", Status: entities.ContainerOn}}, nil)
+
+	s.logger.EXPECT().
+		Info("elasticsearch status retrieved successfully", gomock.Any()).
+		Times(1)
+
+	reportService := NewReportService(server.client(), s.redisClient, s.mailDialer, s.logger, s.templates, env.GomailEnv{}, env.ReportEnv{SLATarget: 99.9}, env.RetryEnv{})
+	result, err := reportService.GetEsStatus(ctx, 1200, startTime, endTime, dto.Asc, entities.ContainerFilter{})
+
+	s.NoError(err)
+	s.Len(result["container1"], 1200)
+	s.Equal(int64(1200), result["container1"][1199].Counter)
+	s.Equal(int32(2), server.msearchCalls.Load())
+	s.Equal(int32(1), server.closePitCalls.Load())
+}
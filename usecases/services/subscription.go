@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vnFuhung2903/vcs-report-service/dto"
+	"github.com/vnFuhung2903/vcs-report-service/entities"
+	"github.com/vnFuhung2903/vcs-report-service/interfaces"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/env"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/logger"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/templates"
+	"go.uber.org/zap"
+)
+
+const subscriptionKey = "report_subscriptions"
+
+var (
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+	ErrInvalidSubscription  = errors.New("invalid subscription")
+)
+
+type ISubscriptionService interface {
+	Create(ctx context.Context, owner string, req dto.SubscriptionRequest) (entities.ReportSubscription, error)
+	Get(ctx context.Context, owner string, id string) (entities.ReportSubscription, error)
+	List(ctx context.Context, owner string) ([]entities.ReportSubscription, error)
+	ListAll(ctx context.Context) ([]entities.ReportSubscription, error)
+	Update(ctx context.Context, owner string, id string, req dto.SubscriptionRequest) (entities.ReportSubscription, error)
+	Delete(ctx context.Context, owner string, id string) error
+}
+
+type subscriptionService struct {
+	redisClient interfaces.IRedisClient
+	logger      logger.ILogger
+	templates   templates.ITemplateRegistry
+	timezone    string
+	window      string
+	targetHosts env.TargetHostsEnv
+}
+
+func NewSubscriptionService(redisClient interfaces.IRedisClient, logger logger.ILogger, templateRegistry templates.ITemplateRegistry, reportEnv env.ReportEnv) ISubscriptionService {
+	return &subscriptionService{
+		redisClient: redisClient,
+		logger:      logger,
+		templates:   templateRegistry,
+		timezone:    reportEnv.Timezone,
+		window:      reportEnv.Window,
+		targetHosts: reportEnv.TargetHosts,
+	}
+}
+
+func (s *subscriptionService) Create(ctx context.Context, owner string, req dto.SubscriptionRequest) (entities.ReportSubscription, error) {
+	now := time.Now()
+	subscription := entities.ReportSubscription{
+		Id:        uuid.NewString(),
+		Owner:     owner,
+		CreatedAt: now,
+	}
+	if err := s.apply(&subscription, req, now); err != nil {
+		return entities.ReportSubscription{}, err
+	}
+
+	if err := s.save(ctx, subscription); err != nil {
+		return entities.ReportSubscription{}, err
+	}
+
+	s.logger.Info("subscription created successfully", zap.String("id", subscription.Id), zap.String("owner", owner))
+	return subscription, nil
+}
+
+func (s *subscriptionService) Get(ctx context.Context, owner string, id string) (entities.ReportSubscription, error) {
+	val, err := s.redisClient.HGet(ctx, subscriptionKey, id)
+	if err != nil {
+		s.logger.Error("failed to get subscription from redis", zap.Error(err))
+		return entities.ReportSubscription{}, err
+	}
+	if val == "" {
+		return entities.ReportSubscription{}, ErrSubscriptionNotFound
+	}
+
+	var subscription entities.ReportSubscription
+	if err := json.Unmarshal([]byte(val), &subscription); err != nil {
+		s.logger.Error("failed to decode subscription", zap.Error(err))
+		return entities.ReportSubscription{}, err
+	}
+	if subscription.Owner != owner {
+		return entities.ReportSubscription{}, ErrSubscriptionNotFound
+	}
+	return subscription, nil
+}
+
+func (s *subscriptionService) List(ctx context.Context, owner string) ([]entities.ReportSubscription, error) {
+	subscriptions, err := s.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	owned := make([]entities.ReportSubscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		if subscription.Owner == owner {
+			owned = append(owned, subscription)
+		}
+	}
+	return owned, nil
+}
+
+func (s *subscriptionService) ListAll(ctx context.Context) ([]entities.ReportSubscription, error) {
+	values, err := s.redisClient.HGetAll(ctx, subscriptionKey)
+	if err != nil {
+		s.logger.Error("failed to list subscriptions from redis", zap.Error(err))
+		return nil, err
+	}
+
+	subscriptions := make([]entities.ReportSubscription, 0, len(values))
+	for id, val := range values {
+		var subscription entities.ReportSubscription
+		if err := json.Unmarshal([]byte(val), &subscription); err != nil {
+			s.logger.Warn("skipping undecodable subscription", zap.String("id", id), zap.Error(err))
+			continue
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	sort.Slice(subscriptions, func(i, j int) bool {
+		return subscriptions[i].CreatedAt.Before(subscriptions[j].CreatedAt)
+	})
+	return subscriptions, nil
+}
+
+func (s *subscriptionService) Update(ctx context.Context, owner string, id string, req dto.SubscriptionRequest) (entities.ReportSubscription, error) {
+	subscription, err := s.Get(ctx, owner, id)
+	if err != nil {
+		return entities.ReportSubscription{}, err
+	}
+	if err := s.apply(&subscription, req, time.Now()); err != nil {
+		return entities.ReportSubscription{}, err
+	}
+
+	if err := s.save(ctx, subscription); err != nil {
+		return entities.ReportSubscription{}, err
+	}
+
+	s.logger.Info("subscription updated successfully", zap.String("id", id), zap.String("owner", owner))
+	return subscription, nil
+}
+
+func (s *subscriptionService) Delete(ctx context.Context, owner string, id string) error {
+	if _, err := s.Get(ctx, owner, id); err != nil {
+		return err
+	}
+
+	if err := s.redisClient.HDel(ctx, subscriptionKey, id); err != nil {
+		s.logger.Error("failed to delete subscription from redis", zap.Error(err))
+		return err
+	}
+
+	s.logger.Info("subscription deleted successfully", zap.String("id", id), zap.String("owner", owner))
+	return nil
+}
+
+func (s *subscriptionService) apply(subscription *entities.ReportSubscription, req dto.SubscriptionRequest, now time.Time) error {
+	subscription.Name = req.Name
+	subscription.Schedule = req.Schedule
+	subscription.Timezone = req.Timezone
+	subscription.Window = req.Window
+	subscription.Channel = req.Channel
+	subscription.Recipients = req.Recipients
+	subscription.Template = req.Template
+	subscription.Filter = req.Filter
+	subscription.UpdatedAt = now
+
+	if subscription.Timezone == "" {
+		subscription.Timezone = s.timezone
+	}
+	if subscription.Window == "" {
+		subscription.Window = s.window
+	}
+
+	if err := env.ValidateReportSchedule(SubscriptionSchedule(*subscription), s.targetHosts); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSubscription, err)
+	}
+	if !s.templates.Has(subscription.Template) {
+		return fmt.Errorf("%w: report template %q does not exist", ErrInvalidSubscription, subscription.Template)
+	}
+	return nil
+}
+
+func (s *subscriptionService) save(ctx context.Context, subscription entities.ReportSubscription) error {
+	val, err := json.Marshal(subscription)
+	if err != nil {
+		s.logger.Error("failed to encode subscription", zap.Error(err))
+		return err
+	}
+
+	if err := s.redisClient.HSet(ctx, subscriptionKey, subscription.Id, string(val)); err != nil {
+		s.logger.Error("failed to save subscription to redis", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func SubscriptionSchedule(subscription entities.ReportSubscription) env.ReportScheduleEnv {
+	return env.ReportScheduleEnv{
+		Name:       subscription.Name,
+		Schedule:   subscription.Schedule,
+		Timezone:   subscription.Timezone,
+		Window:     subscription.Window,
+		Channel:    subscription.Channel,
+		Recipients: subscription.Recipients,
+		Template:   subscription.Template,
+		Filter:     subscription.Filter,
+	}
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/vnFuhung2903/vcs-report-service/dto"
+	"github.com/vnFuhung2903/vcs-report-service/entities"
+	"github.com/vnFuhung2903/vcs-report-service/mocks/interfaces"
+	"github.com/vnFuhung2903/vcs-report-service/mocks/logger"
+	"github.com/vnFuhung2903/vcs-report-service/mocks/templates"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/env"
+)
+
+type SubscriptionServiceSuite struct {
+	suite.Suite
+	ctrl                *gomock.Controller
+	redisClient         *interfaces.MockIRedisClient
+	logger              *logger.MockILogger
+	templates           *templates.MockITemplateRegistry
+	subscriptionService ISubscriptionService
+	ctx                 context.Context
+	request             dto.SubscriptionRequest
+}
+
+func (s *SubscriptionServiceSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.redisClient = interfaces.NewMockIRedisClient(s.ctrl)
+	s.logger = logger.NewMockILogger(s.ctrl)
+	s.templates = templates.NewMockITemplateRegistry(s.ctrl)
+	s.templates.EXPECT().Has("").Return(true).AnyTimes()
+	s.subscriptionService = NewSubscriptionService(s.redisClient, s.logger, s.templates, env.ReportEnv{
+		Timezone:    "UTC",
+		Window:      "day",
+		TargetHosts: env.TargetHostsEnv{Slack: []string{"hooks.slack.com"}},
+	})
+	s.ctx = context.Background()
+	s.request = dto.SubscriptionRequest{
+		Name:       "weekday digest",
+		Schedule:   "0 8 * * MON-FRI",
+		Channel:    "email",
+		Recipients: []string{"user@example.com"},
+	}
+}
+
+func (s *SubscriptionServiceSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func TestSubscriptionServiceSuite(t *testing.T) {
+	suite.Run(t, new(SubscriptionServiceSuite))
+}
+
+func (s *SubscriptionServiceSuite) storedSubscription(id string, owner string) string {
+	subscription, _ := json.Marshal(entities.ReportSubscription{
+		Id:         id,
+		Owner:      owner,
+		Name:       "digest",
+		Schedule:   "0 0 * * *",
+		Timezone:   "UTC",
+		Window:     "day",
+		Channel:    "email",
+		Recipients: []string{owner + "@example.com"},
+		CreatedAt:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+	return string(subscription)
+}
+
+func (s *SubscriptionServiceSuite) TestCreate() {
+	var stored string
+	s.redisClient.EXPECT().
+		HSet(s.ctx, "report_subscriptions", gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, key string, field string, value string) error {
+			stored = value
+			return nil
+		})
+	s.logger.EXPECT().Info("subscription created successfully", gomock.Any(), gomock.Any()).Times(1)
+
+	subscription, err := s.subscriptionService.Create(s.ctx, "user-1", s.request)
+	s.NoError(err)
+	s.NotEmpty(subscription.Id)
+	s.Equal("user-1", subscription.Owner)
+	s.Equal("UTC", subscription.Timezone)
+	s.Equal("day", subscription.Window)
+	s.False(subscription.CreatedAt.IsZero())
+
+	var decoded entities.ReportSubscription
+	s.NoError(json.Unmarshal([]byte(stored), &decoded))
+	s.Equal(subscription.Id, decoded.Id)
+	s.Equal([]string{"user@example.com"}, decoded.Recipients)
+}
+
+func (s *SubscriptionServiceSuite) TestCreateInvalidSchedule() {
+	s.request.Schedule = "every morning"
+
+	_, err := s.subscriptionService.Create(s.ctx, "user-1", s.request)
+	s.ErrorIs(err, ErrInvalidSubscription)
+}
+
+func (s *SubscriptionServiceSuite) TestCreateInvalidTarget() {
+	s.request.Channel = "slack"
+	s.request.Recipients = []string{"https://169.254.169.254/latest/meta-data"}
+
+	_, err := s.subscriptionService.Create(s.ctx, "user-1", s.request)
+	s.ErrorIs(err, ErrInvalidSubscription)
+	s.ErrorContains(err, "invalid report target")
+}
+
+func (s *SubscriptionServiceSuite) TestCreateWithTemplate() {
+	s.request.Template = "executive"
+	s.templates.EXPECT().Has("executive").Return(true)
+	s.redisClient.EXPECT().HSet(s.ctx, "report_subscriptions", gomock.Any(), gomock.Any()).Return(nil)
+	s.logger.EXPECT().Info("subscription created successfully", gomock.Any(), gomock.Any()).Times(1)
+
+	subscription, err := s.subscriptionService.Create(s.ctx, "user-1", s.request)
+	s.NoError(err)
+	s.Equal("executive", subscription.Template)
+	s.Equal("executive", SubscriptionSchedule(subscription).Template)
+}
+
+func (s *SubscriptionServiceSuite) TestCreateWithFilter() {
+	s.request.Filter = entities.ContainerFilter{Ids: []string{"container1"}, Labels: map[string]string{"team": "payments"}}
+	s.redisClient.EXPECT().HSet(s.ctx, "report_subscriptions", gomock.Any(), gomock.Any()).Return(nil)
+	s.logger.EXPECT().Info("subscription created successfully", gomock.Any(), gomock.Any()).Times(1)
+
+	subscription, err := s.subscriptionService.Create(s.ctx, "user-1", s.request)
+	s.NoError(err)
+	s.Equal(s.request.Filter, subscription.Filter)
+	s.Equal(s.request.Filter, SubscriptionSchedule(subscription).Filter)
+
+	s.request.Filter = entities.ContainerFilter{Pattern: "api-["}
+	_, err = s.subscriptionService.Create(s.ctx, "user-1", s.request)
+	s.ErrorIs(err, ErrInvalidSubscription)
+	s.ErrorContains(err, "invalid container filter")
+}
+
+func (s *SubscriptionServiceSuite) TestCreateUnknownTemplate() {
+	s.request.Template = "weekly"
+	s.templates.EXPECT().Has("weekly").Return(false)
+
+	_, err := s.subscriptionService.Create(s.ctx, "user-1", s.request)
+	s.ErrorIs(err, ErrInvalidSubscription)
+	s.ErrorContains(err, `report template "weekly" does not exist`)
+}
+
+func (s *SubscriptionServiceSuite) TestCreateRedisError() {
+	s.redisClient.EXPECT().
+		HSet(s.ctx, "report_subscriptions", gomock.Any(), gomock.Any()).
+		Return(errors.New("redis connection failed"))
+	s.logger.EXPECT().Error("failed to save subscription to redis", gomock.Any()).Times(1)
+
+	_, err := s.subscriptionService.Create(s.ctx, "user-1", s.request)
+	s.EqualError(err, "redis connection failed")
+}
+
+func (s *SubscriptionServiceSuite) TestGet() {
+	s.redisClient.EXPECT().
+		HGet(s.ctx, "report_subscriptions", "sub-1").
+		Return(s.storedSubscription("sub-1", "user-1"), nil)
+
+	subscription, err := s.subscriptionService.Get(s.ctx, "user-1", "sub-1")
+	s.NoError(err)
+	s.Equal("sub-1", subscription.Id)
+}
+
+func (s *SubscriptionServiceSuite) TestGetNotFound() {
+	s.redisClient.EXPECT().
+		HGet(s.ctx, "report_subscriptions", "missing").
+		Return("", nil)
+
+	_, err := s.subscriptionService.Get(s.ctx, "user-1", "missing")
+	s.ErrorIs(err, ErrSubscriptionNotFound)
+
+	s.redisClient.EXPECT().
+		HGet(s.ctx, "report_subscriptions", "sub-2").
+		Return(s.storedSubscription("sub-2", "user-2"), nil)
+
+	_, err = s.subscriptionService.Get(s.ctx, "user-1", "sub-2")
+	s.ErrorIs(err, ErrSubscriptionNotFound)
+}
+
+func (s *SubscriptionServiceSuite) TestList() {
+	s.redisClient.EXPECT().
+		HGetAll(s.ctx, "report_subscriptions").
+		Return(map[string]string{
+			"sub-1": s.storedSubscription("sub-1", "user-1"),
+			"sub-2": s.storedSubscription("sub-2", "user-2"),
+			"sub-3": "{invalid",
+		}, nil)
+	s.logger.EXPECT().Warn("skipping undecodable subscription", gomock.Any(), gomock.Any()).Times(1)
+
+	subscriptions, err := s.subscriptionService.List(s.ctx, "user-1")
+	s.NoError(err)
+	s.Len(subscriptions, 1)
+	s.Equal("sub-1", subscriptions[0].Id)
+}
+
+func (s *SubscriptionServiceSuite) TestListAllRedisError() {
+	s.redisClient.EXPECT().
+		HGetAll(s.ctx, "report_subscriptions").
+		Return(nil, errors.New("redis connection failed"))
+	s.logger.EXPECT().Error("failed to list subscriptions from redis", gomock.Any()).Times(1)
+
+	subscriptions, err := s.subscriptionService.ListAll(s.ctx)
+	s.Error(err)
+	s.Nil(subscriptions)
+}
+
+func (s *SubscriptionServiceSuite) TestUpdate() {
+	s.redisClient.EXPECT().
+		HGet(s.ctx, "report_subscriptions", "sub-1").
+		Return(s.storedSubscription("sub-1", "user-1"), nil)
+	s.redisClient.EXPECT().
+		HSet(s.ctx, "report_subscriptions", "sub-1", gomock.Any()).
+		Return(nil)
+	s.logger.EXPECT().Info("subscription updated successfully", gomock.Any(), gomock.Any()).Times(1)
+
+	s.request.Window = "12h"
+	subscription, err := s.subscriptionService.Update(s.ctx, "user-1", "sub-1", s.request)
+	s.NoError(err)
+	s.Equal("0 8 * * MON-FRI", subscription.Schedule)
+	s.Equal("12h", subscription.Window)
+	s.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), subscription.CreatedAt)
+	s.True(subscription.UpdatedAt.After(subscription.CreatedAt))
+}
+
+func (s *SubscriptionServiceSuite) TestDelete() {
+	s.redisClient.EXPECT().
+		HGet(s.ctx, "report_subscriptions", "sub-1").
+		Return(s.storedSubscription("sub-1", "user-1"), nil)
+	s.redisClient.EXPECT().
+		HDel(s.ctx, "report_subscriptions", "sub-1").
+		Return(nil)
+	s.logger.EXPECT().Info("subscription deleted successfully", gomock.Any(), gomock.Any()).Times(1)
+
+	err := s.subscriptionService.Delete(s.ctx, "user-1", "sub-1")
+	s.NoError(err)
+}
+
+func (s *SubscriptionServiceSuite) TestDeleteNotFound() {
+	s.redisClient.EXPECT().
+		HGet(s.ctx, "report_subscriptions", "sub-2").
+		Return(s.storedSubscription("sub-2", "user-2"), nil)
+
+	err := s.subscriptionService.Delete(s.ctx, "user-1", "sub-2")
+	s.ErrorIs(err, ErrSubscriptionNotFound)
+}
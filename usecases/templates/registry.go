@@ -0,0 +1,261 @@
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/vnFuhung2903/vcs-report-service/dto"
+	"github.com/vnFuhung2903/vcs-report-service/entities"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/charts"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/i18n"
+)
+
+const (
+	TemplateDetailed  = "detailed"
+	TemplateExecutive = "executive"
+	TemplateOnCall    = "oncall"
+	DefaultTemplate   = TemplateDetailed
+)
+
+var ErrTemplateNotFound = errors.New("template not found")
+
+//go:embed defaults/*.html defaults/*.txt defaults/*.tmpl
+var defaults embed.FS
+
+// textFuncs are available to both versions of every template and format
+// text, numbers and dates for the recipient through localizer.
+func textFuncs(localizer i18n.Localizer) texttemplate.FuncMap {
+	return texttemplate.FuncMap{
+		"t":              localizer.T,
+		"locale":         localizer.Locale,
+		"formatTime":     localizer.Date,
+		"formatDateTime": localizer.DateTime,
+		"formatInt":      localizer.Integer,
+		"formatPercent":  localizer.Percent,
+		"formatNumber": func(value float64) string {
+			return localizer.Number(value, 2)
+		},
+		"upper":          strings.ToUpper,
+		"needsAttention": needsAttention,
+	}
+}
+
+// htmlFuncs extend textFuncs for the HTML version: t escapes its arguments
+// unless they are already HTML, strong emphasises an argument of t, and charts
+// render as inline SVG labelled through localizer.
+func htmlFuncs(localizer i18n.Localizer) htmltemplate.FuncMap {
+	funcs := htmltemplate.FuncMap(textFuncs(localizer))
+	funcs["t"] = func(key string, args ...any) htmltemplate.HTML {
+		message := htmltemplate.HTMLEscapeString(localizer.T(key))
+		if len(args) == 0 {
+			return htmltemplate.HTML(message)
+		}
+		escaped := make([]any, len(args))
+		for i, arg := range args {
+			if html, ok := arg.(htmltemplate.HTML); ok {
+				escaped[i] = html
+			} else {
+				escaped[i] = htmltemplate.HTMLEscapeString(fmt.Sprint(arg))
+			}
+		}
+		return htmltemplate.HTML(fmt.Sprintf(message, escaped...))
+	}
+	funcs["strong"] = func(value string) htmltemplate.HTML {
+		return htmltemplate.HTML("<strong>" + htmltemplate.HTMLEscapeString(value) + "</strong>")
+	}
+	funcs["fleetChart"] = func(report dto.ReportResponse) htmltemplate.HTML {
+		return charts.FleetBar(report, localizer)
+	}
+	funcs["availabilityHistogram"] = func(report dto.ReportResponse) htmltemplate.HTML {
+		return charts.AvailabilityHistogram(report, localizer)
+	}
+	funcs["timelineChart"] = func(report dto.ReportResponse) htmltemplate.HTML {
+		return charts.Timelines(report, localizer)
+	}
+	return funcs
+}
+
+type ITemplateRegistry interface {
+	Names() []string
+	Has(name string) bool
+	Render(name string, report dto.ReportResponse, localizer i18n.Localizer) (string, string, error)
+}
+
+type emailTemplate struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+type templateRegistry struct {
+	templates map[string]emailTemplate
+}
+
+// NewTemplateRegistry parses every report email template once. A template
+// named <name> is made of <name>.html and <name>.txt. Partials shared by the
+// HTML versions, such as the "styles" every default template includes, live
+// in .tmpl files and are parsed with each of them. The defaults are embedded
+// in the binary; when dir is set, its files replace the embedded ones of the
+// same name and may add new templates, which then need both files.
+func NewTemplateRegistry(dir string) (ITemplateRegistry, error) {
+	sources := make(map[string]map[string]string)
+	partials := make(map[string]string)
+
+	// Templates are parsed against the default localizer and rebound to the
+	// recipient's one on every render.
+	localizer, err := i18n.NewLocalizer(i18n.DefaultLocale, "UTC")
+	if err != nil {
+		return nil, err
+	}
+
+	embedded, err := fs.Sub(defaults, "defaults")
+	if err != nil {
+		return nil, err
+	}
+	if err := collectSources(embedded, sources, partials); err != nil {
+		return nil, err
+	}
+	if dir != "" {
+		if err := collectSources(os.DirFS(dir), sources, partials); err != nil {
+			return nil, fmt.Errorf("failed to read template directory: %w", err)
+		}
+	}
+	partialNames := make([]string, 0, len(partials))
+	for name := range partials {
+		partialNames = append(partialNames, name)
+	}
+	sort.Strings(partialNames)
+
+	registry := &templateRegistry{templates: make(map[string]emailTemplate, len(sources))}
+	for name, files := range sources {
+		htmlSource, ok := files[".html"]
+		if !ok {
+			return nil, fmt.Errorf("template %q has no html version", name)
+		}
+		textSource, ok := files[".txt"]
+		if !ok {
+			return nil, fmt.Errorf("template %q has no text version", name)
+		}
+
+		html := htmltemplate.New(name).Funcs(htmlFuncs(localizer))
+		for _, partial := range partialNames {
+			if _, err := html.New(partial).Parse(partials[partial]); err != nil {
+				return nil, fmt.Errorf("failed to parse partial %q: %w", partial, err)
+			}
+		}
+		if _, err := html.Parse(htmlSource); err != nil {
+			return nil, fmt.Errorf("failed to parse template %q: %w", name, err)
+		}
+		text, err := texttemplate.New(name).Funcs(textFuncs(localizer)).Parse(textSource)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse template %q: %w", name, err)
+		}
+		registry.templates[name] = emailTemplate{html: html, text: text}
+	}
+	return registry, nil
+}
+
+func (r *templateRegistry) Names() []string {
+	names := make([]string, 0, len(r.templates))
+	for name := range r.templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Has reports whether name can be rendered. An empty name selects the
+// default template.
+func (r *templateRegistry) Has(name string) bool {
+	if name == "" {
+		name = DefaultTemplate
+	}
+	_, ok := r.templates[name]
+	return ok
+}
+
+// Render returns the HTML and the plain-text version of report rendered with
+// the template called name, or with the default template when name is empty,
+// translated and formatted through localizer.
+func (r *templateRegistry) Render(name string, report dto.ReportResponse, localizer i18n.Localizer) (string, string, error) {
+	if name == "" {
+		name = DefaultTemplate
+	}
+	template, ok := r.templates[name]
+	if !ok {
+		return "", "", fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+
+	// The parsed templates are never executed themselves so that they can
+	// keep being cloned with another recipient's funcs.
+	htmlTemplate, err := template.html.Clone()
+	if err != nil {
+		return "", "", err
+	}
+	textTemplate, err := template.text.Clone()
+	if err != nil {
+		return "", "", err
+	}
+
+	var html, text bytes.Buffer
+	if err := htmlTemplate.Funcs(htmlFuncs(localizer)).Execute(&html, report); err != nil {
+		return "", "", err
+	}
+	if err := textTemplate.Funcs(textFuncs(localizer)).Execute(&text, report); err != nil {
+		return "", "", err
+	}
+	return html.String(), text.String(), nil
+}
+
+// collectSources adds the template files found at the root of fsys to
+// sources, keyed by template name then extension, and the partials to
+// partials, keyed by name.
+func collectSources(fsys fs.FS, sources map[string]map[string]string, partials map[string]string) error {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		ext := path.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".html" && ext != ".txt" && ext != ".tmpl") {
+			continue
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(entry.Name(), ext)
+		if ext == ".tmpl" {
+			partials[name] = string(content)
+			continue
+		}
+		if sources[name] == nil {
+			sources[name] = make(map[string]string)
+		}
+		sources[name][ext] = string(content)
+	}
+	return nil
+}
+
+// needsAttention narrows report down to the containers that are off or
+// breached the SLA target, for templates aimed at whoever is on call.
+func needsAttention(report dto.ReportResponse) dto.ReportResponse {
+	containers := make([]dto.ContainerReport, 0, len(report.Containers))
+	for _, container := range report.Containers {
+		if container.Status == entities.ContainerOff || container.SLABreached {
+			containers = append(containers, container)
+		}
+	}
+	report.Containers = containers
+	return report
+}
@@ -0,0 +1,250 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/vnFuhung2903/vcs-report-service/dto"
+	"github.com/vnFuhung2903/vcs-report-service/entities"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/i18n"
+)
+
+type TemplateRegistrySuite struct {
+	suite.Suite
+	dir       string
+	report    dto.ReportResponse
+	localizer i18n.Localizer
+}
+
+func (s *TemplateRegistrySuite) SetupTest() {
+	s.dir = s.T().TempDir()
+	localizer, err := i18n.NewLocalizer(i18n.LocaleEnglish, "UTC")
+	s.Require().NoError(err)
+	s.localizer = localizer
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.report = dto.ReportResponse{
+		ContainerCount:    2,
+		ContainerOnCount:  1,
+		ContainerOffCount: 1,
+		TotalUptime:       30,
+		Availability:      62.5,
+		SLATarget:         99.9,
+		SLABreachedCount:  1,
+		StartTime:         startTime,
+		EndTime:           startTime.Add(24 * time.Hour),
+		Containers: []dto.ContainerReport{
+			{ContainerId: "api", Status: entities.ContainerOn, UptimeHours: 24, Availability: 100},
+			{ContainerId: "worker", Status: entities.ContainerOff, UptimeHours: 6, DowntimeHours: 18, Availability: 25, SLABreached: true},
+		},
+	}
+}
+
+func TestTemplateRegistrySuite(t *testing.T) {
+	suite.Run(t, new(TemplateRegistrySuite))
+}
+
+func (s *TemplateRegistrySuite) writeFile(name string, content string) {
+	s.Require().NoError(os.WriteFile(filepath.Join(s.dir, name), []byte(content), 0644))
+}
+
+func (s *TemplateRegistrySuite) TestDefaults() {
+	registry, err := NewTemplateRegistry("")
+	s.Require().NoError(err)
+
+	s.Equal([]string{TemplateDetailed, TemplateExecutive, TemplateOnCall}, registry.Names())
+	s.True(registry.Has(""))
+	s.True(registry.Has(TemplateExecutive))
+	s.False(registry.Has("weekly"))
+}
+
+func (s *TemplateRegistrySuite) TestDefaultsShareStyles() {
+	registry, err := NewTemplateRegistry("")
+	s.Require().NoError(err)
+
+	for _, name := range registry.Names() {
+		html, _, err := registry.Render(name, s.report, s.localizer)
+		s.NoError(err)
+		s.Contains(html, "box-sizing: border-box;", name)
+	}
+}
+
+func (s *TemplateRegistrySuite) TestRenderDetailed() {
+	registry, err := NewTemplateRegistry("")
+	s.Require().NoError(err)
+
+	html, text, err := registry.Render("", s.report, s.localizer)
+	s.NoError(err)
+	s.Contains(html, "Container Breakdown")
+	s.Contains(html, "<td>worker</td>")
+	s.Contains(html, `aria-label="Fleet Uptime"`)
+	s.Contains(text, "CONTAINER BREAKDOWN")
+	s.Contains(text, "worker (OFF) - SLA BREACHED")
+}
+
+func (s *TemplateRegistrySuite) TestRenderExecutive() {
+	registry, err := NewTemplateRegistry("")
+	s.Require().NoError(err)
+
+	html, text, err := registry.Render(TemplateExecutive, s.report, s.localizer)
+	s.NoError(err)
+	s.Contains(html, "Executive Summary")
+	s.Contains(html, `aria-label="Availability Distribution"`)
+	s.NotContains(html, "<td>worker</td>")
+	s.Contains(text, "SLA Breaches: 1")
+	s.NotContains(text, "worker")
+}
+
+func (s *TemplateRegistrySuite) TestRenderOnCall() {
+	registry, err := NewTemplateRegistry("")
+	s.Require().NoError(err)
+
+	html, text, err := registry.Render(TemplateOnCall, s.report, s.localizer)
+	s.NoError(err)
+	s.Contains(html, "<td>worker</td>")
+	s.NotContains(html, "<td>api</td>")
+	s.Contains(text, "worker (OFF) - SLA BREACHED")
+	s.NotContains(text, "api (ON)")
+
+	s.report.Containers = s.report.Containers[:1]
+	html, text, err = registry.Render(TemplateOnCall, s.report, s.localizer)
+	s.NoError(err)
+	s.Contains(html, "All Clear")
+	s.Contains(text, "ALL CLEAR")
+}
+
+func (s *TemplateRegistrySuite) TestRenderAggregatedHidesTransitions() {
+	registry, err := NewTemplateRegistry("")
+	s.Require().NoError(err)
+
+	for _, name := range []string{TemplateDetailed, TemplateOnCall} {
+		html, text, err := registry.Render(name, s.report, s.localizer)
+		s.NoError(err)
+		s.Contains(html, "Transitions", name)
+		s.Contains(text, "Transitions: 0", name)
+
+		s.report.Aggregated = true
+		html, text, err = registry.Render(name, s.report, s.localizer)
+		s.NoError(err)
+		s.NotContains(html, "Transitions", name)
+		s.NotContains(text, "Transitions", name)
+		s.Contains(text, "Availability: 25.00%", name)
+		s.report.Aggregated = false
+	}
+}
+
+func (s *TemplateRegistrySuite) TestRenderLocalized() {
+	registry, err := NewTemplateRegistry("")
+	s.Require().NoError(err)
+	localizer, err := i18n.NewLocalizer(i18n.LocaleVietnamese, "Asia/Ho_Chi_Minh")
+	s.Require().NoError(err)
+	s.report.TotalUptime = 1234.5
+
+	html, text, err := registry.Render("", s.report, localizer)
+	s.NoError(err)
+	s.Contains(html, `<html lang="vi">`)
+	s.Contains(html, "Báo cáo Phân tích Container")
+	s.Contains(html, "Kỳ báo cáo: 01/01/2024 07:00 +07 - 02/01/2024 07:00 +07")
+	s.Contains(html, "<strong>1</strong> trên <strong>2</strong> container đang hoạt động.")
+	s.Contains(html, "<strong>1.234,50</strong>")
+	s.Contains(html, `<span class="sla-breached">Vi phạm</span>`)
+	s.Contains(html, "Hoạt động 62,50% (1.234,50 giờ)")
+	s.Contains(text, "BÁO CÁO PHÂN TÍCH CONTAINER")
+	s.Contains(text, "Độ sẵn sàng: 62,50%")
+	s.Contains(text, "worker (OFF) - VI PHẠM SLA")
+}
+
+func (s *TemplateRegistrySuite) TestRenderEscapesMessageArguments() {
+	s.writeFile("weekly.html", `<p>{{ t "report.period" "<b>start</b>" (strong "a&b") }}</p>`)
+	s.writeFile("weekly.txt", `{{ t "report.period" "<b>start</b>" "a&b" }}`)
+
+	registry, err := NewTemplateRegistry(s.dir)
+	s.Require().NoError(err)
+
+	html, text, err := registry.Render("weekly", s.report, s.localizer)
+	s.NoError(err)
+	s.Equal("<p>Reporting Period: &lt;b&gt;start&lt;/b&gt; - <strong>a&amp;b</strong></p>", html)
+	s.Equal("Reporting Period: <b>start</b> - a&b", text)
+}
+
+func (s *TemplateRegistrySuite) TestRenderUnknownTemplate() {
+	registry, err := NewTemplateRegistry("")
+	s.Require().NoError(err)
+
+	_, _, err = registry.Render("weekly", s.report, s.localizer)
+	s.ErrorIs(err, ErrTemplateNotFound)
+}
+
+func (s *TemplateRegistrySuite) TestDirectoryOverridesDefaults() {
+	s.writeFile("detailed.html", "<p>{{ .ContainerCount }} containers</p>")
+	s.writeFile("weekly.html", "<p>Weekly {{ .StartTime | formatTime }}</p>")
+	s.writeFile("weekly.txt", "Weekly {{ .StartTime | formatTime }}")
+	s.writeFile("notes.md", "ignored")
+
+	registry, err := NewTemplateRegistry(s.dir)
+	s.Require().NoError(err)
+	s.Equal([]string{TemplateDetailed, TemplateExecutive, TemplateOnCall, "weekly"}, registry.Names())
+
+	html, text, err := registry.Render(TemplateDetailed, s.report, s.localizer)
+	s.NoError(err)
+	s.Equal("<p>2 containers</p>", html)
+	s.Contains(text, "CONTAINER ANALYTICS REPORT")
+
+	html, text, err = registry.Render("weekly", s.report, s.localizer)
+	s.NoError(err)
+	s.Equal("<p>Weekly 2024-01-01</p>", html)
+	s.Equal("Weekly 2024-01-01", text)
+}
+
+func (s *TemplateRegistrySuite) TestDirectoryOverridesPartials() {
+	s.writeFile("styles.tmpl", `{{ define "styles" }}body { color: red; }{{ end }}`)
+
+	registry, err := NewTemplateRegistry(s.dir)
+	s.Require().NoError(err)
+	s.Equal([]string{TemplateDetailed, TemplateExecutive, TemplateOnCall}, registry.Names())
+
+	html, _, err := registry.Render(TemplateOnCall, s.report, s.localizer)
+	s.NoError(err)
+	s.Contains(html, "body { color: red; }")
+	s.NotContains(html, "box-sizing: border-box;")
+}
+
+func (s *TemplateRegistrySuite) TestInvalidPartial() {
+	s.writeFile("styles.tmpl", `{{ define "styles" }}`)
+
+	_, err := NewTemplateRegistry(s.dir)
+	s.ErrorContains(err, `failed to parse partial "styles"`)
+}
+
+func (s *TemplateRegistrySuite) TestTemplateWithoutTextVersion() {
+	s.writeFile("weekly.html", "<p>Weekly</p>")
+
+	_, err := NewTemplateRegistry(s.dir)
+	s.EqualError(err, `template "weekly" has no text version`)
+}
+
+func (s *TemplateRegistrySuite) TestInvalidTemplate() {
+	s.writeFile("executive.txt", "{{ .ContainerCount")
+
+	_, err := NewTemplateRegistry(s.dir)
+	s.ErrorContains(err, `failed to parse template "executive"`)
+}
+
+func (s *TemplateRegistrySuite) TestMissingDirectory() {
+	_, err := NewTemplateRegistry(filepath.Join(s.dir, "missing"))
+	s.ErrorContains(err, "failed to read template directory")
+}
+
+func (s *TemplateRegistrySuite) TestRenderExecutionError() {
+	s.writeFile("weekly.html", "{{ .Unknown }}")
+	s.writeFile("weekly.txt", "")
+
+	registry, err := NewTemplateRegistry(s.dir)
+	s.Require().NoError(err)
+
+	_, _, err = registry.Render("weekly", s.report, s.localizer)
+	s.Error(err)
+}
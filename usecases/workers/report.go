@@ -2,51 +2,137 @@ package workers
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
+	"github.com/robfig/cron/v3"
 	"github.com/vnFuhung2903/vcs-report-service/dto"
+	"github.com/vnFuhung2903/vcs-report-service/entities"
+	"github.com/vnFuhung2903/vcs-report-service/interfaces"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/env"
 	"github.com/vnFuhung2903/vcs-report-service/pkg/logger"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/retry"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/timerange"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/notifiers"
 	"github.com/vnFuhung2903/vcs-report-service/usecases/services"
 	"go.uber.org/zap"
 )
 
+const (
+	WindowSchedule = "schedule"
+	WindowDay      = "day"
+)
+
+type ReportSchedule struct {
+	Name       string
+	Owner      string
+	Cron       cron.Schedule
+	Location   *time.Location
+	Window     string
+	Channel    string
+	Recipients []string
+	Template   string
+	Filter     entities.ContainerFilter
+}
+
+func NewReportSchedule(scheduleEnv env.ReportScheduleEnv) (ReportSchedule, error) {
+	schedule, err := cron.ParseStandard(scheduleEnv.Schedule)
+	if err != nil {
+		return ReportSchedule{}, err
+	}
+	location, err := time.LoadLocation(scheduleEnv.Timezone)
+	if err != nil {
+		return ReportSchedule{}, err
+	}
+
+	return ReportSchedule{
+		Name:       scheduleEnv.Name,
+		Owner:      entities.RunOwnerSystem,
+		Cron:       schedule,
+		Location:   location,
+		Window:     scheduleEnv.Window,
+		Channel:    scheduleEnv.Channel,
+		Recipients: scheduleEnv.Recipients,
+		Template:   scheduleEnv.Template,
+		Filter:     scheduleEnv.Filter,
+	}, nil
+}
+
 type IReportkWorker interface {
 	Start()
 	Stop()
 }
 
 type reportkWorker struct {
-	reportService services.IReportService
-	email         string
-	logger        logger.ILogger
-	interval      time.Duration
-	ctx           context.Context
-	cancel        context.CancelFunc
-	wg            *sync.WaitGroup
+	reportService       services.IReportService
+	runService          services.IReportRunService
+	deadLetterService   services.IDeadLetterService
+	notificationService notifiers.INotificationService
+	lock                interfaces.IRedisLock
+	lockTTL             time.Duration
+	maxCatchUp          int
+	logger              logger.ILogger
+	schedules           []ReportSchedule
+	subscriptionService services.ISubscriptionService
+	refreshInterval     time.Duration
+	subscriptions       map[string]subscriptionRun
+	ctx                 context.Context
+	cancel              context.CancelFunc
+	wg                  *sync.WaitGroup
+}
+
+type subscriptionRun struct {
+	updatedAt time.Time
+	cancel    context.CancelFunc
 }
 
 func NewReportkWorker(
 	reportService services.IReportService,
-	email string,
+	subscriptionService services.ISubscriptionService,
+	runService services.IReportRunService,
+	deadLetterService services.IDeadLetterService,
+	notificationService notifiers.INotificationService,
+	lock interfaces.IRedisLock,
 	logger logger.ILogger,
-	interval time.Duration,
+	schedules []ReportSchedule,
+	reportEnv env.ReportEnv,
 ) IReportkWorker {
+	for i := range schedules {
+		if schedules[i].Location == nil {
+			schedules[i].Location = time.UTC
+		}
+	}
 	ctx, cancel := context.WithCancel(context.Background())
 	return &reportkWorker{
-		reportService: reportService,
-		email:         email,
-		logger:        logger,
-		interval:      interval,
-		ctx:           ctx,
-		cancel:        cancel,
-		wg:            &sync.WaitGroup{},
+		reportService:       reportService,
+		runService:          runService,
+		deadLetterService:   deadLetterService,
+		notificationService: notificationService,
+		lock:                lock,
+		lockTTL:             reportEnv.LockTTL,
+		maxCatchUp:          reportEnv.MaxCatchUp,
+		logger:              logger,
+		schedules:           schedules,
+		subscriptionService: subscriptionService,
+		refreshInterval:     reportEnv.SubscriptionRefresh,
+		subscriptions:       make(map[string]subscriptionRun),
+		ctx:                 ctx,
+		cancel:              cancel,
+		wg:                  &sync.WaitGroup{},
 	}
 }
 
 func (w *reportkWorker) Start() {
-	w.wg.Add(1)
-	go w.run()
+	for _, schedule := range w.schedules {
+		w.wg.Add(1)
+		go w.run(w.ctx, schedule)
+	}
+
+	if w.subscriptionService != nil {
+		w.wg.Add(1)
+		go w.watchSubscriptions()
+	}
 }
 
 func (w *reportkWorker) Stop() {
@@ -54,50 +140,367 @@ func (w *reportkWorker) Stop() {
 	w.wg.Wait()
 }
 
-func (w *reportkWorker) run() {
+func (w *reportkWorker) watchSubscriptions() {
 	defer w.wg.Done()
 
-	ticker := time.NewTicker(w.interval)
+	ticker := time.NewTicker(w.refreshInterval)
 	defer ticker.Stop()
 
 	for {
+		w.syncSubscriptions()
+
 		select {
 		case <-w.ctx.Done():
-			w.logger.Info("daily report workers stopped")
 			return
 		case <-ticker.C:
-			w.report()
 		}
 	}
 }
 
-func (w *reportkWorker) report() {
-	endTime := time.Now()
-	startTime := endTime.Add(-w.interval)
+func (w *reportkWorker) syncSubscriptions() {
+	subscriptions, err := w.subscriptionService.ListAll(w.ctx)
+	if err != nil {
+		w.logger.Error("failed to refresh report subscriptions", zap.Error(err))
+		return
+	}
+
+	active := make(map[string]bool, len(subscriptions))
+	for _, subscription := range subscriptions {
+		active[subscription.Id] = true
+		if running, ok := w.subscriptions[subscription.Id]; ok {
+			if running.updatedAt.Equal(subscription.UpdatedAt) {
+				continue
+			}
+			running.cancel()
+			delete(w.subscriptions, subscription.Id)
+		}
+
+		scheduleEnv := services.SubscriptionSchedule(subscription)
+		scheduleEnv.Name = "subscription:" + subscription.Id
+		schedule, err := NewReportSchedule(scheduleEnv)
+		if err != nil {
+			w.logger.Warn("skipping invalid report subscription", zap.String("id", subscription.Id), zap.Error(err))
+			continue
+		}
+		schedule.Owner = subscription.Owner
+
+		ctx, cancel := context.WithCancel(w.ctx)
+		w.subscriptions[subscription.Id] = subscriptionRun{updatedAt: subscription.UpdatedAt, cancel: cancel}
+		w.wg.Add(1)
+		go w.run(ctx, schedule)
+	}
+
+	for id, running := range w.subscriptions {
+		if !active[id] {
+			running.cancel()
+			delete(w.subscriptions, id)
+		}
+	}
+}
+
+func (w *reportkWorker) run(ctx context.Context, schedule ReportSchedule) {
+	defer w.wg.Done()
+
+	w.catchUp(ctx, schedule)
+
+	for {
+		next := schedule.Cron.Next(time.Now().In(schedule.Location))
+		if next.IsZero() {
+			w.logger.Error("report schedule has no upcoming run", zap.String("schedule", schedule.Name))
+			return
+		}
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			w.logger.Info("report worker stopped", zap.String("schedule", schedule.Name))
+			return
+		case <-timer.C:
+			w.report(ctx, schedule, next, entities.RunTriggerSchedule)
+		}
+	}
+}
+
+// catchUp backfills the windows that came due while no replica was running,
+// keeping at most maxCatchUp of the most recent ones. Schedules that have
+// never been reported have nothing to catch up on.
+func (w *reportkWorker) catchUp(ctx context.Context, schedule ReportSchedule) {
+	if w.runService == nil || w.maxCatchUp == 0 {
+		return
+	}
+
+	lastReported, err := w.runService.LastReported(ctx, schedule.Name)
+	if err != nil {
+		w.logger.Error("failed to load last reported window", zap.String("schedule", schedule.Name), zap.Error(err))
+		return
+	}
+	if lastReported.IsZero() {
+		return
+	}
+
+	missed := schedule.MissedRuns(lastReported, time.Now())
+	if len(missed) > w.maxCatchUp {
+		w.logger.Warn("skipping missed report windows beyond catch-up limit",
+			zap.String("schedule", schedule.Name),
+			zap.Int("missed", len(missed)),
+			zap.Int("limit", w.maxCatchUp),
+		)
+		missed = missed[len(missed)-w.maxCatchUp:]
+	}
+
+	for _, firedAt := range missed {
+		if ctx.Err() != nil {
+			return
+		}
+		w.logger.Info("catching up missed report window", zap.String("schedule", schedule.Name), zap.Time("firedAt", firedAt))
+		w.report(ctx, schedule, firedAt, entities.RunTriggerCatchUp)
+	}
+}
+
+// report claims the run scheduled at firedAt before sending it so that only
+// one replica delivers each window. The lease is kept until it expires after a
+// successful run, which stops replicas with a slightly late clock from
+// claiming the same window again.
+func (w *reportkWorker) report(ctx context.Context, schedule ReportSchedule, firedAt time.Time, trigger entities.RunTrigger) {
+	if w.lock == nil {
+		w.send(ctx, schedule, firedAt, trigger, nil)
+		return
+	}
 
-	statusList, err := w.reportService.GetEsStatus(w.ctx, 10000, startTime, endTime, dto.Asc)
+	lease, err := w.lock.Acquire(ctx, reportLockKey(schedule, firedAt), w.lockTTL)
 	if err != nil {
-		w.logger.Error("failed to retrieve elasticsearch status", zap.Error(err))
+		w.logger.Error("failed to acquire report lease", zap.String("schedule", schedule.Name), zap.Error(err))
+		return
+	}
+	if lease == nil {
+		w.logger.Debug("scheduled report claimed by another instance", zap.String("schedule", schedule.Name), zap.Time("firedAt", firedAt))
 		return
 	}
 
-	overlapStatusList, err := w.reportService.GetEsStatus(w.ctx, 1, endTime, time.Now(), dto.Asc)
+	leaseCtx, cancel := context.WithCancel(ctx)
+	renewed := make(chan struct{})
+	go func() {
+		defer close(renewed)
+		w.renew(leaseCtx, cancel, schedule, lease)
+	}()
+
+	delivered := w.send(leaseCtx, schedule, firedAt, trigger, lease)
+	cancel()
+	<-renewed
+
+	if !delivered {
+		if err := w.lock.Release(context.WithoutCancel(ctx), lease); err != nil {
+			w.logger.Warn("failed to release report lease", zap.String("schedule", schedule.Name), zap.Error(err))
+		}
+	}
+}
+
+func (w *reportkWorker) renew(ctx context.Context, cancel context.CancelFunc, schedule ReportSchedule, lease *interfaces.Lease) {
+	ticker := time.NewTicker(w.lockTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.lock.Renew(ctx, lease, w.lockTTL); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				w.logger.Error("failed to renew report lease", zap.String("schedule", schedule.Name), zap.Int64("fencingToken", lease.Token), zap.Error(err))
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// send generates the report, notifies every recipient and records the run.
+// It stops early once ctx is cancelled, for instance because the lease
+// guarding the run was lost. The run and the reported marker are written under
+// lease, so a holder that lost it cannot overwrite the records of the replica
+// that took over. Deliveries themselves are not fenced: a recipient notified
+// just before the lease was lost may receive the window twice. The returned
+// value reports whether at least one recipient received the report, in which
+// case the window counts as reported.
+func (w *reportkWorker) send(ctx context.Context, schedule ReportSchedule, firedAt time.Time, trigger entities.RunTrigger, lease *interfaces.Lease) bool {
+	startTime, endTime, windowErr := schedule.ReportWindow(firedAt)
+	run := w.startRun(ctx, lease, entities.ReportRun{
+		Trigger:    trigger,
+		Owner:      schedule.Owner,
+		Schedule:   schedule.Name,
+		Channel:    schedule.Channel,
+		Recipients: schedule.Recipients,
+		Template:   schedule.Template,
+		Filter:     schedule.Filter,
+		StartTime:  startTime,
+		EndTime:    endTime,
+	})
+	if windowErr != nil {
+		w.logger.Error("failed to resolve scheduled report window", zap.String("schedule", schedule.Name), zap.String("window", schedule.Window), zap.Time("firedAt", firedAt), zap.Error(windowErr))
+		run.Error = windowErr.Error()
+		w.finishRun(ctx, lease, run, nil)
+		return false
+	}
+
+	report, err := w.reportService.GenerateReport(ctx, startTime, endTime, schedule.Filter)
 	if err != nil {
-		w.logger.Error("failed to retrieve elasticsearch status", zap.Error(err))
+		w.logger.Error("failed to generate scheduled report", zap.String("schedule", schedule.Name), zap.Error(err))
+		run.Error = err.Error()
+		w.finishRun(ctx, lease, run, nil)
+		w.deadLetter(ctx, run, schedule.Recipients, err)
+		return false
+	}
+	report.Template = schedule.Template
+
+	for i, recipient := range schedule.Recipients {
+		if ctx.Err() != nil {
+			w.logger.Warn("scheduled report aborted", zap.String("schedule", schedule.Name), zap.Error(ctx.Err()))
+			run.FailedRecipients = append(run.FailedRecipients, schedule.Recipients[i:]...)
+			run.Error = ctx.Err().Error()
+			w.deadLetter(ctx, run, schedule.Recipients[i:], ctx.Err())
+			break
+		}
+
+		if err := w.notificationService.Notify(ctx, schedule.Channel, recipient, report); err != nil {
+			w.logger.Error("failed to send scheduled report",
+				zap.String("schedule", schedule.Name),
+				zap.String("channel", schedule.Channel),
+				zap.String("recipient", recipient),
+				zap.Error(err),
+			)
+			run.FailedRecipients = append(run.FailedRecipients, recipient)
+			run.Error = err.Error()
+			w.deadLetter(ctx, run, []string{recipient}, err)
+			continue
+		}
+
+		w.logger.Info("scheduled report sent successfully",
+			zap.String("schedule", schedule.Name),
+			zap.String("channel", schedule.Channel),
+			zap.String("recipient", recipient),
+			zap.Time("start", startTime),
+			zap.Time("end", endTime),
+			zap.Int("onCount", report.ContainerOnCount),
+			zap.Int("offCount", report.ContainerOffCount),
+		)
+	}
+
+	w.finishRun(ctx, lease, run, &report)
+	if len(run.FailedRecipients) >= len(schedule.Recipients) {
+		return false
+	}
+
+	if w.runService != nil {
+		if err := w.runService.MarkReported(context.WithoutCancel(ctx), schedule.Name, endTime, lease); err != nil {
+			w.logger.Warn("failed to record reported window", zap.String("schedule", schedule.Name), zap.Error(err))
+		}
+	}
+	return true
+}
+
+func (w *reportkWorker) startRun(ctx context.Context, lease *interfaces.Lease, run entities.ReportRun) entities.ReportRun {
+	if w.runService == nil {
+		return run
+	}
+
+	run, err := w.runService.Start(ctx, run, lease)
+	if err != nil {
+		w.logger.Warn("failed to record report run", zap.String("schedule", run.Schedule), zap.Error(err))
+	}
+	return run
+}
+
+func (w *reportkWorker) finishRun(ctx context.Context, lease *interfaces.Lease, run entities.ReportRun, report *dto.ReportResponse) {
+	if w.runService == nil {
 		return
 	}
 
-	onCount, offCount, totalUptime := w.reportService.CalculateReportStatistic(statusList, overlapStatusList, startTime, endTime)
+	if _, err := w.runService.Finish(context.WithoutCancel(ctx), run, report, lease); err != nil {
+		w.logger.Warn("failed to record report run", zap.String("schedule", run.Schedule), zap.Error(err))
+	}
+}
 
-	if err := w.reportService.SendEmail(w.ctx, w.email, onCount+offCount, onCount, offCount, totalUptime, startTime, endTime); err != nil {
-		w.logger.Error("failed to email daily report", zap.Error(err))
+// deadLetter parks the deliveries of run to targets so that they can be
+// replayed once the cause of the failure is fixed, counting the attempts the
+// retry policy made before giving up with cause.
+func (w *reportkWorker) deadLetter(ctx context.Context, run entities.ReportRun, targets []string, cause error) {
+	if w.deadLetterService == nil {
 		return
 	}
 
-	w.logger.Info("daily report sent successfully",
-		zap.Time("start", startTime),
-		zap.Time("end", endTime),
-		zap.Int("onCount", onCount),
-		zap.Int("offCount", offCount),
-	)
+	for _, target := range targets {
+		_, err := w.deadLetterService.Park(context.WithoutCancel(ctx), entities.DeadLetter{
+			RunId:     run.Id,
+			Schedule:  run.Schedule,
+			Owner:     run.Owner,
+			Channel:   run.Channel,
+			Target:    target,
+			Template:  run.Template,
+			Filter:    run.Filter,
+			StartTime: run.StartTime,
+			EndTime:   run.EndTime,
+			Error:     cause.Error(),
+		}, retry.Attempts(cause))
+		if err != nil {
+			w.logger.Error("failed to park report delivery", zap.String("schedule", run.Schedule), zap.String("target", target), zap.Error(err))
+		}
+	}
+}
+
+func reportLockKey(schedule ReportSchedule, firedAt time.Time) string {
+	return fmt.Sprintf("report_lock:%s:%d", schedule.Name, firedAt.Unix())
+}
+
+// ReportWindow returns the period covered by the run scheduled at firedAt:
+// the previous calendar day for WindowDay, a fixed length ending at firedAt
+// when Window is a duration, a named or relative range such as "last_week" or
+// "now-7d/d" resolved against firedAt, otherwise everything since the
+// previous scheduled run. It fails when the range cannot be resolved at
+// firedAt.
+func (s ReportSchedule) ReportWindow(firedAt time.Time) (time.Time, time.Time, error) {
+	firedAt = firedAt.In(s.Location)
+	if s.Window == WindowDay {
+		endTime := time.Date(firedAt.Year(), firedAt.Month(), firedAt.Day(), 0, 0, 0, 0, s.Location)
+		return endTime.AddDate(0, 0, -1), endTime, nil
+	}
+	if length, err := time.ParseDuration(s.Window); err == nil && length > 0 {
+		return firedAt.Add(-length), firedAt, nil
+	}
+	if s.Window != WindowSchedule && s.Window != "" {
+		return timerange.Resolve(s.Window, firedAt, s.Location)
+	}
+	return s.previous(firedAt), firedAt, nil
+}
+
+// MissedRuns returns the run times up to now whose window ends after
+// lastReported, oldest first. Runs whose window cannot be resolved are kept so
+// that their failure gets recorded.
+func (s ReportSchedule) MissedRuns(lastReported time.Time, now time.Time) []time.Time {
+	var missed []time.Time
+	for firedAt := s.Cron.Next(lastReported.In(s.Location)); !firedAt.IsZero() && !firedAt.After(now); firedAt = s.Cron.Next(firedAt) {
+		if _, endTime, err := s.ReportWindow(firedAt); err != nil || endTime.After(lastReported) {
+			missed = append(missed, firedAt)
+		}
+	}
+	return missed
+}
+
+func (s ReportSchedule) previous(firedAt time.Time) time.Time {
+	lookback := time.Minute
+	candidate := firedAt.Add(-lookback)
+	for !s.Cron.Next(candidate).Before(firedAt) {
+		lookback *= 2
+		candidate = firedAt.Add(-lookback)
+	}
+
+	for {
+		next := s.Cron.Next(candidate)
+		if next.IsZero() || !next.Before(firedAt) {
+			return candidate
+		}
+		candidate = next
+	}
 }
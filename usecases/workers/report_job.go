@@ -0,0 +1,300 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vnFuhung2903/vcs-report-service/entities"
+	"github.com/vnFuhung2903/vcs-report-service/interfaces"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/env"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/logger"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/notifiers"
+	"github.com/vnFuhung2903/vcs-report-service/usecases/services"
+	"go.uber.org/zap"
+)
+
+const (
+	jobPollTimeout    = time.Second
+	jobWorkerLockName = "report_job_worker"
+)
+
+var errWorkerLeaseHeld = errors.New("report job worker lease is already held")
+
+type IReportJobWorker interface {
+	Start() error
+	Stop()
+}
+
+type reportJobWorker struct {
+	jobService          services.IReportJobService
+	reportService       services.IReportService
+	notificationService notifiers.INotificationService
+	runService          services.IReportRunService
+	lock                interfaces.IRedisLock
+	logger              logger.ILogger
+	id                  string
+	workers             int
+	leaseTTL            time.Duration
+	lease               *interfaces.Lease
+	ctx                 context.Context
+	cancel              context.CancelFunc
+	wg                  *sync.WaitGroup
+	heartbeatCancel     context.CancelFunc
+	heartbeatDone       chan struct{}
+}
+
+func NewReportJobWorker(
+	jobService services.IReportJobService,
+	reportService services.IReportService,
+	notificationService notifiers.INotificationService,
+	runService services.IReportRunService,
+	lock interfaces.IRedisLock,
+	logger logger.ILogger,
+	reportEnv env.ReportEnv,
+) IReportJobWorker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &reportJobWorker{
+		jobService:          jobService,
+		reportService:       reportService,
+		notificationService: notificationService,
+		runService:          runService,
+		lock:                lock,
+		logger:              logger,
+		id:                  uuid.NewString(),
+		workers:             max(reportEnv.JobWorkers, 1),
+		leaseTTL:            reportEnv.LockTTL,
+		ctx:                 ctx,
+		cancel:              cancel,
+		wg:                  &sync.WaitGroup{},
+	}
+}
+
+// Start registers the worker, requeues the jobs left in progress by workers
+// that died, then starts polling the queue. Every job taken is kept in the
+// processing list of the worker until it finished, and the worker proves it
+// is alive by holding a lease that it renews until Stop. The jobs of workers
+// that die later are recovered once their lease expired, by whichever worker
+// notices first. A worker without a lock neither registers nor recovers jobs.
+// Start fails when the worker cannot register, since its jobs could then never
+// be recovered.
+func (w *reportJobWorker) Start() error {
+	if w.lock != nil {
+		if err := w.register(); err != nil {
+			return err
+		}
+		w.recoverOrphans()
+
+		w.wg.Add(1)
+		go w.recoverPeriodically()
+	}
+
+	for range w.workers {
+		w.wg.Add(1)
+		go w.poll()
+	}
+	return nil
+}
+
+// Stop stops taking new jobs, waits for the jobs in progress to finish, then
+// unregisters the worker.
+func (w *reportJobWorker) Stop() {
+	w.cancel()
+	w.wg.Wait()
+
+	if w.lease == nil {
+		return
+	}
+	w.heartbeatCancel()
+	<-w.heartbeatDone
+
+	ctx := context.Background()
+	if _, err := w.jobService.Requeue(ctx, w.id); err != nil {
+		w.logger.Warn("failed to unregister report job worker", zap.String("worker", w.id), zap.Error(err))
+	}
+	if err := w.lock.Release(ctx, w.lease); err != nil {
+		w.logger.Warn("failed to release report job worker lease", zap.String("worker", w.id), zap.Error(err))
+	}
+}
+
+func (w *reportJobWorker) register() error {
+	lease, err := w.lock.Acquire(w.ctx, workerLockKey(w.id), w.leaseTTL)
+	if err == nil && lease == nil {
+		err = errWorkerLeaseHeld
+	}
+	if err != nil {
+		w.logger.Error("failed to acquire report job worker lease", zap.String("worker", w.id), zap.Error(err))
+		return err
+	}
+	if err := w.jobService.Register(w.ctx, w.id); err != nil {
+		if err := w.lock.Release(w.ctx, lease); err != nil {
+			w.logger.Warn("failed to release report job worker lease", zap.String("worker", w.id), zap.Error(err))
+		}
+		return err
+	}
+
+	w.lease = lease
+	ctx, cancel := context.WithCancel(context.Background())
+	w.heartbeatCancel = cancel
+	w.heartbeatDone = make(chan struct{})
+	go w.heartbeat(ctx)
+	return nil
+}
+
+// heartbeat renews the lease of the worker until ctx is done. It outlives the
+// pollers so that jobs still finishing during Stop are not requeued.
+func (w *reportJobWorker) heartbeat(ctx context.Context) {
+	defer close(w.heartbeatDone)
+	ticker := time.NewTicker(w.leaseTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.lock.Renew(ctx, w.lease, w.leaseTTL); err != nil && ctx.Err() == nil {
+				w.logger.Error("failed to renew report job worker lease", zap.String("worker", w.id), zap.Error(err))
+			}
+		}
+	}
+}
+
+// recoverPeriodically looks for workers whose lease expired once per lease
+// TTL, the soonest a dead worker can be noticed, until the worker stops.
+func (w *reportJobWorker) recoverPeriodically() {
+	defer w.wg.Done()
+	ticker := time.NewTicker(w.leaseTTL)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+			w.recoverOrphans()
+		}
+	}
+}
+
+// recoverOrphans requeues the jobs of every registered worker whose lease
+// expired. Taking over that lease first makes sure only one worker recovers
+// them.
+func (w *reportJobWorker) recoverOrphans() {
+	workers, err := w.jobService.Workers(w.ctx)
+	if err != nil {
+		return
+	}
+
+	for _, worker := range workers {
+		if worker == w.id {
+			continue
+		}
+		lease, err := w.lock.Acquire(w.ctx, workerLockKey(worker), w.leaseTTL)
+		if err != nil {
+			w.logger.Error("failed to acquire report job worker lease", zap.String("worker", worker), zap.Error(err))
+			continue
+		}
+		if lease == nil {
+			continue
+		}
+
+		requeued, err := w.jobService.Requeue(w.ctx, worker)
+		if err == nil {
+			w.logger.Info("requeued orphaned report jobs", zap.String("worker", worker), zap.Int("count", requeued))
+		}
+		if err := w.lock.Release(context.WithoutCancel(w.ctx), lease); err != nil {
+			w.logger.Warn("failed to release report job worker lease", zap.String("worker", worker), zap.Error(err))
+		}
+	}
+}
+
+func (w *reportJobWorker) poll() {
+	defer w.wg.Done()
+
+	for w.ctx.Err() == nil {
+		job, err := w.jobService.Dequeue(w.ctx, w.id, jobPollTimeout)
+		if err != nil {
+			select {
+			case <-w.ctx.Done():
+			case <-time.After(jobPollTimeout):
+			}
+			continue
+		}
+		if job == nil {
+			continue
+		}
+
+		ctx := context.WithoutCancel(w.ctx)
+		w.process(ctx, *job)
+		if err := w.jobService.Ack(ctx, w.id, job.Id); err != nil {
+			w.logger.Warn("failed to acknowledge report job", zap.String("job", job.Id), zap.Error(err))
+		}
+	}
+}
+
+// process generates the report of job, delivers it and stores the outcome,
+// with the recorded run attached as the job result.
+func (w *reportJobWorker) process(ctx context.Context, job entities.ReportJob) {
+	job.Status = entities.JobRunning
+	job.StartedAt = time.Now()
+	w.save(ctx, job)
+
+	run, err := w.runService.Start(ctx, entities.ReportRun{
+		Trigger:    entities.RunTriggerAPI,
+		Owner:      job.Owner,
+		Channel:    job.Channel,
+		Recipients: []string{job.Target},
+		Filter:     job.Filter,
+		StartTime:  job.StartTime,
+		EndTime:    job.EndTime,
+	}, nil)
+	if err != nil {
+		w.logger.Warn("failed to record report run", zap.String("job", job.Id), zap.Error(err))
+	}
+
+	report, err := w.reportService.GenerateReport(ctx, job.StartTime, job.EndTime, job.Filter)
+	if err != nil {
+		run.Error = err.Error()
+		run, _ = w.runService.Finish(ctx, run, nil, nil)
+		w.finish(ctx, job, run, err)
+		return
+	}
+
+	if err := w.notificationService.Notify(ctx, job.Channel, job.Target, report); err != nil {
+		run.FailedRecipients = []string{job.Target}
+		run.Error = err.Error()
+		run, _ = w.runService.Finish(ctx, run, &report, nil)
+		w.finish(ctx, job, run, err)
+		return
+	}
+
+	run, _ = w.runService.Finish(ctx, run, &report, nil)
+	w.finish(ctx, job, run, nil)
+}
+
+func (w *reportJobWorker) finish(ctx context.Context, job entities.ReportJob, run entities.ReportRun, cause error) {
+	job.Result = &run
+	job.FinishedAt = time.Now()
+	if cause != nil {
+		job.Status = entities.JobFailed
+		job.Error = cause.Error()
+		w.logger.Error("report job failed", zap.String("job", job.Id), zap.Error(cause))
+	} else {
+		job.Status = entities.JobSucceeded
+		w.logger.Info("report job completed successfully", zap.String("job", job.Id), zap.String("channel", job.Channel))
+	}
+	w.save(ctx, job)
+}
+
+func (w *reportJobWorker) save(ctx context.Context, job entities.ReportJob) {
+	if err := w.jobService.Save(ctx, job); err != nil {
+		w.logger.Warn("failed to update report job", zap.String("job", job.Id), zap.Error(err))
+	}
+}
+
+func workerLockKey(worker string) string {
+	return jobWorkerLockName + ":" + worker
+}
@@ -0,0 +1,318 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/suite"
+	"github.com/vnFuhung2903/vcs-report-service/dto"
+	"github.com/vnFuhung2903/vcs-report-service/entities"
+	"github.com/vnFuhung2903/vcs-report-service/interfaces"
+	mockInterfaces "github.com/vnFuhung2903/vcs-report-service/mocks/interfaces"
+	"github.com/vnFuhung2903/vcs-report-service/mocks/logger"
+	"github.com/vnFuhung2903/vcs-report-service/mocks/notifiers"
+	"github.com/vnFuhung2903/vcs-report-service/mocks/services"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/env"
+)
+
+type ReportJobWorkerSuite struct {
+	suite.Suite
+	ctrl              *gomock.Controller
+	mockJobService    *services.MockIReportJobService
+	mockReportService *services.MockIReportService
+	mockNotification  *notifiers.MockINotificationService
+	mockRunService    *services.MockIReportRunService
+	mockLogger        *logger.MockILogger
+	worker            *reportJobWorker
+	job               entities.ReportJob
+}
+
+func (s *ReportJobWorkerSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.mockJobService = services.NewMockIReportJobService(s.ctrl)
+	s.mockReportService = services.NewMockIReportService(s.ctrl)
+	s.mockNotification = notifiers.NewMockINotificationService(s.ctrl)
+	s.mockRunService = services.NewMockIReportRunService(s.ctrl)
+	s.mockLogger = logger.NewMockILogger(s.ctrl)
+	s.worker = NewReportJobWorker(s.mockJobService, s.mockReportService, s.mockNotification, s.mockRunService, nil, s.mockLogger, env.ReportEnv{JobWorkers: 1}).(*reportJobWorker)
+
+	s.job = entities.ReportJob{
+		Id:        "job-1",
+		Owner:     "user-1",
+		Channel:   "email",
+		Target:    "ops@example.com",
+		StartTime: time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC),
+		Status:    entities.JobQueued,
+	}
+}
+
+func (s *ReportJobWorkerSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func TestReportJobWorkerSuite(t *testing.T) {
+	suite.Run(t, new(ReportJobWorkerSuite))
+}
+
+// expectJob expects the job to be marked running and then finished with
+// status, returning a channel that receives the finished job.
+func (s *ReportJobWorkerSuite) expectJob(status entities.JobStatus) chan entities.ReportJob {
+	finished := make(chan entities.ReportJob, 1)
+	gomock.InOrder(
+		s.mockJobService.EXPECT().
+			Save(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, job entities.ReportJob) error {
+				s.Equal(entities.JobRunning, job.Status)
+				s.False(job.StartedAt.IsZero())
+				return nil
+			}),
+		s.mockJobService.EXPECT().
+			Save(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, job entities.ReportJob) error {
+				s.Equal(status, job.Status)
+				finished <- job
+				return nil
+			}),
+	)
+
+	s.mockRunService.EXPECT().
+		Start(gomock.Any(), gomock.Any(), nil).
+		DoAndReturn(func(ctx context.Context, run entities.ReportRun, lease *interfaces.Lease) (entities.ReportRun, error) {
+			s.Equal(entities.RunTriggerAPI, run.Trigger)
+			s.Equal("user-1", run.Owner)
+			s.Equal([]string{"ops@example.com"}, run.Recipients)
+			run.Id = "run-1"
+			return run, nil
+		})
+	s.mockRunService.EXPECT().
+		Finish(gomock.Any(), gomock.Any(), gomock.Any(), nil).
+		DoAndReturn(func(ctx context.Context, run entities.ReportRun, report *dto.ReportResponse, lease *interfaces.Lease) (entities.ReportRun, error) {
+			if status == entities.JobFailed {
+				run.Status = entities.RunFailed
+			} else {
+				run.Status = entities.RunSucceeded
+			}
+			return run, nil
+		})
+	return finished
+}
+
+func (s *ReportJobWorkerSuite) TestProcessesQueuedJobs() {
+	report := dto.ReportResponse{ContainerCount: 2}
+	job := s.job
+	s.mockJobService.EXPECT().Dequeue(gomock.Any(), s.worker.id, jobPollTimeout).Return(&job, nil)
+	s.mockJobService.EXPECT().
+		Dequeue(gomock.Any(), s.worker.id, jobPollTimeout).
+		DoAndReturn(func(ctx context.Context, worker string, timeout time.Duration) (*entities.ReportJob, error) {
+			<-ctx.Done()
+			return nil, nil
+		}).
+		AnyTimes()
+	finished := s.expectJob(entities.JobSucceeded)
+	s.mockReportService.EXPECT().GenerateReport(gomock.Any(), s.job.StartTime, s.job.EndTime, entities.ContainerFilter{}).Return(report, nil)
+	s.mockNotification.EXPECT().Notify(gomock.Any(), "email", "ops@example.com", report).Return(nil)
+	s.mockLogger.EXPECT().Info("report job completed successfully", gomock.Any(), gomock.Any()).Times(1)
+	s.mockJobService.EXPECT().Ack(gomock.Any(), s.worker.id, "job-1").Return(nil)
+
+	s.Require().NoError(s.worker.Start())
+	select {
+	case job := <-finished:
+		s.Require().NotNil(job.Result)
+		s.Equal("run-1", job.Result.Id)
+		s.Equal(entities.RunSucceeded, job.Result.Status)
+		s.False(job.FinishedAt.IsZero())
+	case <-time.After(2 * time.Second):
+		s.Fail("job was not processed")
+	}
+	s.worker.Stop()
+}
+
+func (s *ReportJobWorkerSuite) TestDequeueErrorBacksOff() {
+	calls := 0
+	s.mockJobService.EXPECT().
+		Dequeue(gomock.Any(), s.worker.id, jobPollTimeout).
+		DoAndReturn(func(ctx context.Context, worker string, timeout time.Duration) (*entities.ReportJob, error) {
+			calls++
+			return nil, errors.New("redis connection failed")
+		}).
+		AnyTimes()
+
+	s.Require().NoError(s.worker.Start())
+	time.Sleep(100 * time.Millisecond)
+	s.worker.Stop()
+
+	s.Equal(1, calls)
+}
+
+// blockDequeue makes every poll wait until the worker stops.
+func (s *ReportJobWorkerSuite) blockDequeue(worker string) {
+	s.mockJobService.EXPECT().
+		Dequeue(gomock.Any(), worker, jobPollTimeout).
+		DoAndReturn(func(ctx context.Context, worker string, timeout time.Duration) (*entities.ReportJob, error) {
+			<-ctx.Done()
+			return nil, nil
+		}).
+		AnyTimes()
+}
+
+func (s *ReportJobWorkerSuite) TestStartRecoversOrphanedJobs() {
+	miniRedis, err := miniredis.Run()
+	s.Require().NoError(err)
+	defer miniRedis.Close()
+	redisClient := redis.NewClient(&redis.Options{Addr: miniRedis.Addr()})
+	defer redisClient.Close()
+
+	lock := interfaces.NewRedisLock(redisClient)
+	worker := NewReportJobWorker(s.mockJobService, s.mockReportService, s.mockNotification, s.mockRunService, lock, s.mockLogger, env.ReportEnv{JobWorkers: 1, LockTTL: time.Minute}).(*reportJobWorker)
+	alive, err := lock.Acquire(context.Background(), workerLockKey("live-worker"), time.Minute)
+	s.Require().NoError(err)
+	s.Require().NotNil(alive)
+
+	s.mockJobService.EXPECT().Register(gomock.Any(), worker.id).Return(nil)
+	s.mockJobService.EXPECT().Workers(gomock.Any()).Return([]string{"dead-worker", "live-worker", worker.id}, nil)
+	s.mockJobService.EXPECT().Requeue(gomock.Any(), "dead-worker").Return(2, nil)
+	s.mockLogger.EXPECT().Info("requeued orphaned report jobs", gomock.Any(), gomock.Any()).Times(1)
+	s.blockDequeue(worker.id)
+
+	s.Require().NoError(worker.Start())
+	s.True(miniRedis.Exists(workerLockKey(worker.id)))
+	s.False(miniRedis.Exists(workerLockKey("dead-worker")))
+
+	s.mockJobService.EXPECT().Requeue(gomock.Any(), worker.id).Return(0, nil)
+	worker.Stop()
+	s.False(miniRedis.Exists(workerLockKey(worker.id)))
+	s.True(miniRedis.Exists(workerLockKey("live-worker")))
+}
+
+func (s *ReportJobWorkerSuite) TestRecoversWorkersThatDieLater() {
+	miniRedis, err := miniredis.Run()
+	s.Require().NoError(err)
+	defer miniRedis.Close()
+	redisClient := redis.NewClient(&redis.Options{Addr: miniRedis.Addr()})
+	defer redisClient.Close()
+
+	lock := interfaces.NewRedisLock(redisClient)
+	worker := NewReportJobWorker(s.mockJobService, s.mockReportService, s.mockNotification, s.mockRunService, lock, s.mockLogger, env.ReportEnv{JobWorkers: 1, LockTTL: 100 * time.Millisecond}).(*reportJobWorker)
+	peer, err := lock.Acquire(context.Background(), workerLockKey("peer-worker"), time.Hour)
+	s.Require().NoError(err)
+	s.Require().NotNil(peer)
+	requeued := make(chan struct{})
+
+	s.mockJobService.EXPECT().Register(gomock.Any(), worker.id).Return(nil)
+	s.mockJobService.EXPECT().Workers(gomock.Any()).Return([]string{"peer-worker", worker.id}, nil).MinTimes(1)
+	s.mockJobService.EXPECT().
+		Requeue(gomock.Any(), "peer-worker").
+		DoAndReturn(func(ctx context.Context, worker string) (int, error) {
+			close(requeued)
+			return 1, nil
+		})
+	s.mockLogger.EXPECT().Info("requeued orphaned report jobs", gomock.Any(), gomock.Any()).Times(1)
+	s.blockDequeue(worker.id)
+
+	s.Require().NoError(worker.Start())
+	// The peer stops renewing its heartbeat after the worker started.
+	miniRedis.Del(workerLockKey("peer-worker"))
+	select {
+	case <-requeued:
+	case <-time.After(2 * time.Second):
+		s.Fail("jobs of the dead worker were not recovered")
+	}
+
+	s.mockJobService.EXPECT().Requeue(gomock.Any(), worker.id).Return(0, nil)
+	worker.Stop()
+}
+
+func (s *ReportJobWorkerSuite) TestStartFailsWhenRegisterFails() {
+	mockLock := mockInterfaces.NewMockIRedisLock(s.ctrl)
+	worker := NewReportJobWorker(s.mockJobService, s.mockReportService, s.mockNotification, s.mockRunService, mockLock, s.mockLogger, env.ReportEnv{JobWorkers: 1, LockTTL: time.Minute}).(*reportJobWorker)
+	lease := &interfaces.Lease{Key: workerLockKey(worker.id), Token: 7}
+
+	mockLock.EXPECT().Acquire(gomock.Any(), lease.Key, time.Minute).Return(lease, nil)
+	s.mockJobService.EXPECT().Register(gomock.Any(), worker.id).Return(errors.New("redis connection failed"))
+	mockLock.EXPECT().Release(gomock.Any(), lease).Return(nil)
+	s.Error(worker.Start())
+
+	mockLock.EXPECT().Acquire(gomock.Any(), lease.Key, time.Minute).Return(nil, errors.New("redis connection failed"))
+	s.mockLogger.EXPECT().Error("failed to acquire report job worker lease", gomock.Any(), gomock.Any()).Times(1)
+	s.Error(worker.Start())
+
+	worker.Stop()
+}
+
+func (s *ReportJobWorkerSuite) TestHeartbeatRenewsLease() {
+	mockLock := mockInterfaces.NewMockIRedisLock(s.ctrl)
+	worker := NewReportJobWorker(s.mockJobService, s.mockReportService, s.mockNotification, s.mockRunService, mockLock, s.mockLogger, env.ReportEnv{JobWorkers: 1, LockTTL: 30 * time.Millisecond}).(*reportJobWorker)
+	lease := &interfaces.Lease{Key: workerLockKey(worker.id), Token: 7}
+	renewed := make(chan struct{}, 1)
+
+	mockLock.EXPECT().Acquire(gomock.Any(), lease.Key, 30*time.Millisecond).Return(lease, nil)
+	s.mockJobService.EXPECT().Register(gomock.Any(), worker.id).Return(nil)
+	s.mockJobService.EXPECT().Workers(gomock.Any()).Return([]string{worker.id}, nil).MinTimes(1)
+	s.blockDequeue(worker.id)
+	mockLock.EXPECT().
+		Renew(gomock.Any(), lease, 30*time.Millisecond).
+		DoAndReturn(func(ctx context.Context, lease *interfaces.Lease, ttl time.Duration) error {
+			select {
+			case renewed <- struct{}{}:
+			default:
+			}
+			return nil
+		}).
+		MinTimes(1)
+
+	s.Require().NoError(worker.Start())
+	select {
+	case <-renewed:
+	case <-time.After(time.Second):
+		s.Fail("worker lease was not renewed")
+	}
+
+	s.mockJobService.EXPECT().Requeue(gomock.Any(), worker.id).Return(0, errors.New("redis connection failed"))
+	s.mockLogger.EXPECT().Warn("failed to unregister report job worker", gomock.Any(), gomock.Any()).Times(1)
+	mockLock.EXPECT().Release(gomock.Any(), lease).Return(nil)
+	worker.Stop()
+}
+
+func (s *ReportJobWorkerSuite) TestProcessGenerateReportError() {
+	finished := s.expectJob(entities.JobFailed)
+	s.mockReportService.EXPECT().GenerateReport(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(dto.ReportResponse{}, errors.New("elasticsearch unavailable"))
+	s.mockLogger.EXPECT().Error("report job failed", gomock.Any(), gomock.Any()).Times(1)
+
+	s.worker.process(context.Background(), s.job)
+
+	job := <-finished
+	s.Equal("elasticsearch unavailable", job.Error)
+	s.Require().NotNil(job.Result)
+	s.Equal(entities.RunFailed, job.Result.Status)
+}
+
+func (s *ReportJobWorkerSuite) TestProcessNotifyError() {
+	finished := s.expectJob(entities.JobFailed)
+	s.mockReportService.EXPECT().GenerateReport(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(dto.ReportResponse{}, nil)
+	s.mockNotification.EXPECT().Notify(gomock.Any(), "email", "ops@example.com", gomock.Any()).Return(errors.New("smtp unavailable"))
+	s.mockLogger.EXPECT().Error("report job failed", gomock.Any(), gomock.Any()).Times(1)
+
+	s.worker.process(context.Background(), s.job)
+
+	job := <-finished
+	s.Equal("smtp unavailable", job.Error)
+	s.Equal([]string{"ops@example.com"}, job.Result.FailedRecipients)
+}
+
+func (s *ReportJobWorkerSuite) TestProcessSaveError() {
+	s.mockJobService.EXPECT().Save(gomock.Any(), gomock.Any()).Return(errors.New("redis connection failed")).Times(2)
+	s.mockRunService.EXPECT().Start(gomock.Any(), gomock.Any(), nil).Return(entities.ReportRun{Id: "run-1"}, nil)
+	s.mockRunService.EXPECT().Finish(gomock.Any(), gomock.Any(), gomock.Any(), nil).Return(entities.ReportRun{Id: "run-1"}, nil)
+	s.mockReportService.EXPECT().GenerateReport(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(dto.ReportResponse{}, nil)
+	s.mockNotification.EXPECT().Notify(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	s.mockLogger.EXPECT().Warn("failed to update report job", gomock.Any(), gomock.Any()).Times(2)
+	s.mockLogger.EXPECT().Info("report job completed successfully", gomock.Any(), gomock.Any()).Times(1)
+
+	s.worker.process(context.Background(), s.job)
+}
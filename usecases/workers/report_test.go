@@ -1,18 +1,29 @@
 package workers
 
 import (
+	"context"
 	"errors"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/alicebob/miniredis/v2"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
+	"github.com/redis/go-redis/v9"
+	"github.com/robfig/cron/v3"
 	"github.com/stretchr/testify/suite"
 	"github.com/vnFuhung2903/vcs-report-service/dto"
 	"github.com/vnFuhung2903/vcs-report-service/entities"
+	"github.com/vnFuhung2903/vcs-report-service/interfaces"
+	mockInterfaces "github.com/vnFuhung2903/vcs-report-service/mocks/interfaces"
 	"github.com/vnFuhung2903/vcs-report-service/mocks/logger"
 	"github.com/vnFuhung2903/vcs-report-service/mocks/middlewares"
+	"github.com/vnFuhung2903/vcs-report-service/mocks/notifiers"
 	"github.com/vnFuhung2903/vcs-report-service/mocks/services"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/env"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/retry"
+	"github.com/vnFuhung2903/vcs-report-service/pkg/timerange"
 )
 
 type ReportHandlerSuite struct {
@@ -20,15 +31,19 @@ type ReportHandlerSuite struct {
 	ctrl              *gomock.Controller
 	reportWorker      IReportkWorker
 	mockReportService *services.MockIReportService
+	mockNotification  *notifiers.MockINotificationService
 	mockJWTMiddleware *middlewares.MockIJWTMiddleware
 	mockLogger        *logger.MockILogger
+	reportEnv         env.ReportEnv
 }
 
 func (s *ReportHandlerSuite) SetupTest() {
 	s.ctrl = gomock.NewController(s.T())
 	s.mockReportService = services.NewMockIReportService(s.ctrl)
+	s.mockNotification = notifiers.NewMockINotificationService(s.ctrl)
 	s.mockJWTMiddleware = middlewares.NewMockIJWTMiddleware(s.ctrl)
 	s.mockLogger = logger.NewMockILogger(s.ctrl)
+	s.reportEnv = env.ReportEnv{SubscriptionRefresh: time.Minute, LockTTL: time.Minute}
 
 	s.mockJWTMiddleware.EXPECT().
 		RequireScope("report:mail").
@@ -37,7 +52,13 @@ func (s *ReportHandlerSuite) SetupTest() {
 		}).
 		AnyTimes()
 
-	s.reportWorker = NewReportkWorker(s.mockReportService, "test@example.com", s.mockLogger, 2*time.Second)
+	s.reportWorker = NewReportkWorker(s.mockReportService, nil, nil, nil, s.mockNotification, nil, s.mockLogger, []ReportSchedule{{
+		Name:       "daily",
+		Cron:       cron.Every(2 * time.Second),
+		Window:     WindowSchedule,
+		Channel:    "email",
+		Recipients: []string{"test@example.com"},
+	}}, s.reportEnv)
 }
 
 func (s *ReportHandlerSuite) TearDownTest() {
@@ -48,43 +69,18 @@ func TestReportHandlerSuite(t *testing.T) {
 	suite.Run(t, new(ReportHandlerSuite))
 }
 
-func (s *ReportHandlerSuite) TestSendEmail() {
-	baseTime := time.Now()
-
-	statusList := map[string][]dto.EsStatus{
-		"container1": {
-			{ContainerId: "container1", Status: entities.ContainerOn, Uptime: int64(3600), LastUpdated: baseTime.Add(-210 * time.Minute)},
-			{ContainerId: "container1", Status: entities.ContainerOff, Uptime: int64(1800), LastUpdated: baseTime.Add(-3 * time.Hour)},
-			{ContainerId: "container1", Status: entities.ContainerOn, Uptime: int64(3600), LastUpdated: baseTime.Add(-2 * time.Hour)},
-		},
-		"container2": {
-			{ContainerId: "container2", Status: entities.ContainerOff, Uptime: int64(7200), LastUpdated: baseTime.Add(-1 * time.Minute)},
-		},
-	}
-
-	overlapStatusList := map[string][]dto.EsStatus{
-		"container1": {},
-		"container2": {},
-	}
-
-	s.mockReportService.EXPECT().
-		GetEsStatus(gomock.Any(), 10000, gomock.Any(), gomock.Any(), dto.Asc).
-		Return(statusList, nil)
-
-	s.mockReportService.EXPECT().
-		GetEsStatus(gomock.Any(), 1, gomock.Any(), gomock.Any(), dto.Asc).
-		Return(overlapStatusList, nil)
-
+func (s *ReportHandlerSuite) TestSendReport() {
+	report := dto.ReportResponse{ContainerCount: 2, ContainerOnCount: 1, ContainerOffCount: 1, TotalUptime: 50.0}
 	s.mockReportService.EXPECT().
-		CalculateReportStatistic(statusList, overlapStatusList, gomock.Any(), gomock.Any()).
-		Return(1, 1, 50.0)
+		GenerateReport(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(report, nil)
 
-	s.mockReportService.EXPECT().
-		SendEmail(gomock.Any(), "test@example.com", 2, 1, 1, 50.0, gomock.Any(), gomock.Any()).
+	s.mockNotification.EXPECT().
+		Notify(gomock.Any(), "email", "test@example.com", report).
 		Return(nil)
 
-	s.mockLogger.EXPECT().Info("daily report sent successfully", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
-	s.mockLogger.EXPECT().Info("daily report workers stopped").AnyTimes()
+	s.mockLogger.EXPECT().Info("scheduled report sent successfully", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	s.mockLogger.EXPECT().Info("report worker stopped", gomock.Any()).AnyTimes()
 
 	s.reportWorker.Start()
 	time.Sleep(3 * time.Second)
@@ -92,13 +88,13 @@ func (s *ReportHandlerSuite) TestSendEmail() {
 	s.reportWorker.Stop()
 }
 
-func (s *ReportHandlerSuite) TestSendEmailGetEsStatusError() {
+func (s *ReportHandlerSuite) TestSendReportGenerateReportError() {
 	s.mockReportService.EXPECT().
-		GetEsStatus(gomock.Any(), 10000, gomock.Any(), gomock.Any(), dto.Asc).
-		Return(map[string][]dto.EsStatus{}, errors.New("elasticsearch error"))
+		GenerateReport(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(dto.ReportResponse{}, errors.New("elasticsearch error"))
 
-	s.mockLogger.EXPECT().Error("failed to retrieve elasticsearch status", gomock.Any()).AnyTimes()
-	s.mockLogger.EXPECT().Info("daily report workers stopped").AnyTimes()
+	s.mockLogger.EXPECT().Error("failed to generate scheduled report", gomock.Any(), gomock.Any()).AnyTimes()
+	s.mockLogger.EXPECT().Info("report worker stopped", gomock.Any()).AnyTimes()
 
 	s.reportWorker.Start()
 	time.Sleep(3 * time.Second)
@@ -106,26 +102,18 @@ func (s *ReportHandlerSuite) TestSendEmailGetEsStatusError() {
 	s.reportWorker.Stop()
 }
 
-func (s *ReportHandlerSuite) TestSendEmailGetEsStatusOverlapError() {
-	baseTime := time.Now()
-	statusList := map[string][]dto.EsStatus{
-		"container1": {
-			{ContainerId: "container1", Status: entities.ContainerOn, Uptime: int64(3600), LastUpdated: baseTime.Add(-210 * time.Minute)},
-			{ContainerId: "container1", Status: entities.ContainerOff, Uptime: int64(1800), LastUpdated: baseTime.Add(-3 * time.Hour)},
-			{ContainerId: "container1", Status: entities.ContainerOn, Uptime: int64(3600), LastUpdated: baseTime.Add(-2 * time.Hour)},
-		},
-	}
-
+func (s *ReportHandlerSuite) TestSendReportNotifyError() {
+	report := dto.ReportResponse{ContainerCount: 1, ContainerOnCount: 1, ContainerOffCount: 0, TotalUptime: 100.0}
 	s.mockReportService.EXPECT().
-		GetEsStatus(gomock.Any(), 10000, gomock.Any(), gomock.Any(), dto.Asc).
-		Return(statusList, nil)
+		GenerateReport(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(report, nil)
 
-	s.mockReportService.EXPECT().
-		GetEsStatus(gomock.Any(), 1, gomock.Any(), gomock.Any(), dto.Asc).
-		Return(map[string][]dto.EsStatus{}, errors.New("elasticsearch error"))
+	s.mockNotification.EXPECT().
+		Notify(gomock.Any(), "email", "test@example.com", report).
+		Return(errors.New("service error"))
 
-	s.mockLogger.EXPECT().Error("failed to retrieve elasticsearch status", gomock.Any()).AnyTimes()
-	s.mockLogger.EXPECT().Info("daily report workers stopped").AnyTimes()
+	s.mockLogger.EXPECT().Error("failed to send scheduled report", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	s.mockLogger.EXPECT().Info("report worker stopped", gomock.Any()).AnyTimes()
 
 	s.reportWorker.Start()
 	time.Sleep(3 * time.Second)
@@ -133,41 +121,531 @@ func (s *ReportHandlerSuite) TestSendEmailGetEsStatusOverlapError() {
 	s.reportWorker.Stop()
 }
 
-func (s *ReportHandlerSuite) TestSendEmailSendEmailServiceError() {
-	baseTime := time.Now()
-	statusList := map[string][]dto.EsStatus{
-		"container1": {
-			{ContainerId: "container1", Status: entities.ContainerOn, Uptime: int64(3600), LastUpdated: baseTime.Add(-210 * time.Minute)},
-			{ContainerId: "container1", Status: entities.ContainerOff, Uptime: int64(1800), LastUpdated: baseTime.Add(-3 * time.Hour)},
-			{ContainerId: "container1", Status: entities.ContainerOn, Uptime: int64(3600), LastUpdated: baseTime.Add(-2 * time.Hour)},
+func (s *ReportHandlerSuite) TestMultipleSchedules() {
+	report := dto.ReportResponse{ContainerCount: 1, ContainerOnCount: 1}
+	s.mockReportService.EXPECT().
+		GenerateReport(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(report, nil).
+		Times(2)
+
+	s.mockNotification.EXPECT().
+		Notify(gomock.Any(), "email", "ops@example.com", report).
+		Return(errors.New("mailbox unavailable"))
+	s.mockNotification.EXPECT().
+		Notify(gomock.Any(), "email", "dev@example.com", report).
+		Return(nil)
+	s.mockNotification.EXPECT().
+		Notify(gomock.Any(), "slack", "https://hooks.slack.com/services/T000/B000/XXX", report).
+		Return(nil)
+
+	s.mockLogger.EXPECT().Error("failed to send scheduled report", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	s.mockLogger.EXPECT().Info("scheduled report sent successfully", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(2)
+	s.mockLogger.EXPECT().Info("report worker stopped", gomock.Any()).Times(2)
+
+	reportWorker := NewReportkWorker(s.mockReportService, nil, nil, nil, s.mockNotification, nil, s.mockLogger, []ReportSchedule{
+		{
+			Name:       "team-mail",
+			Cron:       cron.Every(2 * time.Second),
+			Window:     "1h",
+			Channel:    "email",
+			Recipients: []string{"ops@example.com", "dev@example.com"},
+		},
+		{
+			Name:       "team-chat",
+			Cron:       cron.Every(2 * time.Second),
+			Window:     WindowSchedule,
+			Channel:    "slack",
+			Recipients: []string{"https://hooks.slack.com/services/T000/B000/XXX"},
 		},
+	}, s.reportEnv)
+
+	reportWorker.Start()
+	time.Sleep(3 * time.Second)
+
+	reportWorker.Stop()
+}
+
+func (s *ReportHandlerSuite) TestSyncSubscriptions() {
+	mockSubscriptionService := services.NewMockISubscriptionService(s.ctrl)
+	subscription := entities.ReportSubscription{
+		Id:         "sub-1",
+		Owner:      "user-1",
+		Name:       "digest",
+		Schedule:   "0 8 * * *",
+		Timezone:   "UTC",
+		Window:     WindowDay,
+		Channel:    "email",
+		Recipients: []string{"user@example.com"},
+		UpdatedAt:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
 	}
+	invalid := entities.ReportSubscription{Id: "sub-2", Schedule: "never", Timezone: "UTC"}
+	updated := subscription
+	updated.UpdatedAt = updated.UpdatedAt.Add(time.Hour)
+
+	gomock.InOrder(
+		mockSubscriptionService.EXPECT().ListAll(gomock.Any()).Return([]entities.ReportSubscription{subscription, invalid}, nil),
+		mockSubscriptionService.EXPECT().ListAll(gomock.Any()).Return([]entities.ReportSubscription{subscription}, nil),
+		mockSubscriptionService.EXPECT().ListAll(gomock.Any()).Return([]entities.ReportSubscription{updated}, nil),
+		mockSubscriptionService.EXPECT().ListAll(gomock.Any()).Return(nil, errors.New("redis connection failed")),
+		mockSubscriptionService.EXPECT().ListAll(gomock.Any()).Return([]entities.ReportSubscription{}, nil),
+	)
+	s.mockLogger.EXPECT().Warn("skipping invalid report subscription", gomock.Any(), gomock.Any()).Times(1)
+	s.mockLogger.EXPECT().Error("failed to refresh report subscriptions", gomock.Any()).Times(1)
+	s.mockLogger.EXPECT().Info("report worker stopped", gomock.Any()).Times(2)
+
+	worker := NewReportkWorker(s.mockReportService, mockSubscriptionService, nil, nil, s.mockNotification, nil, s.mockLogger, nil, s.reportEnv).(*reportkWorker)
+
+	worker.syncSubscriptions()
+	s.Len(worker.subscriptions, 1)
+	s.Contains(worker.subscriptions, "sub-1")
+
+	worker.syncSubscriptions()
+	s.Equal(subscription.UpdatedAt, worker.subscriptions["sub-1"].updatedAt)
+
+	worker.syncSubscriptions()
+	s.Equal(updated.UpdatedAt, worker.subscriptions["sub-1"].updatedAt)
+
+	worker.syncSubscriptions()
+	s.Len(worker.subscriptions, 1)
 
-	overlapStatusList := map[string][]dto.EsStatus{
-		"container1": {},
+	worker.syncSubscriptions()
+	s.Empty(worker.subscriptions)
+
+	worker.Stop()
+}
+
+func (s *ReportHandlerSuite) TestReportSingleInstancePerWindow() {
+	miniRedis, err := miniredis.Run()
+	s.Require().NoError(err)
+	defer miniRedis.Close()
+	redisClient := redis.NewClient(&redis.Options{Addr: miniRedis.Addr()})
+	defer redisClient.Close()
+
+	schedule := ReportSchedule{
+		Name:       "daily",
+		Cron:       cron.Every(time.Hour),
+		Location:   time.UTC,
+		Window:     WindowDay,
+		Channel:    "email",
+		Recipients: []string{"test@example.com"},
 	}
+	firedAt := time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
+	report := dto.ReportResponse{ContainerCount: 1, ContainerOnCount: 1}
 
 	s.mockReportService.EXPECT().
-		GetEsStatus(gomock.Any(), 10000, gomock.Any(), gomock.Any(), dto.Asc).
-		Return(statusList, nil)
+		GenerateReport(gomock.Any(), time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC), firedAt, gomock.Any()).
+		Return(report, nil).
+		Times(1)
+	s.mockNotification.EXPECT().
+		Notify(gomock.Any(), "email", "test@example.com", report).
+		Return(nil).
+		Times(1)
+	s.mockLogger.EXPECT().Info("scheduled report sent successfully", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	s.mockLogger.EXPECT().Debug("scheduled report claimed by another instance", gomock.Any(), gomock.Any()).Times(2)
+
+	var wg sync.WaitGroup
+	for range 3 {
+		worker := NewReportkWorker(s.mockReportService, nil, nil, nil, s.mockNotification, interfaces.NewRedisLock(redisClient), s.mockLogger, nil, s.reportEnv).(*reportkWorker)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker.report(context.Background(), schedule, firedAt, entities.RunTriggerSchedule)
+		}()
+	}
+	wg.Wait()
+
+	s.True(miniRedis.Exists("report_lock:daily:1704672000"))
+}
+
+func (s *ReportHandlerSuite) TestReportAcquireLeaseError() {
+	mockLock := mockInterfaces.NewMockIRedisLock(s.ctrl)
+	mockLock.EXPECT().Acquire(gomock.Any(), "report_lock:daily:1704672000", time.Minute).Return(nil, errors.New("redis connection failed"))
+	s.mockLogger.EXPECT().Error("failed to acquire report lease", gomock.Any(), gomock.Any()).Times(1)
 
+	worker := NewReportkWorker(s.mockReportService, nil, nil, nil, s.mockNotification, mockLock, s.mockLogger, nil, s.reportEnv).(*reportkWorker)
+	worker.report(context.Background(), ReportSchedule{Name: "daily", Location: time.UTC, Window: "1h"}, time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), entities.RunTriggerSchedule)
+}
+
+func (s *ReportHandlerSuite) TestReportReleasesLeaseOnFailure() {
+	mockLock := mockInterfaces.NewMockIRedisLock(s.ctrl)
+	lease := &interfaces.Lease{Key: "report_lock:daily:1704672000", Token: 7}
+	mockLock.EXPECT().Acquire(gomock.Any(), lease.Key, time.Minute).Return(lease, nil)
+	mockLock.EXPECT().Release(gomock.Any(), lease).Return(nil)
 	s.mockReportService.EXPECT().
-		GetEsStatus(gomock.Any(), 1, gomock.Any(), gomock.Any(), dto.Asc).
-		Return(overlapStatusList, nil)
+		GenerateReport(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(dto.ReportResponse{}, errors.New("elasticsearch error"))
+	s.mockLogger.EXPECT().Error("failed to generate scheduled report", gomock.Any(), gomock.Any()).Times(1)
 
+	worker := NewReportkWorker(s.mockReportService, nil, nil, nil, s.mockNotification, mockLock, s.mockLogger, nil, s.reportEnv).(*reportkWorker)
+	worker.report(context.Background(), ReportSchedule{Name: "daily", Location: time.UTC, Window: "1h"}, time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), entities.RunTriggerSchedule)
+}
+
+func (s *ReportHandlerSuite) TestReportRecordsRunUnderLease() {
+	mockLock := mockInterfaces.NewMockIRedisLock(s.ctrl)
+	mockRunService := services.NewMockIReportRunService(s.ctrl)
+	lease := &interfaces.Lease{Key: "report_lock:daily:1704672000", Token: 7}
+	firedAt := time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
+	report := dto.ReportResponse{ContainerCount: 1, ContainerOnCount: 1}
+
+	mockLock.EXPECT().Acquire(gomock.Any(), lease.Key, time.Minute).Return(lease, nil)
+	mockRunService.EXPECT().Start(gomock.Any(), gomock.Any(), lease).Return(entities.ReportRun{Id: "run-1"}, nil)
+	s.mockReportService.EXPECT().GenerateReport(gomock.Any(), gomock.Any(), firedAt, gomock.Any()).Return(report, nil)
+	s.mockNotification.EXPECT().Notify(gomock.Any(), "email", "ops@example.com", report).Return(nil)
+	s.mockLogger.EXPECT().Info("scheduled report sent successfully", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	mockRunService.EXPECT().Finish(gomock.Any(), gomock.Any(), &report, lease).Return(entities.ReportRun{Id: "run-1"}, nil)
+	mockRunService.EXPECT().MarkReported(gomock.Any(), "daily", firedAt, lease).Return(interfaces.ErrLeaseLost)
+	s.mockLogger.EXPECT().Warn("failed to record reported window", gomock.Any(), gomock.Any()).Times(1)
+
+	worker := NewReportkWorker(s.mockReportService, nil, mockRunService, nil, s.mockNotification, mockLock, s.mockLogger, nil, s.reportEnv).(*reportkWorker)
+	worker.report(context.Background(), ReportSchedule{
+		Name:       "daily",
+		Location:   time.UTC,
+		Window:     "1h",
+		Channel:    "email",
+		Recipients: []string{"ops@example.com"},
+	}, firedAt, entities.RunTriggerSchedule)
+}
+
+func (s *ReportHandlerSuite) TestReportLeaseLost() {
+	mockLock := mockInterfaces.NewMockIRedisLock(s.ctrl)
+	lease := &interfaces.Lease{Key: "report_lock:daily:1704672000", Token: 7}
+	report := dto.ReportResponse{ContainerCount: 1, ContainerOnCount: 1}
+	mockLock.EXPECT().Acquire(gomock.Any(), lease.Key, 30*time.Millisecond).Return(lease, nil)
+	mockLock.EXPECT().Renew(gomock.Any(), lease, 30*time.Millisecond).Return(interfaces.ErrLeaseLost)
 	s.mockReportService.EXPECT().
-		CalculateReportStatistic(statusList, overlapStatusList, gomock.Any(), gomock.Any()).
-		Return(1, 0, 100.0)
+		GenerateReport(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(report, nil)
+	s.mockNotification.EXPECT().
+		Notify(gomock.Any(), "email", "ops@example.com", report).
+		DoAndReturn(func(ctx context.Context, channel, target string, report dto.ReportResponse) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	s.mockLogger.EXPECT().Error("failed to renew report lease", gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	s.mockLogger.EXPECT().Error("failed to send scheduled report", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	s.mockLogger.EXPECT().Warn("scheduled report aborted", gomock.Any(), gomock.Any()).Times(1)
+	mockLock.EXPECT().Release(gomock.Any(), lease).Return(interfaces.ErrLeaseLost)
+	s.mockLogger.EXPECT().Warn("failed to release report lease", gomock.Any(), gomock.Any()).Times(1)
+
+	worker := NewReportkWorker(s.mockReportService, nil, nil, nil, s.mockNotification, mockLock, s.mockLogger, nil, env.ReportEnv{SubscriptionRefresh: time.Minute, LockTTL: 30 * time.Millisecond}).(*reportkWorker)
+	worker.report(context.Background(), ReportSchedule{
+		Name:       "daily",
+		Location:   time.UTC,
+		Window:     "1h",
+		Channel:    "email",
+		Recipients: []string{"ops@example.com", "dev@example.com"},
+	}, time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), entities.RunTriggerSchedule)
+}
+
+func (s *ReportHandlerSuite) TestReportRecordsRun() {
+	mockRunService := services.NewMockIReportRunService(s.ctrl)
+	report := dto.ReportResponse{ContainerCount: 1, ContainerOnCount: 1}
+	startTime := time.Date(2024, 1, 7, 23, 0, 0, 0, time.UTC)
+	endTime := time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
+
+	mockRunService.EXPECT().
+		Start(gomock.Any(), entities.ReportRun{
+			Trigger:    entities.RunTriggerSchedule,
+			Owner:      "user-1",
+			Schedule:   "hourly",
+			Channel:    "email",
+			Recipients: []string{"ops@example.com", "dev@example.com"},
+			StartTime:  startTime,
+			EndTime:    endTime,
+		}, nil).
+		DoAndReturn(func(ctx context.Context, run entities.ReportRun, lease *interfaces.Lease) (entities.ReportRun, error) {
+			run.Id = "run-1"
+			run.Status = entities.RunRunning
+			return run, nil
+		})
+	mockRunService.EXPECT().
+		Finish(gomock.Any(), gomock.Any(), &report, nil).
+		DoAndReturn(func(ctx context.Context, run entities.ReportRun, report *dto.ReportResponse, lease *interfaces.Lease) (entities.ReportRun, error) {
+			s.Equal("run-1", run.Id)
+			s.Equal([]string{"ops@example.com"}, run.FailedRecipients)
+			s.Equal("mailbox unavailable", run.Error)
+			return run, errors.New("redis connection failed")
+		})
+	s.mockReportService.EXPECT().GenerateReport(gomock.Any(), startTime, endTime, gomock.Any()).Return(report, nil)
+	s.mockNotification.EXPECT().Notify(gomock.Any(), "email", "ops@example.com", report).Return(errors.New("mailbox unavailable"))
+	s.mockNotification.EXPECT().Notify(gomock.Any(), "email", "dev@example.com", report).Return(nil)
+	s.mockLogger.EXPECT().Error("failed to send scheduled report", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	s.mockLogger.EXPECT().Info("scheduled report sent successfully", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	s.mockLogger.EXPECT().Warn("failed to record report run", gomock.Any(), gomock.Any()).Times(1)
+	mockRunService.EXPECT().MarkReported(gomock.Any(), "hourly", endTime, nil).Return(nil)
+
+	worker := NewReportkWorker(s.mockReportService, nil, mockRunService, nil, s.mockNotification, nil, s.mockLogger, nil, s.reportEnv).(*reportkWorker)
+	worker.report(context.Background(), ReportSchedule{
+		Name:       "hourly",
+		Owner:      "user-1",
+		Location:   time.UTC,
+		Window:     "1h",
+		Channel:    "email",
+		Recipients: []string{"ops@example.com", "dev@example.com"},
+	}, endTime, entities.RunTriggerSchedule)
+}
 
+func (s *ReportHandlerSuite) TestReportRecordsFailedRun() {
+	mockRunService := services.NewMockIReportRunService(s.ctrl)
+	mockRunService.EXPECT().
+		Start(gomock.Any(), gomock.Any(), nil).
+		DoAndReturn(func(ctx context.Context, run entities.ReportRun, lease *interfaces.Lease) (entities.ReportRun, error) {
+			run.Id = "run-1"
+			return run, nil
+		})
+	mockRunService.EXPECT().
+		Finish(gomock.Any(), gomock.Any(), nil, nil).
+		DoAndReturn(func(ctx context.Context, run entities.ReportRun, report *dto.ReportResponse, lease *interfaces.Lease) (entities.ReportRun, error) {
+			s.Equal("elasticsearch error", run.Error)
+			return run, nil
+		})
 	s.mockReportService.EXPECT().
-		SendEmail(gomock.Any(), "test@example.com", 1, 1, 0, 100.0, gomock.Any(), gomock.Any()).
-		Return(errors.New("service error"))
+		GenerateReport(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(dto.ReportResponse{}, errors.New("elasticsearch error"))
+	s.mockLogger.EXPECT().Error("failed to generate scheduled report", gomock.Any(), gomock.Any()).Times(1)
 
-	s.mockLogger.EXPECT().Error("failed to email daily report", gomock.Any()).AnyTimes()
-	s.mockLogger.EXPECT().Info("daily report workers stopped").AnyTimes()
+	worker := NewReportkWorker(s.mockReportService, nil, mockRunService, nil, s.mockNotification, nil, s.mockLogger, nil, s.reportEnv).(*reportkWorker)
+	worker.report(context.Background(), ReportSchedule{Name: "hourly", Location: time.UTC, Window: "1h"}, time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), entities.RunTriggerSchedule)
+}
 
-	s.reportWorker.Start()
-	time.Sleep(3 * time.Second)
+func (s *ReportHandlerSuite) TestReportRecordsUnresolvableWindow() {
+	firedAt := time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
+	mockRunService := services.NewMockIReportRunService(s.ctrl)
+	mockRunService.EXPECT().
+		Start(gomock.Any(), gomock.Any(), nil).
+		DoAndReturn(func(ctx context.Context, run entities.ReportRun, lease *interfaces.Lease) (entities.ReportRun, error) {
+			run.Id = "run-1"
+			return run, nil
+		})
+	mockRunService.EXPECT().
+		Finish(gomock.Any(), gomock.Any(), nil, nil).
+		DoAndReturn(func(ctx context.Context, run entities.ReportRun, report *dto.ReportResponse, lease *interfaces.Lease) (entities.ReportRun, error) {
+			s.Equal("run-1", run.Id)
+			s.Contains(run.Error, "does not start before it ends")
+			return run, nil
+		})
+	s.mockLogger.EXPECT().Error("failed to resolve scheduled report window", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+
+	worker := NewReportkWorker(s.mockReportService, nil, mockRunService, nil, s.mockNotification, nil, s.mockLogger, nil, s.reportEnv).(*reportkWorker)
+	worker.report(context.Background(), ReportSchedule{Name: "today", Location: time.UTC, Window: "now/d..now"}, firedAt, entities.RunTriggerSchedule)
+
+	_, _, err := ReportSchedule{Location: time.UTC, Window: "now/d..now"}.ReportWindow(firedAt)
+	s.ErrorIs(err, timerange.ErrInvalidExpression)
+}
 
-	s.reportWorker.Stop()
+func (s *ReportHandlerSuite) TestReportParksFailedDeliveries() {
+	mockDeadLetterService := services.NewMockIDeadLetterService(s.ctrl)
+	report := dto.ReportResponse{ContainerCount: 1}
+	endTime := time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
+	schedule := ReportSchedule{
+		Name:       "hourly",
+		Location:   time.UTC,
+		Window:     "1h",
+		Channel:    "webhook",
+		Recipients: []string{"https://example.com/a", "https://example.com/b"},
+		Template:   "oncall",
+		Filter:     entities.ContainerFilter{Pattern: "api-"},
+	}
+	notified := dto.ReportResponse{ContainerCount: 1, Template: "oncall"}
+	exhausted := retry.Policy{Attempts: 3}.Do(context.Background(), func(ctx context.Context) error {
+		return errors.New("webhook request failed: 503 Service Unavailable")
+	})
+
+	s.mockReportService.EXPECT().GenerateReport(gomock.Any(), gomock.Any(), gomock.Any(), schedule.Filter).Return(report, nil)
+	s.mockNotification.EXPECT().Notify(gomock.Any(), "webhook", "https://example.com/a", notified).Return(exhausted)
+	s.mockNotification.EXPECT().Notify(gomock.Any(), "webhook", "https://example.com/b", notified).Return(nil)
+	mockDeadLetterService.EXPECT().
+		Park(gomock.Any(), entities.DeadLetter{
+			Schedule:  "hourly",
+			Channel:   "webhook",
+			Target:    "https://example.com/a",
+			Template:  "oncall",
+			Filter:    entities.ContainerFilter{Pattern: "api-"},
+			StartTime: endTime.Add(-time.Hour),
+			EndTime:   endTime,
+			Error:     "webhook request failed: 503 Service Unavailable",
+		}, 3).
+		Return(entities.DeadLetter{Id: "letter-1"}, nil)
+	s.mockLogger.EXPECT().Error("failed to send scheduled report", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	s.mockLogger.EXPECT().Info("scheduled report sent successfully", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+
+	worker := NewReportkWorker(s.mockReportService, nil, nil, mockDeadLetterService, s.mockNotification, nil, s.mockLogger, nil, s.reportEnv).(*reportkWorker)
+	worker.report(context.Background(), schedule, endTime, entities.RunTriggerSchedule)
+
+	s.mockReportService.EXPECT().GenerateReport(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(dto.ReportResponse{}, errors.New("elasticsearch error"))
+	mockDeadLetterService.EXPECT().Park(gomock.Any(), gomock.Any(), 1).Return(entities.DeadLetter{}, nil)
+	mockDeadLetterService.EXPECT().Park(gomock.Any(), gomock.Any(), 1).Return(entities.DeadLetter{}, errors.New("redis connection failed"))
+	s.mockLogger.EXPECT().Error("failed to generate scheduled report", gomock.Any(), gomock.Any()).Times(1)
+	s.mockLogger.EXPECT().Error("failed to park report delivery", gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+
+	worker.report(context.Background(), schedule, endTime.Add(time.Hour), entities.RunTriggerSchedule)
+}
+
+func (s *ReportHandlerSuite) TestCatchUp() {
+	mockRunService := services.NewMockIReportRunService(s.ctrl)
+	hourly, err := cron.ParseStandard("0 * * * *")
+	s.Require().NoError(err)
+	schedule := ReportSchedule{
+		Name:       "hourly",
+		Cron:       hourly,
+		Location:   time.UTC,
+		Window:     WindowSchedule,
+		Channel:    "email",
+		Recipients: []string{"ops@example.com"},
+	}
+	lastReported := time.Now().UTC().Truncate(time.Hour).Add(-5 * time.Hour)
+	report := dto.ReportResponse{ContainerCount: 1}
+
+	mockRunService.EXPECT().LastReported(gomock.Any(), "hourly").Return(lastReported, nil)
+	mockRunService.EXPECT().
+		Start(gomock.Any(), gomock.Any(), nil).
+		DoAndReturn(func(ctx context.Context, run entities.ReportRun, lease *interfaces.Lease) (entities.ReportRun, error) {
+			s.Equal(entities.RunTriggerCatchUp, run.Trigger)
+			return run, nil
+		}).
+		Times(2)
+	mockRunService.EXPECT().Finish(gomock.Any(), gomock.Any(), &report, nil).Return(entities.ReportRun{}, nil).Times(2)
+	gomock.InOrder(
+		s.mockReportService.EXPECT().GenerateReport(gomock.Any(), lastReported.Add(3*time.Hour), lastReported.Add(4*time.Hour), gomock.Any()).Return(report, nil),
+		s.mockReportService.EXPECT().GenerateReport(gomock.Any(), lastReported.Add(4*time.Hour), lastReported.Add(5*time.Hour), gomock.Any()).Return(report, nil),
+	)
+	s.mockNotification.EXPECT().Notify(gomock.Any(), "email", "ops@example.com", report).Return(nil).Times(2)
+	mockRunService.EXPECT().MarkReported(gomock.Any(), "hourly", lastReported.Add(4*time.Hour), nil).Return(nil)
+	mockRunService.EXPECT().MarkReported(gomock.Any(), "hourly", lastReported.Add(5*time.Hour), nil).Return(errors.New("redis connection failed"))
+
+	s.mockLogger.EXPECT().Warn("skipping missed report windows beyond catch-up limit", gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	s.mockLogger.EXPECT().Info("catching up missed report window", gomock.Any(), gomock.Any()).Times(2)
+	s.mockLogger.EXPECT().Info("scheduled report sent successfully", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(2)
+	s.mockLogger.EXPECT().Warn("failed to record reported window", gomock.Any(), gomock.Any()).Times(1)
+
+	reportEnv := s.reportEnv
+	reportEnv.MaxCatchUp = 2
+	worker := NewReportkWorker(s.mockReportService, nil, mockRunService, nil, s.mockNotification, nil, s.mockLogger, nil, reportEnv).(*reportkWorker)
+	worker.catchUp(context.Background(), schedule)
+}
+
+func (s *ReportHandlerSuite) TestCatchUpSkipped() {
+	mockRunService := services.NewMockIReportRunService(s.ctrl)
+	schedule := ReportSchedule{Name: "hourly", Cron: cron.Every(time.Hour), Location: time.UTC, Window: WindowSchedule}
+
+	reportEnv := s.reportEnv
+	reportEnv.MaxCatchUp = 3
+	worker := NewReportkWorker(s.mockReportService, nil, mockRunService, nil, s.mockNotification, nil, s.mockLogger, nil, reportEnv).(*reportkWorker)
+
+	mockRunService.EXPECT().LastReported(gomock.Any(), "hourly").Return(time.Time{}, nil)
+	worker.catchUp(context.Background(), schedule)
+
+	mockRunService.EXPECT().LastReported(gomock.Any(), "hourly").Return(time.Time{}, errors.New("redis connection failed"))
+	s.mockLogger.EXPECT().Error("failed to load last reported window", gomock.Any(), gomock.Any()).Times(1)
+	worker.catchUp(context.Background(), schedule)
+
+	mockRunService.EXPECT().LastReported(gomock.Any(), "hourly").Return(time.Now().Add(-30*time.Minute), nil)
+	worker.catchUp(context.Background(), schedule)
+
+	disabled := NewReportkWorker(s.mockReportService, nil, mockRunService, nil, s.mockNotification, nil, s.mockLogger, nil, s.reportEnv).(*reportkWorker)
+	disabled.catchUp(context.Background(), schedule)
+}
+
+func (s *ReportHandlerSuite) TestMissedRuns() {
+	location := time.FixedZone("ICT", 7*3600)
+	weekdays, err := cron.ParseStandard("0 8 * * MON-FRI")
+	s.Require().NoError(err)
+
+	reportSchedule := ReportSchedule{Cron: weekdays, Location: location, Window: WindowDay}
+	missed := reportSchedule.MissedRuns(time.Date(2024, 1, 4, 0, 0, 0, 0, location), time.Date(2024, 1, 9, 9, 0, 0, 0, location))
+	s.Equal([]time.Time{
+		time.Date(2024, 1, 5, 8, 0, 0, 0, location),
+		time.Date(2024, 1, 8, 8, 0, 0, 0, location),
+		time.Date(2024, 1, 9, 8, 0, 0, 0, location),
+	}, missed)
+
+	reportSchedule.Window = WindowSchedule
+	missed = reportSchedule.MissedRuns(time.Date(2024, 1, 8, 8, 0, 0, 0, location), time.Date(2024, 1, 9, 7, 0, 0, 0, location))
+	s.Empty(missed)
+
+	midnight, err := cron.ParseStandard("0 0 * * *")
+	s.Require().NoError(err)
+	reportSchedule = ReportSchedule{Cron: midnight, Location: location, Window: "now/d..now"}
+	missed = reportSchedule.MissedRuns(time.Date(2024, 1, 8, 8, 0, 0, 0, location), time.Date(2024, 1, 9, 7, 0, 0, 0, location))
+	s.Equal([]time.Time{time.Date(2024, 1, 9, 0, 0, 0, 0, location)}, missed)
+}
+
+func (s *ReportHandlerSuite) TestNewReportSchedule() {
+	schedule, err := NewReportSchedule(env.ReportScheduleEnv{
+		Name:       "weekdays",
+		Schedule:   "0 8 * * MON-FRI",
+		Timezone:   "UTC",
+		Window:     WindowDay,
+		Channel:    "teams",
+		Recipients: []string{"https://example.com/webhook"},
+	})
+	s.NoError(err)
+	s.Equal("weekdays", schedule.Name)
+	s.Equal(time.UTC, schedule.Location)
+	s.Equal(time.Date(2024, 1, 8, 8, 0, 0, 0, time.UTC), schedule.Cron.Next(time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC)))
+
+	_, err = NewReportSchedule(env.ReportScheduleEnv{Name: "broken", Schedule: "every day", Timezone: "UTC"})
+	s.Error(err)
+
+	_, err = NewReportSchedule(env.ReportScheduleEnv{Name: "broken", Schedule: "0 8 * * *", Timezone: "Mars/Olympus_Mons"})
+	s.Error(err)
+}
+
+func (s *ReportHandlerSuite) TestReportWindowDuration() {
+	reportSchedule := ReportSchedule{Cron: cron.Every(time.Hour), Location: time.UTC, Window: "6h"}
+
+	startTime, endTime, err := reportSchedule.ReportWindow(time.Date(2024, 1, 8, 12, 0, 0, 0, time.UTC))
+	s.NoError(err)
+	s.Equal(time.Date(2024, 1, 8, 6, 0, 0, 0, time.UTC), startTime)
+	s.Equal(time.Date(2024, 1, 8, 12, 0, 0, 0, time.UTC), endTime)
+}
+
+func (s *ReportHandlerSuite) TestReportWindowDay() {
+	location := time.FixedZone("ICT", 7*3600)
+	schedule, err := cron.ParseStandard("0 8 * * MON-FRI")
+	s.Require().NoError(err)
+
+	reportSchedule := ReportSchedule{Cron: schedule, Location: location, Window: WindowDay}
+	startTime, endTime, err := reportSchedule.ReportWindow(time.Date(2024, 1, 8, 8, 0, 0, 0, location))
+	s.NoError(err)
+
+	s.Equal(time.Date(2024, 1, 7, 0, 0, 0, 0, location), startTime)
+	s.Equal(time.Date(2024, 1, 8, 0, 0, 0, 0, location), endTime)
+}
+
+func (s *ReportHandlerSuite) TestReportWindowRange() {
+	location := time.FixedZone("ICT", 7*3600)
+	schedule, err := cron.ParseStandard("0 8 * * MON")
+	s.Require().NoError(err)
+	firedAt := time.Date(2024, 1, 8, 8, 0, 0, 0, location)
+
+	reportSchedule := ReportSchedule{Cron: schedule, Location: location, Window: "last_week"}
+	startTime, endTime, err := reportSchedule.ReportWindow(firedAt)
+	s.NoError(err)
+	s.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, location), startTime)
+	s.Equal(time.Date(2024, 1, 8, 0, 0, 0, 0, location), endTime)
+
+	reportSchedule.Window = "now-7d/d"
+	startTime, endTime, err = reportSchedule.ReportWindow(firedAt)
+	s.NoError(err)
+	s.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, location), startTime)
+	s.Equal(firedAt, endTime)
+}
+
+func (s *ReportHandlerSuite) TestReportWindowSchedule() {
+	location := time.FixedZone("ICT", 7*3600)
+	schedule, err := cron.ParseStandard("0 8 * * MON-FRI")
+	s.Require().NoError(err)
+
+	reportSchedule := ReportSchedule{Cron: schedule, Location: location, Window: WindowSchedule}
+
+	startTime, endTime, err := reportSchedule.ReportWindow(time.Date(2024, 1, 8, 8, 0, 0, 0, location))
+	s.NoError(err)
+	s.Equal(time.Date(2024, 1, 5, 8, 0, 0, 0, location), startTime)
+	s.Equal(time.Date(2024, 1, 8, 8, 0, 0, 0, location), endTime)
+
+	startTime, endTime, err = reportSchedule.ReportWindow(time.Date(2024, 1, 9, 1, 0, 0, 0, time.UTC))
+	s.NoError(err)
+	s.Equal(time.Date(2024, 1, 8, 8, 0, 0, 0, location), startTime)
+	s.Equal(time.Date(2024, 1, 9, 8, 0, 0, 0, location), endTime)
 }